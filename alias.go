@@ -0,0 +1,107 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The "alias" tag option gives a field additional names, separated by "|", as
+// in `param:"limit,alias=per_page|page_size"`. This is useful when renaming a
+// parameter, since clients may go on using the old name for a while. Giving a
+// field values under more than one of its names is an error.
+func aliases(opts tagOptions) []string {
+	a, ok := opts["alias"]
+	if !ok {
+		return nil
+	}
+	return strings.Split(a, "|")
+}
+
+// Make sure none of the aliases of the fields in the given struct's cache are
+// empty or already taken by another name.
+func checkAliases(s reflect.Type, sc structCache) {
+	taken := make(map[string]bool, len(sc))
+	for name := range sc {
+		taken[name] = true
+	}
+	for _, l := range sc {
+		for _, alias := range l.aliases {
+			sf := s.Field(l.offset)
+			if alias == "" || alias == "-" {
+				pebkac("struct %v field %q has invalid alias %q.",
+					s, sf.Name, alias)
+			}
+			if taken[alias] {
+				pebkac("struct %v field %q has alias %q, which is "+
+					"already taken.", s, sf.Name, alias)
+			}
+			taken[alias] = true
+		}
+	}
+}
+
+// Looks up the field with the given name, which may be an alias, returning the
+// field's canonical name.
+func (sc structCache) lookup(name string) (string, cacheLine, bool) {
+	if l, ok := sc[name]; ok {
+		return name, l, true
+	}
+	// Aliases are rare, and unknown names rarer still, so we don't
+	// bother indexing them.
+	for canonical, l := range sc {
+		for _, alias := range l.aliases {
+			if alias == name {
+				return canonical, l, true
+			}
+		}
+	}
+	return "", cacheLine{}, false
+}
+
+// Panics with a ConflictError if the field with the given canonical name was
+// given values under more than one of its names. prefix is the key of the
+// field's struct, as for subkey. Each field is only checked once per parse,
+// against an index of the keys of params built the first time it's needed, so
+// that many keys for the same field don't each have to look at all of them.
+func (p *parser) checkAliasConflict(prefix, name string, l cacheLine) {
+	key := subkey(prefix, name)
+	if p.aliasChecked[key] {
+		return
+	}
+	if p.aliasChecked == nil {
+		p.aliasChecked = make(map[string]bool)
+		p.heads = keyHeads(p.params)
+	}
+	p.aliasChecked[key] = true
+
+	var keys []string
+	for _, n := range append([]string{name}, l.aliases...) {
+		if k := subkey(prefix, n); p.heads[k] {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) > 1 {
+		sort.Strings(keys)
+		panic(ConflictError{
+			Key:  key,
+			Keys: keys,
+		})
+	}
+}
+
+// Returns the set of the given keys and of the parts of them that precede each
+// of their opening brackets: "a[b][c]" gives "a", "a[b]", and "a[b][c]".
+func keyHeads(params url.Values) map[string]bool {
+	heads := make(map[string]bool, len(params))
+	for key := range params {
+		heads[key] = true
+		for i := 0; i < len(key); i++ {
+			if key[i] == '[' {
+				heads[key[:i]] = true
+			}
+		}
+	}
+	return heads
+}
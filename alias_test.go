@@ -0,0 +1,76 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+type Paging struct {
+	Limit  int      `param:"limit,alias=per_page|page_size,default=25"`
+	Filter Renamed  `param:"filter,alias=f"`
+	Sort   []string `param:"sort,required,alias=order"`
+}
+
+type Renamed struct {
+	Name string `param:"name,alias=title"`
+}
+
+func TestAlias(t *testing.T) {
+	t.Parallel()
+
+	for _, params := range []url.Values{
+		{"limit": {"10"}, "filter[name]": {"x"}, "sort[]": {"a"}},
+		{"per_page": {"10"}, "f[title]": {"x"}, "order[]": {"a"}},
+		{"page_size": {"10"}, "filter[title]": {"x"}, "sort[]": {"a"}},
+	} {
+		p := Paging{}
+		err := Parse(params, &p)
+		if err != nil {
+			t.Fatalf("Parse error for %v: %v", params, err)
+		}
+		assertEqual(t, "p", Paging{
+			Limit:  10,
+			Filter: Renamed{Name: "x"},
+			Sort:   []string{"a"},
+		}, p)
+	}
+
+	p := Paging{}
+	err := Parse(url.Values{"order[]": {"a"}}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Limit", 25, p.Limit)
+}
+
+func TestAliasConflict(t *testing.T) {
+	t.Parallel()
+
+	for key, params := range map[string]url.Values{
+		"limit": {
+			"limit": {"10"}, "per_page": {"20"}, "sort[]": {"a"},
+		},
+		"filter[name]": {
+			"filter[name]": {"x"}, "filter[title]": {"y"},
+			"sort[]": {"a"},
+		},
+		"filter": {
+			"filter[name]": {"x"}, "f[name]": {"y"}, "sort[]": {"a"},
+		},
+		"f[name]": {
+			"f[name]": {"x"}, "f[title]": {"y"}, "sort[]": {"a"},
+		},
+	} {
+		p := Paging{}
+		err := Parse(params, &p)
+		var cerr ConflictError
+		if !errors.As(err, &cerr) {
+			t.Errorf("Expected ConflictError for %q, got %v", key,
+				err)
+			continue
+		}
+		assertEqual(t, "cerr.Key", key, cerr.Key)
+		assertEqual(t, "len(cerr.Keys)", 2, len(cerr.Keys))
+	}
+}
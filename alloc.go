@@ -0,0 +1,60 @@
+package param
+
+import "reflect"
+
+// WithMaxAlloc returns an Option that limits how much memory a single call to
+// Parse may allocate for the slices, maps, pointers, strings, and byte slices it
+// sets, to roughly n bytes. A parse that would exceed the limit is abandoned
+// with a LimitError, before the allocation that would exceed it is made.
+//
+// The accounting is approximate: it counts the sizes of the values allocated
+// (including the contents of strings), but not the overhead of the allocator
+// or of maps, nor anything allocated by TextUnmarshalers and the like.
+func WithMaxAlloc(n int) Option {
+	return func(d *Decoder) {
+		d.maxAlloc = n
+	}
+}
+
+// WithMaxNested returns an Option that limits how many values a single call to
+// Parse may allocate on its own initiative to hold nested values: the values of
+// nil pointers, nil maps, and new map entries. A parse that would allocate more
+// than n of them is abandoned with a LimitError.
+//
+// Unlike WithMaxDepth, this bounds the work done for recursive types as a whole,
+// however the keys are spread out: "a[a][a][x]=1&b[b][b][x]=1" allocates six
+// pointers if a and b are pointers to the struct they're in.
+func WithMaxNested(n int) Option {
+	return func(d *Decoder) {
+		d.maxNested = n
+	}
+}
+
+// Record that a nested value is about to be allocated while parsing the given
+// key, complaining if that would exceed the Decoder's limit.
+func (p *parser) nest(key string) {
+	if p.maxNested <= 0 {
+		return
+	}
+	p.nested++
+	if p.nested > p.maxNested {
+		panic(LimitError{Key: key, Limit: "nested values", Max: p.maxNested})
+	}
+}
+
+// Record that n bytes are about to be allocated while parsing the given key,
+// complaining if that would exceed the Decoder's allocation limit.
+func (p *parser) alloc(key string, n int) {
+	if p.maxAlloc <= 0 {
+		return
+	}
+	p.allocated += n
+	if p.allocated > p.maxAlloc {
+		panic(LimitError{Key: key, Limit: "allocation", Max: p.maxAlloc})
+	}
+}
+
+// Record the allocation of n values of type t.
+func (p *parser) allocValues(key string, t reflect.Type, n int) {
+	p.alloc(key, int(t.Size())*n)
+}
@@ -0,0 +1,102 @@
+package param
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type Budgeted struct {
+	Name  string            `param:"name"`
+	IDs   []int             `param:"ids"`
+	Items []Sub             `param:"items"`
+	Tags  map[string]string `param:"tags"`
+}
+
+func TestMaxAlloc(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithMaxAlloc(1 << 10))
+
+	b := Budgeted{}
+	err := d.Parse(url.Values{
+		"name":    {strings.Repeat("x", 100)},
+		"ids[]":   {"1", "2", "3"},
+		"tags[a]": {"b"},
+	}, &b)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "b.IDs", []int{1, 2, 3}, b.IDs)
+
+	err = d.Parse(url.Values{"name": {strings.Repeat("x", 2<<10)}}, &b)
+	assertEqual(t, "err",
+		LimitError{Key: "name", Limit: "allocation", Max: 1 << 10}, err)
+
+	// A single index can allocate a large slice.
+	err = d.Parse(url.Values{"items[1000][A]": {"1"}}, &Budgeted{})
+	assertEqual(t, "err", LimitError{
+		Key:   "items[1000]",
+		Limit: "allocation",
+		Max:   1 << 10,
+	}, err)
+
+	// The budget is shared between all the keys of a single parse, but
+	// not between parses.
+	params := url.Values{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		params.Set("tags["+k+"]", strings.Repeat("x", 150))
+	}
+	err = d.Parse(params, &Budgeted{})
+	if lerr, ok := err.(LimitError); !ok || lerr.Limit != "allocation" {
+		t.Errorf("Expected allocation LimitError, got %v", err)
+	}
+	delete(params, "tags[h]")
+	delete(params, "tags[g]")
+	delete(params, "tags[f]")
+	for i := 0; i < 3; i++ {
+		if err := d.Parse(params, &Budgeted{}); err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+	}
+}
+
+func TestMaxNested(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithMaxNested(6))
+
+	c := Crazy{}
+	err := d.Parse(url.Values{
+		"A[A][A][Value]": {"1"},
+		"B[B][B][Value]": {"2"},
+	}, &c)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "c.B.B.B.Value", 2, c.B.B.B.Value)
+
+	// Reusing what's already there is free.
+	err = d.Parse(url.Values{"A[A][A][Value]": {"3"}}, &c)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	deep := "A" + strings.Repeat("[A]", 6) + "[Value]"
+	err = d.Parse(url.Values{deep: {"1"}}, &Crazy{})
+	assertEqual(t, "err", LimitError{
+		Key:   "A[A][A][A][A][A][A]",
+		Limit: "nested values",
+		Max:   6,
+	}, err)
+
+	// Map entries count too.
+	err = d.Parse(url.Values{
+		"Map[a][Value]":     {"1"},
+		"Map[b][Value]":     {"1"},
+		"A[A][A][A][Value]": {"1"},
+	}, &Crazy{})
+	if lerr, ok := err.(LimitError); !ok || lerr.Limit != "nested values" {
+		t.Errorf("Expected nested values LimitError, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package param
+
+import "reflect"
+
+// WithAppendSlices returns an Option that makes the values of slices given all
+// at once, as in "foo[]=1&foo[]=2", be appended to the slice already in the
+// target instead of replacing it. Individual fields may be given this behavior
+// with the "append" tag option, as in `param:"tags,append"`.
+func WithAppendSlices() Option {
+	return func(d *Decoder) {
+		d.appendSlices = true
+	}
+}
+
+// Implements the "append" tag option for the given struct field.
+func wrapAppend(s reflect.Type, sf reflect.StructField, h parseFunc) parseFunc {
+	if sf.Type.Kind() != reflect.Slice || isLeaf(sf.Type) || isBytes(sf.Type) {
+		pebkac("struct %v field %q has the append option, but is of "+
+			"type %v, not a slice.", s, sf.Name, sf.Type)
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		if p.appendSlices || target.Len() == 0 ||
+			keytail != "[]" && !(p.repeatedKeys && keytail == "") {
+			h(p, key, keytail, values, target)
+			return
+		}
+		// Limit the capacity of the old slice so that appending to it
+		// can't scribble over anything beyond its end.
+		n := target.Len()
+		old := target.Slice3(0, n, n)
+		h(p, key, keytail, values, target)
+		target.Set(reflect.AppendSlice(old, target))
+	}
+}
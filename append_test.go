@@ -0,0 +1,63 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Appended struct {
+	Tags  []string `param:"tags,append"`
+	IDs   []int    `param:"ids"`
+	Names []string `param:"names"`
+}
+
+func TestAppendTag(t *testing.T) {
+	t.Parallel()
+
+	a := Appended{Tags: []string{"a", "b"}, IDs: []int{1}}
+	err := Parse(url.Values{
+		"tags[]": {"c"},
+		"ids[]":  {"2", "3"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.Tags", []string{"a", "b", "c"}, a.Tags)
+	assertEqual(t, "a.IDs", []int{2, 3}, a.IDs)
+
+	// Appending must not write into the old slice's spare capacity.
+	tags := make([]string, 1, 4)
+	tags[0] = "x"
+	a = Appended{Tags: tags}
+	err = Parse(url.Values{"tags[]": {"y"}}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.Tags", []string{"x", "y"}, a.Tags)
+	assertEqual(t, "tags", []string{"x", ""}, tags[:2])
+}
+
+func TestAppendSlices(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithAppendSlices())
+	a := Appended{Tags: []string{"a"}, IDs: []int{1}}
+	err := d.Parse(url.Values{
+		"tags[]":  {"b"},
+		"ids[]":   {"2", "3"},
+		"names[]": {"x"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.Tags", []string{"a", "b"}, a.Tags)
+	assertEqual(t, "a.IDs", []int{1, 2, 3}, a.IDs)
+	assertEqual(t, "a.Names", []string{"x"}, a.Names)
+
+	err = d.Parse(url.Values{"ids[]": {"4"}, "ids[1]": {"5"}}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "len(a.IDs)", 4, len(a.IDs))
+	assertEqual(t, "a.IDs[1]", 5, a.IDs[1])
+}
@@ -0,0 +1,106 @@
+package param
+
+import (
+	"encoding"
+	"encoding/base64"
+	"reflect"
+)
+
+var binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+var binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+
+// The encodings that may be selected with the "base64" tag option.
+var base64Encodings = map[string]*base64.Encoding{
+	"":       base64.StdEncoding,
+	"std":    base64.StdEncoding,
+	"url":    base64.URLEncoding,
+	"rawstd": base64.RawStdEncoding,
+	"rawurl": base64.RawURLEncoding,
+}
+
+// Reports whether values of the given type are byte slices.
+func isBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// Byte slices given as a single value, as in "data=aGVsbG8=", are decoded as
+// standard, padded base64, like encoding/json does. They may also be given as a
+// slice of numbers, as in "data[]=104&data[]=105", like any other slice.
+func parseBytes(p *parser, key, keytail string, values []string, target reflect.Value) {
+	if keytail != "" {
+		parseSlice(p, key, keytail, values, target)
+		return
+	}
+	decodeBase64(p, base64.StdEncoding, key, keytail, values, target)
+}
+
+// The "base64" tag option selects the encoding of a byte slice field: "std"
+// (the default, which is also used without the option), "url", "rawstd", or
+// "rawurl", as in `param:"data,base64=url"`. The raw encodings are unpadded.
+// Fields with the option may only be given as a single value.
+//
+// The option may also be given to fields of types implementing
+// encoding.BinaryUnmarshaler (or pointers to them), such as opaque binary IDs,
+// in which case the decoded bytes are passed to UnmarshalBinary.
+//
+// To accept arbitrary bytes verbatim instead, use the "raw" option.
+func base64Handler(s reflect.Type, sf reflect.StructField, name string) parseFunc {
+	enc, ok := base64Encodings[name]
+	if !ok {
+		pebkac("struct %v field %q has unknown base64 encoding %q.",
+			s, sf.Name, name)
+	}
+	if isBytes(sf.Type) {
+		return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+			decodeBase64(p, enc, key, keytail, values, target)
+		}
+	}
+	if !reflect.PtrTo(structType(sf.Type)).Implements(binaryUnmarshalerType) {
+		pebkac("struct %v field %q has the base64 option, but is of "+
+			"type %v, which is neither a byte slice nor an "+
+			"encoding.BinaryUnmarshaler.", s, sf.Name, sf.Type)
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		decodeBinary(p, enc, key, keytail, values, target)
+	}
+}
+
+func decodeBase64(p *parser, enc *base64.Encoding, key, keytail string, values []string, target reflect.Value) {
+	target.SetBytes(p.base64Value(enc, key, keytail, values, target.Type()))
+}
+
+func decodeBinary(p *parser, enc *base64.Encoding, key, keytail string, values []string, target reflect.Value) {
+	b := p.base64Value(enc, key, keytail, values, target.Type())
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+
+	bu := target.Addr().Interface().(encoding.BinaryUnmarshaler)
+	if err := bu.UnmarshalBinary(b); err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  err,
+		})
+	}
+}
+
+// Returns the bytes encoded by the single value of the given key, which is
+// bound to a value of type t.
+func (p *parser) base64Value(enc *base64.Encoding, key, keytail string, values []string, t reflect.Type) []byte {
+	value := p.primitive(key, keytail, t, values)
+	p.alloc(kpath(key, keytail), enc.DecodedLen(len(value)))
+	b, err := enc.DecodeString(value)
+	if err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: t,
+			Err:  err,
+		})
+	}
+	return b
+}
@@ -0,0 +1,115 @@
+package param
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+type Attachment struct {
+	Data   []byte  `param:"data"`
+	URL    []byte  `param:"url,base64=url"`
+	RawURL []byte  `param:"rawurl,base64=rawurl"`
+	Ptr    *[]byte `param:"ptr"`
+}
+
+func TestBase64(t *testing.T) {
+	t.Parallel()
+
+	var a Attachment
+	err := Parse(url.Values{
+		"data":   {"aGk/Pz8="},
+		"url":    {"aGk_Pz8="},
+		"rawurl": {"aGk_Pz8"},
+		"ptr[]":  {"104", "105"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.Data", []byte("hi???"), a.Data)
+	assertEqual(t, "a.URL", []byte("hi???"), a.URL)
+	assertEqual(t, "a.RawURL", []byte("hi???"), a.RawURL)
+	assertEqual(t, "a.Ptr", &[]byte{'h', 'i'}, a.Ptr)
+
+	for key, value := range map[string]string{
+		"data":   "aGk_Pz8=",
+		"url":    "aGk/Pz8=",
+		"rawurl": "aGk_Pz8=",
+	} {
+		err := Parse(url.Values{key: {value}}, &Attachment{})
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %q, got %v", key, err)
+		}
+	}
+
+	err = Parse(url.Values{"url[]": {"1"}}, &Attachment{})
+	if _, ok := err.(NestingError); !ok {
+		t.Errorf("Expected NestingError, got %v", err)
+	}
+}
+
+func TestBase64Encode(t *testing.T) {
+	t.Parallel()
+
+	a := Attachment{Data: []byte{0xff, 0xfe}, RawURL: []byte{0xff, 0xfe}}
+	params, err := Encode(a)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{
+		"data":   {base64.StdEncoding.EncodeToString(a.Data)},
+		"rawurl": {"__4"},
+	}, params)
+}
+
+// TokenID implements encoding.BinaryUnmarshaler, but not
+// encoding.TextUnmarshaler.
+type TokenID [4]byte
+
+func (id *TokenID) UnmarshalBinary(b []byte) error {
+	if len(b) != len(id) {
+		return errors.New("token IDs are four bytes long")
+	}
+	copy(id[:], b)
+	return nil
+}
+
+func (id TokenID) MarshalBinary() ([]byte, error) {
+	return id[:], nil
+}
+
+type Session struct {
+	ID     TokenID  `param:"id,base64=rawurl"`
+	Parent *TokenID `param:"parent,base64"`
+}
+
+func TestBase64Binary(t *testing.T) {
+	t.Parallel()
+
+	s := Session{}
+	err := Parse(url.Values{
+		"id":     {"AQIDBA"},
+		"parent": {"BQYHCA=="},
+	}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.ID", TokenID{1, 2, 3, 4}, s.ID)
+	assertEqual(t, "*s.Parent", TokenID{5, 6, 7, 8}, *s.Parent)
+
+	params, err := Encode(s)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{
+		"id":     {"AQIDBA"},
+		"parent": {"BQYHCA=="},
+	}, params)
+
+	err = Parse(url.Values{"id": {"AQID"}}, &s)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+	assertEqual(t, "Check", 0, len(Check(&s)))
+}
@@ -0,0 +1,77 @@
+package param
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errBadIndex = errors.New("invalid record index")
+
+// ParseBatch parses a batch of records, given as parameters of the form
+// "prefix[0][name]=a&prefix[1][name]=b", into a slice of structs of type T,
+// which replaces the contents of *out. Each record is parsed exactly as Parse
+// would parse its parameters with the "prefix[i]" removed ("name=a"). Records
+// appear in *out in order of their index, and indices needn't be contiguous.
+// Parameters that do not begin with prefix are ignored.
+//
+// If some records fail to parse, the rest are parsed regardless, and ParseBatch
+// returns a BatchErrors describing each failure. Keys that are malformed, or
+// that have indices which are not non-negative integers, are reported
+// immediately as SyntaxErrors and TypeErrors.
+func ParseBatch[T any](values url.Values, prefix string, out *[]T) (err error) {
+	defer recoverError(&err)
+
+	t := reflect.TypeOf(out).Elem()
+	targetStruct("param.ParseBatch", reflect.New(t.Elem()).Interface())
+
+	records := make(map[int]url.Values)
+	for _, key := range sortedKeys(values) {
+		if !strings.HasPrefix(key, prefix+"[") {
+			continue
+		}
+		idx, rest := keyed(t, key, key[len(prefix):])
+		name, tail := keyed(t.Elem(), key, rest)
+
+		i, err := strconv.Atoi(idx)
+		if err != nil || i < 0 {
+			panic(TypeError{
+				Key:  prefix + "[" + idx + "]",
+				Type: t.Elem(),
+				Err:  errBadIndex,
+			})
+		}
+		if records[i] == nil {
+			records[i] = make(url.Values)
+		}
+		records[i][name+tail] = values[key]
+	}
+
+	indices := make([]int, 0, len(records))
+	for i := range records {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	var errs BatchErrors
+	batch := make([]T, len(indices))
+	for j, i := range indices {
+		p := defaultDecoder.newParser(context.Background())
+		err := defaultDecoder.decode(p, "param.ParseBatch", records[i],
+			&batch[j])
+		p.release()
+		if err != nil {
+			errs = append(errs, BatchError{Index: i, Err: err})
+		}
+	}
+	*out = batch
+
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
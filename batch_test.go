@@ -0,0 +1,87 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseBatch(t *testing.T) {
+	t.Parallel()
+
+	var subs []Sub
+	err := ParseBatch(url.Values{
+		"subs[0][A]":  {"1"},
+		"subs[0][B]":  {"2"},
+		"subs[10][A]": {"3"},
+		"subs[2][B]":  {"4"},
+		"other":       {"5"},
+	}, "subs", &subs)
+	if err != nil {
+		t.Fatal("ParseBatch error: ", err)
+	}
+	assertEqual(t, "subs", []Sub{{1, 2}, {0, 4}, {3, 0}}, subs)
+
+	var es []Everything
+	err = ParseBatch(url.Values{
+		"e[0][Int]":       {"1"},
+		"e[1][Int]":       {"x"},
+		"e[2][Struct][A]": {"3"},
+		"e[3][Llama]":     {"4"},
+	}, "e", &es)
+	var errs BatchErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected BatchErrors, got %v", err)
+	}
+	assertEqual(t, "len(errs)", 2, len(errs))
+	assertEqual(t, "errs[0].Index", 1, errs[0].Index)
+	assertEqual(t, "errs[1].Index", 3, errs[1].Index)
+	assertEqual(t, "ErrorCode", "batch", ErrorCode(err))
+	assertEqual(t, "ErrorCode(errs[0])", "batch", ErrorCode(errs[0]))
+	if !errors.Is(err, ErrBatch) || !errors.Is(errs[0], ErrBatch) {
+		t.Errorf("Expected %v to be ErrBatch", err)
+	}
+	if !errors.Is(err, ErrType) || !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected %v to wrap the record errors", err)
+	}
+	assertEqual(t, "len(es)", 4, len(es))
+	assertEqual(t, "es[0].Int", 1, es[0].Int)
+	assertEqual(t, "es[2].Struct.A", 3, es[2].Struct.A)
+}
+
+func TestParseBatchErrors(t *testing.T) {
+	t.Parallel()
+
+	for key, sentinel := range map[string]error{
+		"subs[x][A]":  ErrType,
+		"subs[-1][A]": ErrType,
+		"subs[0]":     ErrSyntax,
+		"subs[0":      ErrSyntax,
+	} {
+		var subs []Sub
+		err := ParseBatch(url.Values{key: {"1"}}, "subs", &subs)
+		if !errors.Is(err, sentinel) {
+			t.Errorf("Expected %v for %q, got %v", sentinel, key, err)
+		}
+	}
+}
+
+func TestParseBatchBadIndices(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{
+		"subs[x][A]":  {"1"},
+		"subs[-1][A]": {"2"},
+		"subs[y][A]":  {"3"},
+	}
+	for i := 0; i < 10; i++ {
+		var subs []Sub
+		err := ParseBatch(params, "subs", &subs)
+		assertEqual(t, "err", TypeError{
+			Key:  "subs[-1]",
+			Type: reflect.TypeOf(Sub{}),
+			Err:  errBadIndex,
+		}, err)
+	}
+}
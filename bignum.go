@@ -0,0 +1,71 @@
+package param
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+var bigIntType = reflect.TypeOf(big.Int{})
+var bigFloatType = reflect.TypeOf(big.Float{})
+var bigRatType = reflect.TypeOf(big.Rat{})
+
+// Reports whether values of the given type are arbitrary-precision numbers.
+func isBig(t reflect.Type) bool {
+	return t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+// The arbitrary-precision numbers of math/big implement
+// encoding.TextUnmarshaler, but their UnmarshalText methods accept things, such
+// as "0x1F" for a big.Int, that we don't accept for other numbers. We parse
+// them with SetString instead, so that they are subject to the same Decoder
+// options as other numbers. big.Floats that don't already have a precision are
+// given one sufficient for the digits of the value.
+func parseBig(p *parser, key, keytail string, values []string, target reflect.Value) {
+	t := target.Type()
+	value := p.ungroup(p.primitive(key, keytail, t, values))
+	if value == "" && p.emptyZero {
+		p.warn(WarnEmptyZero, kpath(key, keytail))
+		target.Set(reflect.Zero(t))
+		return
+	}
+
+	var err error
+	if p.decimalComma && t != bigIntType {
+		value, err = decimalComma(value)
+	}
+	ok := err == nil
+	if ok {
+		switch n := target.Addr().Interface().(type) {
+		case *big.Int:
+			_, ok = n.SetString(value, p.intBase())
+		case *big.Float:
+			if n.Prec() == 0 {
+				n.SetPrec(floatPrec(value))
+			}
+			_, ok = n.SetString(value)
+		case *big.Rat:
+			_, ok = n.SetString(value)
+		}
+		if !ok {
+			err = strconv.ErrSyntax
+		}
+	}
+	if err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: t,
+			Err:  err,
+		})
+	}
+}
+
+// Returns a precision sufficient to represent a decimal number with as many
+// digits as the given value has characters (each of which needs a little less
+// than four bits), and at least that of a float64.
+func floatPrec(value string) uint {
+	if prec := uint(len(value)) * 4; prec > 53 {
+		return prec
+	}
+	return 53
+}
@@ -0,0 +1,73 @@
+package param
+
+import (
+	"math/big"
+	"net/url"
+	"testing"
+)
+
+type Ledger struct {
+	Balance big.Int    `param:"balance"`
+	Rate    *big.Float `param:"rate"`
+	Share   big.Rat    `param:"share"`
+	Amounts []*big.Int `param:"amounts"`
+}
+
+func TestBig(t *testing.T) {
+	t.Parallel()
+
+	l := Ledger{}
+	err := Parse(url.Values{
+		"balance":   {"123456789012345678901234567890"},
+		"rate":      {"0.1000000000000000000000000001"},
+		"share":     {"1/3"},
+		"amounts[]": {"1", "-2"},
+	}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l.Balance", "123456789012345678901234567890",
+		l.Balance.String())
+	assertEqual(t, "l.Rate", "0.1000000000000000000000000001",
+		l.Rate.Text('f', 28))
+	assertEqual(t, "l.Share", "1/3", l.Share.String())
+	assertEqual(t, "l.Amounts[1]", "-2", l.Amounts[1].String())
+
+	params, err := Encode(l)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params[balance]", []string{"123456789012345678901234567890"},
+		params["balance"])
+
+	for _, params := range []url.Values{
+		{"balance": {"0x10"}},
+		{"balance": {"1.5"}},
+		{"rate": {"1,5"}},
+		{"share": {"one third"}},
+	} {
+		err = Parse(params, &l)
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %v, got %v", params, err)
+		}
+	}
+}
+
+func TestBigOptions(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithPrefixedIntegers(), WithDecimalComma(),
+		WithGroupSeparators("."))
+	l := Ledger{}
+	err := d.Parse(url.Values{
+		"balance": {"0x10"},
+		"rate":    {"1.234,5"},
+		"share":   {"0,25"},
+	}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l.Balance", "16", l.Balance.String())
+	assertEqual(t, "l.Rate", "1234.5", l.Rate.Text('f', 1))
+	assertEqual(t, "l.Share", "1/4", l.Share.String())
+}
@@ -0,0 +1,141 @@
+package param
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// A Binder populates a struct from the various places an HTTP request carries
+// parameters. Each top-level field of the target struct may have an "in" struct
+// tag naming where its value comes from:
+//
+//	path    a path parameter, looked up with the Binder's PathParam function
+//	query   the URL's query string
+//	header  a request header (the field's name is canonicalized)
+//	cookie  a cookie
+//	form    the url-encoded request body
+//
+// A tag may name several sources separated by "|", for instance
+// `in:"header|query"`. In that case the sources are consulted in the order
+// they are listed, and the first one that has any value for the field wins;
+// values from different sources are never combined. Fields without an "in" tag
+// behave as if they were tagged `in:"form|query"`, so that, as with
+// http.Request.FormValue, the request body takes precedence over the query
+// string.
+//
+// Field names are derived the same way as for Parse, and fields may be given
+// by any name Parse accepts for them: their aliases, and the names of fields
+// promoted from embedded structs and of the fields of flattened fields, each of
+// which is bound according to its own "in" tag. Values from the query string
+// and request body may use the usual bracket syntax to address nested fields
+// (e.g., "filter[name]"); paths, headers, and cookies are only ever looked up
+// by the bare field name. Once the values for every field have been gathered
+// they are passed to Parse, so type conversion and errors are exactly as they
+// are there. Keys that do not correspond to any field are ignored.
+type Binder struct {
+	// PathParam returns the value of the named path parameter in the given
+	// request, or the empty string if there is none. Its signature matches
+	// that of goji.io/pat.Param. It must be set if any field is bound from
+	// "path".
+	PathParam func(r *http.Request, name string) string
+}
+
+// The sources untagged fields are bound from.
+const defaultSources = "form|query"
+
+// Bind the parameters of the given request into the given pointer to a struct
+// object.
+func (b Binder) Bind(r *http.Request, target interface{}) (err error) {
+	defer recoverError(&err)
+
+	t := targetStruct("param.Binder.Bind", target).Type()
+
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	query, form := keysByName(r.URL.Query()), keysByName(r.PostForm)
+
+	params := make(url.Values)
+	for _, f := range reachableFields(t, true, []reflect.Type{t}) {
+		sources := f.s.Field(f.l.offset).Tag.Get("in")
+		if sources == "" {
+			sources = defaultSources
+		}
+		for _, source := range strings.Split(sources, "|") {
+			if b.lookup(r, query, form, source, f.names, params) {
+				break
+			}
+		}
+	}
+
+	return Parse(params, target)
+}
+
+// Parameters grouped by the names of the fields they address, which is to say
+// by everything before the first opening bracket of their keys.
+type namedKeys map[string]url.Values
+
+func keysByName(params url.Values) namedKeys {
+	keys := make(namedKeys)
+	for key, values := range params {
+		name := key
+		if i := strings.IndexByte(key, '['); i >= 0 {
+			name = key[:i]
+		}
+		if keys[name] == nil {
+			keys[name] = make(url.Values)
+		}
+		keys[name][key] = values
+	}
+	return keys
+}
+
+// Copy the values for the field with the given names from the given source into
+// params, reporting whether any were found.
+func (b Binder) lookup(r *http.Request, query, form namedKeys, source string, names []string, params url.Values) bool {
+	found := false
+	for _, name := range names {
+		switch source {
+		case "path":
+			if b.PathParam == nil {
+				pebkac("field %q is bound from a path parameter, "+
+					"but the Binder has no PathParam function.",
+					name)
+			}
+			if v := b.PathParam(r, name); v != "" {
+				params[name] = []string{v}
+				found = true
+			}
+		case "query":
+			found = copyKeys(params, query[name]) || found
+		case "header":
+			if vs := r.Header[http.CanonicalHeaderKey(name)]; len(vs) > 0 {
+				params[name] = vs
+				found = true
+			}
+		case "cookie":
+			for _, c := range r.Cookies() {
+				if c.Name == name {
+					params[name] = append(params[name], c.Value)
+					found = true
+				}
+			}
+		case "form":
+			found = copyKeys(params, form[name]) || found
+		default:
+			pebkac("field %q has unknown source %q in its \"in\" tag.",
+				name, source)
+		}
+	}
+	return found
+}
+
+// Copy the given parameters into dst, reporting whether there were any.
+func copyKeys(dst, src url.Values) bool {
+	for key, values := range src {
+		dst[key] = values
+	}
+	return len(src) > 0
+}
@@ -0,0 +1,119 @@
+package param
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type Bound struct {
+	ID     int               `param:"id" in:"path"`
+	Page   int               `param:"page" in:"query"`
+	Token  string            `in:"header"`
+	Sess   string            `param:"session" in:"cookie"`
+	Name   string            `param:"name" in:"form"`
+	Lang   string            `param:"lang" in:"header|query"`
+	Filter map[string]string `param:"filter"`
+}
+
+func boundRequest() *http.Request {
+	body := url.Values{
+		"name":         {"bob"},
+		"filter[kind]": {"llama"},
+	}.Encode()
+	r := httptest.NewRequest("POST",
+		"/things/42?page=3&lang=fr&filter[kind]=alpaca&ignored=1",
+		strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Token", "secret")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	return r
+}
+
+func pathID(r *http.Request, name string) string {
+	if name == "id" {
+		return strings.TrimPrefix(r.URL.Path, "/things/")
+	}
+	return ""
+}
+
+func TestBind(t *testing.T) {
+	t.Parallel()
+
+	b := Binder{PathParam: pathID}
+	var bd Bound
+	err := b.Bind(boundRequest(), &bd)
+	if err != nil {
+		t.Fatal("Bind error: ", err)
+	}
+
+	assertEqual(t, "bd.ID", 42, bd.ID)
+	assertEqual(t, "bd.Page", 3, bd.Page)
+	assertEqual(t, "bd.Token", "secret", bd.Token)
+	assertEqual(t, "bd.Sess", "abc", bd.Sess)
+	assertEqual(t, "bd.Name", "bob", bd.Name)
+	assertEqual(t, "bd.Lang", "fr", bd.Lang)
+	// The request body takes precedence over the query string
+	assertEqual(t, "bd.Filter", map[string]string{"kind": "llama"},
+		bd.Filter)
+}
+
+func TestBindPrecedence(t *testing.T) {
+	t.Parallel()
+
+	r := boundRequest()
+	r.Header.Set("Lang", "de")
+
+	var bd Bound
+	err := Binder{PathParam: pathID}.Bind(r, &bd)
+	if err != nil {
+		t.Fatal("Bind error: ", err)
+	}
+	assertEqual(t, "bd.Lang", "de", bd.Lang)
+}
+
+func TestBindErrors(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest("GET", "/things/llama", nil)
+	var bd Bound
+	err := Binder{PathParam: pathID}.Bind(r, &bd)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError binding bad path, got %v", err)
+	}
+}
+
+type BoundPaging struct {
+	Page  int `param:"page" in:"query"`
+	Limit int `param:"limit,alias=per_page"`
+}
+
+type BoundList struct {
+	BoundPaging
+	Home Location `param:"home_,flatten"`
+}
+
+func TestBindReachable(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest("GET",
+		"/things?page=3&limit=20&home_street=main&home_[postcode]=12345",
+		nil)
+	var bl BoundList
+	if err := (Binder{}).Bind(r, &bl); err != nil {
+		t.Fatal("Bind error: ", err)
+	}
+	assertEqual(t, "bl.Page", 3, bl.Page)
+	assertEqual(t, "bl.Limit", 20, bl.Limit)
+	assertEqual(t, "bl.Home.Street", "main", bl.Home.Street)
+	assertEqual(t, "bl.Home.Zip", "12345", bl.Home.Zip)
+
+	r = httptest.NewRequest("GET", "/things?per_page=30", nil)
+	bl = BoundList{}
+	if err := (Binder{}).Bind(r, &bl); err != nil {
+		t.Fatal("Bind error: ", err)
+	}
+	assertEqual(t, "bl.Limit", 30, bl.Limit)
+}
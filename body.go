@@ -0,0 +1,137 @@
+package param
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"unsafe"
+)
+
+// WithZeroCopy returns an Option that makes ParseBody avoid copying the body
+// it is given: values that need no unescaping are parsed into string fields as
+// slices of the body itself, rather than as fresh strings. This can
+// drastically reduce the number of allocations made when parsing large bodies.
+//
+// The catch is that the strings a zero-copy ParseBody produces are only valid
+// for as long as the body is. The caller must not modify the body, or reuse its
+// buffer, until it is done with the target and with any strings it has copied
+// out of it.
+func WithZeroCopy() Option {
+	return func(d *Decoder) {
+		d.zeroCopy = true
+	}
+}
+
+var errSemicolon = errors.New("invalid semicolon separator in query")
+
+// ParseBody parses the given application/x-www-form-urlencoded request body
+// into the given pointer to a struct object. It is equivalent to parsing the
+// body with url.ParseQuery and passing the result to Parse, except that a
+// Decoder created with WithZeroCopy does not copy the body's contents, and one
+// created with WithSemicolons accepts semicolons as separators.
+func (d *Decoder) ParseBody(body []byte, target interface{}) error {
+	var params url.Values
+	var err error
+	if d.zeroCopy {
+		params, err = parseQueryZeroCopy(body, d.semicolons)
+	} else {
+		params, _, err = parseQueryOrdered(string(body), d.semicolons)
+	}
+	if err != nil {
+		return err
+	}
+	return d.parseContext(context.Background(), "param.Decoder.ParseBody",
+		params, target)
+}
+
+// Like url.ParseQuery, but the keys and values it returns refer to body's
+// memory wherever they needn't be unescaped. Unlike url.ParseQuery, it stops
+// at the first error.
+func parseQueryZeroCopy(body []byte, semicolons bool) (url.Values, error) {
+	// Nothing parseQueryOrdered does modifies the string, and
+	// url.QueryUnescape returns its argument when there is nothing to
+	// unescape, which is what saves us the copy.
+	query := unsafe.String(unsafe.SliceData(body), len(body))
+	params, _, err := parseQueryOrdered(query, semicolons)
+	return params, err
+}
+
+// Returns s, copied if the Decoder is zero-copy, and so s might refer to the
+// memory of a body, for the sake of keeping it somewhere that might outlive the
+// body, such as an error.
+func (d *Decoder) detach(s string) string {
+	if d.zeroCopy {
+		return strings.Clone(s)
+	}
+	return s
+}
+
+// Like detach, but for slices of strings.
+func (d *Decoder) detachAll(ss []string) []string {
+	if !d.zeroCopy || ss == nil {
+		return ss
+	}
+	detached := make([]string, len(ss))
+	for i, s := range ss {
+		detached[i] = strings.Clone(s)
+	}
+	return detached
+}
+
+// Returns err with copies of whatever keys and values it holds, as detach
+// does. The underlying errors of TypeErrors are left alone, but those strconv
+// returns copy the values they quote already.
+func (d *Decoder) detachError(err error) error {
+	if !d.zeroCopy {
+		return err
+	}
+	switch e := err.(type) {
+	case TypeError:
+		e.Key = d.detach(e.Key)
+		if ce, ok := e.Err.(ChoiceError); ok {
+			ce.Value = d.detach(ce.Value)
+			e.Err = ce
+		}
+		return e
+	case SingletonError:
+		e.Key, e.Values = d.detach(e.Key), d.detachAll(e.Values)
+		return e
+	case NestingError:
+		e.Key, e.Nesting = d.detach(e.Key), d.detach(e.Nesting)
+		return e
+	case SyntaxError:
+		e.Key, e.FullKey = d.detach(e.Key), d.detach(e.FullKey)
+		e.ErrorPart, e.Remainder = d.detach(e.ErrorPart), d.detach(e.Remainder)
+		return e
+	case KeyError:
+		e.FullKey, e.Key = d.detach(e.FullKey), d.detach(e.Key)
+		e.Field = d.detach(e.Field)
+		return e
+	case RequiredError:
+		e.Key = d.detach(e.Key)
+		return e
+	case ValidationError:
+		e.Key = d.detach(e.Key)
+		return e
+	case ValidationErrors:
+		detached := make(ValidationErrors, len(e))
+		for i, ve := range e {
+			detached[i] = d.detachError(ve).(ValidationError)
+		}
+		return detached
+	case LimitError:
+		e.Key = d.detach(e.Key)
+		return e
+	case ConflictError:
+		e.Key, e.Keys = d.detach(e.Key), d.detachAll(e.Keys)
+		return e
+	case FilterError:
+		e.Key = d.detach(e.Key)
+		return e
+	case InvalidParseError:
+		e.Key = d.detach(e.Key)
+		return e
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package param
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+	"unsafe"
+)
+
+type Post struct {
+	Title string   `param:"title"`
+	Body  string   `param:"body"`
+	Tags  []string `param:"tags"`
+	Draft bool     `param:"draft"`
+}
+
+func TestParseBody(t *testing.T) {
+	t.Parallel()
+
+	body := []byte("title=Hello%2C+world&body=lorem&tags[]=a&tags[]=b&draft=true")
+	for _, d := range []*Decoder{NewDecoder(), NewDecoder(WithZeroCopy())} {
+		var p Post
+		if err := d.ParseBody(body, &p); err != nil {
+			t.Fatal("ParseBody error: ", err)
+		}
+		assertEqual(t, "p", Post{
+			Title: "Hello, world",
+			Body:  "lorem",
+			Tags:  []string{"a", "b"},
+			Draft: true,
+		}, p)
+
+		aliased := p.Body != "" && unsafe.StringData(p.Body) == &body[bytes.Index(body, []byte("lorem"))]
+		assertEqual(t, "aliased", d.zeroCopy, aliased)
+	}
+}
+
+func TestParseBodyErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []*Decoder{NewDecoder(), NewDecoder(WithZeroCopy())} {
+		var p Post
+		err := d.ParseBody([]byte("title=%zz"), &p)
+		if _, ok := err.(url.EscapeError); !ok {
+			t.Errorf("Expected url.EscapeError, got %v", err)
+		}
+		if err := d.ParseBody([]byte("title=a;body=b"), &p); err == nil {
+			t.Error("Expected error for semicolon")
+		}
+		err = d.ParseBody([]byte("draft=maybe"), &p)
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError, got %v", err)
+		}
+	}
+}
+
+func TestZeroCopyErrors(t *testing.T) {
+	t.Parallel()
+
+	var failures []Failure
+	d := NewDecoder(WithZeroCopy(),
+		WithFailureSink(FailureSinkFunc(func(f Failure) {
+			failures = append(failures, f)
+		})))
+	body := []byte("title=a&bogus=x")
+	err := d.ParseBody(body, &Post{})
+	if _, ok := err.(KeyError); !ok {
+		t.Fatalf("Expected KeyError, got %v", err)
+	}
+
+	// Errors and captures must survive the body's buffer being reused.
+	for i := range body {
+		body[i] = '?'
+	}
+	assertEqual(t, "FullKey", "bogus", err.(KeyError).FullKey)
+	assertEqual(t, "failures[0].Err", err, failures[0].Err)
+	assertEqual(t, "failures[0].Params", url.Values{
+		"title": {"a"},
+		"bogus": {"x"},
+	}, failures[0].Params)
+}
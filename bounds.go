@@ -0,0 +1,155 @@
+package param
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var errOutOfBounds = errors.New("value out of bounds")
+
+// The bounds given to a numeric field by its "min" and "max" tag options.
+// Missing bounds are those of the field's type, so that min and max are always
+// of the types a RangeError gives them as.
+type bounds struct {
+	min, max interface{}
+	clamp    bool
+}
+
+// The "min" and "max" tag options restrict integer and floating point fields
+// (or slices of them, and so on) to the given bounds, inclusive, as in
+// `param:"limit,min=1,max=100"`. Values outside them are rejected with a
+// TypeError whose underlying error is a RangeError giving the bounds, unless
+// the field also has the "clamp" option, in which case they are replaced by the
+// nearest bound instead. Bounds must be within the range of the field's type,
+// and clamp must be given with at least one of them.
+func wrapBounds(s reflect.Type, sf reflect.StructField, opts tagOptions, h parseFunc) parseFunc {
+	if !opts.has("min") && !opts.has("max") {
+		pebkac("struct %v field %q has the clamp option, but neither "+
+			"a min nor a max option.", s, sf.Name)
+	}
+	bt := baseType(sf.Type)
+	switch bt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		pebkac("struct %v field %q has the min or max option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+
+	b := bounds{clamp: opts.has("clamp")}
+	b.min, b.max = typeBounds(bt)
+	if min, ok := opts["min"]; ok {
+		b.min = parseBound(s, sf, bt, "min", min)
+	}
+	if max, ok := opts["max"]; ok {
+		b.max = parseBound(s, sf, bt, "max", max)
+	}
+	if below(bt.Kind(), b.max, b.min) {
+		pebkac("struct %v field %q has a min option greater than its max "+
+			"option.", s, sf.Name)
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		h(p, key, keytail, values, target)
+		b.check(kpath(key, keytail), target)
+	}
+}
+
+// Parse the bound given by the given option as the kind of value bounds holds
+// for values of type t.
+func parseBound(s reflect.Type, sf reflect.StructField, t reflect.Type, opt, bound string) interface{} {
+	var v interface{}
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err = strconv.ParseInt(bound, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err = strconv.ParseUint(bound, 10, 64)
+	default:
+		v, err = strconv.ParseFloat(bound, 64)
+	}
+	if err != nil {
+		pebkac("struct %v field %q has invalid %s %q: %v", s, sf.Name, opt,
+			bound, err)
+	}
+	// A bound the field can't hold is a mistake, and would make clamping
+	// overflow.
+	min, max := typeBounds(t)
+	if below(t.Kind(), v, min) || below(t.Kind(), max, v) {
+		pebkac("struct %v field %q has %s %q, which is out of range "+
+			"for %v.", s, sf.Name, opt, bound, t)
+	}
+	return v
+}
+
+// Reports whether x is less than y, both of which are of the kind of value
+// bounds holds for values of the given kind.
+func below(k reflect.Kind, x, y interface{}) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.(int64) < y.(int64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return x.(uint64) < y.(uint64)
+	}
+	return x.(float64) < y.(float64)
+}
+
+// Check that the number or numbers in v, which has the given key, are within
+// bounds, clamping them if called for.
+func (b bounds) check(key string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			b.check(key, v.Elem())
+		}
+		return
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			b.check(key, v.Index(i))
+		}
+		return
+	}
+
+	var n interface{}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		// NaN is neither above nor below anything, but isn't within
+		// bounds either, and can't be clamped.
+		if math.IsNaN(f) {
+			b.fail(key, v)
+		}
+		n = f
+	default:
+		return
+	}
+
+	var bound interface{}
+	switch k := v.Kind(); {
+	case below(k, n, b.min):
+		bound = b.min
+	case below(k, b.max, n):
+		bound = b.max
+	default:
+		return
+	}
+	if !b.clamp {
+		b.fail(key, v)
+	}
+	v.Set(reflect.ValueOf(bound).Convert(v.Type()))
+}
+
+func (b bounds) fail(key string, v reflect.Value) {
+	panic(TypeError{
+		Key:  key,
+		Type: v.Type(),
+		Err:  RangeError{Min: b.min, Max: b.max, Err: errOutOfBounds},
+	})
+}
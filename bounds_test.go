@@ -0,0 +1,67 @@
+package param
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Page struct {
+	Limit  int       `param:"limit,min=1,max=100,default=25"`
+	Offset uint      `param:"offset,max=1000,clamp"`
+	Ratio  float64   `param:"ratio,min=0,max=1"`
+	Scores []int8    `param:"scores,min=-5,max=5,clamp"`
+	Weight *float32  `param:"weight,min=0.5"`
+	Sizes  []float64 `param:"sizes,max=10"`
+}
+
+func TestBounds(t *testing.T) {
+	t.Parallel()
+
+	p := Page{}
+	err := Parse(url.Values{
+		"offset":   {"5000"},
+		"ratio":    {"0.5"},
+		"scores[]": {"-10", "3", "100"},
+		"weight":   {"0.5"},
+	}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Limit", 25, p.Limit)
+	assertEqual(t, "p.Offset", uint(1000), p.Offset)
+	assertEqual(t, "p.Ratio", 0.5, p.Ratio)
+	assertEqual(t, "p.Scores", []int8{-5, 3, 5}, p.Scores)
+	assertEqual(t, "*p.Weight", float32(0.5), *p.Weight)
+
+	tests := []struct {
+		key, value string
+		min, max   interface{}
+		typ        reflect.Type
+	}{
+		{"limit", "0", int64(1), int64(100), reflect.TypeOf(0)},
+		{"limit", "101", int64(1), int64(100), reflect.TypeOf(0)},
+		{"ratio", "1.5", 0.0, 1.0, reflect.TypeOf(0.0)},
+		{"ratio", "NaN", 0.0, 1.0, reflect.TypeOf(0.0)},
+		{"weight", "0.25", 0.5, float64(math.MaxFloat32), reflect.TypeOf(float32(0))},
+		{"sizes[]", "11", -math.MaxFloat64, 10.0, reflect.TypeOf(0.0)},
+	}
+	for _, test := range tests {
+		err := Parse(url.Values{test.key: {test.value}}, &Page{})
+		var te TypeError
+		if !errors.As(err, &te) {
+			t.Errorf("Expected TypeError for %s=%s, got %v", test.key,
+				test.value, err)
+			continue
+		}
+		assertEqual(t, "te.Type", test.typ, te.Type)
+		assertEqual(t, "te.Err", RangeError{
+			Min: test.min,
+			Max: test.max,
+			Err: errOutOfBounds,
+		}, te.Err)
+		assertEqual(t, "ErrorCode(te.Err)", "range", ErrorCode(te.Err))
+	}
+}
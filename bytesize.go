@@ -0,0 +1,70 @@
+package param
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+var errByteSize = errors.New("invalid byte size")
+
+// The units accepted by the "bytesize" tag option, in lower case.
+var byteUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"eb":  1e18,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+	"eib": 1 << 60,
+}
+
+// The "bytesize" tag option parses integer fields (or slices of them, and so
+// on) as a number of bytes, optionally followed by a decimal (SI) or binary
+// (IEC) unit, as in "10MiB" or "1.5 GB". Units are case-insensitive. Sizes must
+// come to a whole number of bytes.
+func wrapByteSize(s reflect.Type, sf reflect.StructField, h parseFunc) parseFunc {
+	switch baseType(sf.Type).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		pebkac("struct %v field %q has the bytesize option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+
+	return transformValues(h, parseByteSize)
+}
+
+// Translate a byte size into a plain number of bytes, which the usual handler
+// can check is in range for the field's type.
+func parseByteSize(v string) (string, error) {
+	if v == "" {
+		// Let the usual handler decide what to make of it.
+		return v, nil
+	}
+	i := len(v)
+	for i > 0 && (v[i-1] < '0' || v[i-1] > '9') && v[i-1] != '.' {
+		i--
+	}
+	num := v[:i]
+	unit := strings.ToLower(strings.TrimSpace(v[i:]))
+
+	mult, ok := byteUnits[unit]
+	r, rok := new(big.Rat).SetString(num)
+	if !ok || !rok || num == "" || strings.ContainsAny(num, "/eE") {
+		return v, errByteSize
+	}
+	r.Mul(r, new(big.Rat).SetInt64(mult))
+	if !r.IsInt() {
+		return v, errByteSize
+	}
+	return r.Num().String(), nil
+}
@@ -0,0 +1,57 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Quota struct {
+	Max   int64    `param:"max,bytesize"`
+	Small uint16   `param:"small,bytesize"`
+	Tiers []uint64 `param:"tiers,bytesize"`
+}
+
+func TestByteSize(t *testing.T) {
+	t.Parallel()
+
+	for value, expected := range map[string]int64{
+		"0":       0,
+		"512":     512,
+		"512B":    512,
+		"10MiB":   10 << 20,
+		"2GB":     2e9,
+		"1.5 GiB": 3 << 29,
+		"1.5kb":   1500,
+		"8eib":    -1,
+		"7EiB":    7 << 60,
+		"-1KiB":   -1024,
+	} {
+		var q Quota
+		err := Parse(url.Values{"max": {value}}, &q)
+		if expected == -1 {
+			if _, ok := err.(TypeError); !ok {
+				t.Errorf("Expected TypeError for %q, got %v", value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Parse error for %q: %v", value, err)
+		}
+		assertEqual(t, value, expected, q.Max)
+	}
+
+	var q Quota
+	err := Parse(url.Values{"tiers[]": {"1KiB", "1KB"}}, &q)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "q.Tiers", []uint64{1024, 1000}, q.Tiers)
+
+	for _, value := range []string{"", "MiB", "1.0001KB", "1XB", "1/2KiB",
+		"1e3", "64KiB"} {
+		err := Parse(url.Values{"small": {value}}, &Quota{})
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %q, got %v", value, err)
+		}
+	}
+}
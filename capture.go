@@ -0,0 +1,144 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+)
+
+// Failure describes a call to Decoder.Parse that returned an error.
+type Failure struct {
+	// The parameters that failed to parse. Values are redacted according
+	// to the Decoder's redaction policy (see WithRedaction).
+	Params url.Values
+	// The type of struct the parameters were being parsed into.
+	Type reflect.Type
+	// The error that was returned. Values given for redacted keys are
+	// removed from it, as they are from Params, so it may differ from the
+	// error Parse returned.
+	Err error
+}
+
+// A FailureSink receives a Failure for each failed parse of a Decoder it is
+// attached to, for instance so they can be saved as regression test fixtures.
+// Capture is called synchronously from Decoder.Parse, possibly from several
+// goroutines at once.
+type FailureSink interface {
+	Capture(f Failure)
+}
+
+// The FailureSinkFunc type is an adapter to allow the use of ordinary functions
+// as FailureSinks.
+type FailureSinkFunc func(f Failure)
+
+// Capture calls f(failure).
+func (f FailureSinkFunc) Capture(failure Failure) {
+	f(failure)
+}
+
+// The string that redacted values are replaced with.
+const Redacted = "[REDACTED]"
+
+// WithFailureSink returns an Option that passes every failed parse to the given
+// sink.
+func WithFailureSink(sink FailureSink) Option {
+	return func(d *Decoder) {
+		d.failureSink = sink
+	}
+}
+
+// WithRedaction returns an Option that sets the Decoder's redaction policy:
+// the values of every key for which redact returns true are replaced with
+// Redacted before they leave the Decoder. redact is passed entire keys, such
+// as "user[password]".
+func WithRedaction(redact func(key string) bool) Option {
+	return func(d *Decoder) {
+		d.redact = redact
+	}
+}
+
+func (d *Decoder) captureFailure(params url.Values, target interface{}, err error) {
+	captured := make(url.Values, len(params))
+	for key, values := range params {
+		key = d.detach(key)
+		if d.redact != nil && d.redact(key) {
+			redacted := make([]string, len(values))
+			for i := range redacted {
+				redacted[i] = Redacted
+			}
+			captured[key] = redacted
+		} else {
+			captured[key] = append([]string(nil), d.detachAll(values)...)
+		}
+	}
+
+	d.failureSink.Capture(Failure{
+		Params: captured,
+		Type:   reflect.TypeOf(target),
+		Err:    d.redactError(err),
+	})
+}
+
+// What redacted errors are given in place of the errors they wrapped.
+var errRedacted = errors.New(Redacted)
+
+// Returns err with the values of the keys the Decoder redacts removed. Values
+// turn up in errors in all sorts of places, not least in the messages of the
+// errors strconv and TextUnmarshalers return, so rather than pick through them
+// we drop the underlying errors of errors about such keys altogether, keeping
+// only the bounds and choices we know to be safe.
+func (d *Decoder) redactError(err error) error {
+	if d.redact == nil {
+		return err
+	}
+	switch e := err.(type) {
+	case TypeError:
+		if d.redact(e.Key) {
+			e.Err = redactCause(e.Err)
+		}
+		return e
+	case SingletonError:
+		if d.redact(e.Key) {
+			values := make([]string, len(e.Values))
+			for i := range values {
+				values[i] = Redacted
+			}
+			e.Values = values
+		}
+		return e
+	case ValidationError:
+		if d.redact(e.Key) {
+			e.Err = errRedacted
+		}
+		return e
+	case ValidationErrors:
+		redacted := make(ValidationErrors, len(e))
+		for i, ve := range e {
+			redacted[i] = d.redactError(ve).(ValidationError)
+		}
+		return redacted
+	case FilterError:
+		if d.redact(e.Key) {
+			e.Err = errRedacted
+		}
+		return e
+	case InvalidParseError:
+		if d.redact(e.Key) {
+			e.Value = errRedacted
+		}
+		return e
+	}
+	return err
+}
+
+func redactCause(err error) error {
+	switch e := err.(type) {
+	case RangeError:
+		e.Err = errRedacted
+		return e
+	case ChoiceError:
+		e.Value = Redacted
+		return e
+	}
+	return errRedacted
+}
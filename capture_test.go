@@ -0,0 +1,84 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFailureSink(t *testing.T) {
+	t.Parallel()
+
+	var failures []Failure
+	d := NewDecoder(
+		WithFailureSink(FailureSinkFunc(func(f Failure) {
+			failures = append(failures, f)
+		})),
+		WithRedaction(func(key string) bool {
+			return strings.HasPrefix(key, "Struct")
+		}),
+	)
+
+	e := Everything{}
+	err := d.Parse(url.Values{"Int": {"1"}}, &e)
+	if err != nil {
+		t.Error("Parse error: ", err)
+	}
+	assertEqual(t, "len(failures)", 0, len(failures))
+
+	err = d.Parse(url.Values{
+		"Int":       {"llama"},
+		"Struct[A]": {"1", "2"},
+	}, &e)
+	if err == nil {
+		t.Fatal("Expected parse error")
+	}
+	if len(failures) != 1 {
+		t.Fatalf("Expected one failure, got %d", len(failures))
+	}
+
+	f := failures[0]
+	assertEqual(t, "f.Err", err, f.Err)
+	assertEqual(t, "f.Type", reflect.TypeOf(&e), f.Type)
+	assertEqual(t, "f.Params", url.Values{
+		"Int":       {"llama"},
+		"Struct[A]": {Redacted, Redacted},
+	}, f.Params)
+}
+
+func TestFailureSinkRedactsErrors(t *testing.T) {
+	t.Parallel()
+
+	var failures []Failure
+	d := NewDecoder(
+		WithFailureSink(FailureSinkFunc(func(f Failure) {
+			failures = append(failures, f)
+		})),
+		WithRedaction(func(key string) bool {
+			return key == "Int" || key == "String"
+		}),
+	)
+
+	for _, params := range []url.Values{
+		{"String": {"hunter2", "hunter3"}},
+		{"Int": {"hunter2"}},
+		{"Int": {"99999999999999999999"}},
+	} {
+		if err := d.Parse(params, &Everything{}); err == nil {
+			t.Fatal("Expected parse error")
+		}
+	}
+	assertEqual(t, "len(failures)", 3, len(failures))
+
+	for _, f := range failures {
+		if msg := f.Err.Error(); strings.Contains(msg, "hunter") ||
+			strings.Contains(msg, "9999") {
+			t.Errorf("Captured error %q contains a redacted value", msg)
+		}
+	}
+	assertEqual(t, "Values", []string{Redacted, Redacted},
+		failures[0].Err.(SingletonError).Values)
+	te := failures[2].Err.(TypeError)
+	assertEqual(t, "ErrorCode", "range", ErrorCode(te.Err))
+}
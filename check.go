@@ -0,0 +1,124 @@
+package param
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// An UnboundField describes a struct field that Parse can never set, as
+// reported by Check.
+type UnboundField struct {
+	// The path from the target to the field, such as
+	// "Order.Items[].secret".
+	Path string
+	// The field itself.
+	Field reflect.StructField
+	// Why the field can't be set, such as "unexported".
+	Reason string
+}
+
+func (u UnboundField) String() string {
+	return u.Path + ": " + u.Reason
+}
+
+// Check reports the fields of the struct pointed to by target, and of every
+// struct reachable from it, that Parse can never set: fields that are
+// unexported, that are ignored because they are tagged "-", whose name is
+// shared with a later field (which wins), or whose type Parse does not support.
+// This allows the authors of a struct to verify that it contains no fields that
+// they believe clients can set but cannot. Fields are reported in the order
+// they are declared.
+//
+// Unlike Parse, Check does not complain about fields of unsupported types. It
+// does not take into account Decoder options such as WithFieldFilter.
+func Check(target interface{}) []UnboundField {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		pebkac("Target of param.Check must be a pointer to a struct. "+
+			"We instead were passed a %v", v.Type())
+	}
+	t := v.Elem().Type()
+
+	var unbound []UnboundField
+	checkType(t, t.Name(), make(map[reflect.Type]bool), &unbound)
+	return unbound
+}
+
+// Check the struct fields reachable from a value of type t, whose path is path,
+// returning a reason if values of type t themselves can't be parsed.
+func checkType(t reflect.Type, path string, seen map[reflect.Type]bool, unbound *[]UnboundField) string {
+	if isLeaf(t) {
+		return ""
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return ""
+	case reflect.Ptr:
+		return checkType(t.Elem(), path, seen, unbound)
+	case reflect.Slice:
+		return checkType(t.Elem(), path+"[]", seen, unbound)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return fmt.Sprintf("map key type %v is not a string",
+				t.Key())
+		}
+		return checkType(t.Elem(), path+"[]", seen, unbound)
+	case reflect.Struct:
+		if !seen[t] {
+			seen[t] = true
+			checkStruct(t, path, seen, unbound)
+		}
+		return ""
+	}
+	return fmt.Sprintf("unsupported type %v", t)
+}
+
+func checkStruct(t reflect.Type, path string, seen map[reflect.Type]bool, unbound *[]UnboundField) {
+	// The index of the last field cacheStruct sees with each name, which
+	// is the one that wins.
+	last := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); sf.PkgPath == "" || sf.Anonymous {
+			last[extractName(sf)] = i
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fpath := path + "." + sf.Name
+		name := extractName(sf)
+
+		var reason string
+		switch {
+		case sf.PkgPath != "":
+			reason = "unexported"
+		case ignored(sf):
+			reason = `tagged "-"`
+		case last[name] != i:
+			reason = fmt.Sprintf("name %q is shared with field %s",
+				name, t.Field(last[name]).Name)
+		case extractOptions(sf).has("base64"):
+			// Such fields are opaque, and base64Handler makes sure
+			// they can be parsed.
+		case sf.Type.Kind() == reflect.Interface:
+			if !extractOptions(sf).has("discriminator") {
+				reason = fmt.Sprintf("unsupported type %v "+
+					"(interfaces need a discriminator)", sf.Type)
+			}
+		default:
+			reason = checkType(sf.Type, fpath, seen, unbound)
+		}
+
+		if reason != "" {
+			*unbound = append(*unbound, UnboundField{
+				Path:   fpath,
+				Field:  sf,
+				Reason: reason,
+			})
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package param
+
+import (
+	"testing"
+)
+
+type DTO struct {
+	Name     string `param:"name"`
+	secret   string
+	Internal string `param:"-"`
+	Alias    string `param:"name2"`
+	Alias2   string `param:"name2"`
+	Nested   []*DTONested
+	Shape    Shape `param:"shape,discriminator=name"`
+	Any      interface{}
+	Self     *DTO
+}
+
+type DTONested struct {
+	Done     chan bool
+	ByID     map[int]string
+	Callback func()
+	Fine     map[string][]int
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	unbound := Check(&DTO{})
+	paths := make([]string, len(unbound))
+	for i, u := range unbound {
+		paths[i] = u.String()
+	}
+	assertEqual(t, "paths", []string{
+		"DTO.secret: unexported",
+		`DTO.Internal: tagged "-"`,
+		`DTO.Alias: name "name2" is shared with field Alias2`,
+		"DTO.Nested[].Done: unsupported type chan bool",
+		"DTO.Nested[].ByID: map key type int is not a string",
+		"DTO.Nested[].Callback: unsupported type func()",
+		"DTO.Any: unsupported type interface {} (interfaces need a " +
+			"discriminator)",
+	}, paths)
+	assertEqual(t, "unbound[0].Field.Name", "secret", unbound[0].Field.Name)
+
+	assertEqual(t, "Check(&Everything{})", []UnboundField(nil),
+		Check(&Everything{}))
+}
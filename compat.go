@@ -0,0 +1,306 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A DuplicatePolicy determines what happens when a key that expects a single
+// value, such as one bound to an int field, is given several.
+type DuplicatePolicy int
+
+const (
+	// DuplicatesError rejects duplicate values with a SingletonError. This
+	// is the default.
+	DuplicatesError DuplicatePolicy = iota
+	// DuplicatesFirst uses the first of the values.
+	DuplicatesFirst
+	// DuplicatesLast uses the last of the values.
+	DuplicatesLast
+)
+
+// WithDuplicates returns an Option that sets the Decoder's DuplicatePolicy.
+func WithDuplicates(policy DuplicatePolicy) Option {
+	return func(d *Decoder) {
+		d.duplicates = policy
+	}
+}
+
+// WithMapDuplicates returns an Option that sets the DuplicatePolicy for map
+// entries given several values, as in "m[a]=1&m[a]=2", separately from the
+// Decoder's DuplicatePolicy, which otherwise applies to them as it does to any
+// other key. Entries that are slices, and so expect several values, are not
+// affected.
+func WithMapDuplicates(policy DuplicatePolicy) Option {
+	return func(d *Decoder) {
+		d.mapPolicy = &policy
+	}
+}
+
+// Apply the Decoder's DuplicatePolicy for map entries, if it has one, to the
+// values given for the entry of a map of type t with the given key.
+func (p *parser) mapEntryValues(key string, t reflect.Type, values []string) []string {
+	if p.mapPolicy == nil || len(values) < 2 {
+		return values
+	}
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() == reflect.Slice && !isLeaf(et) && !isBytes(et) {
+		return values
+	}
+
+	switch *p.mapPolicy {
+	case DuplicatesFirst:
+		p.warn(WarnDuplicates, key, values[1:]...)
+		return values[:1]
+	case DuplicatesLast:
+		p.warn(WarnDuplicates, key, values[:len(values)-1]...)
+		return values[len(values)-1:]
+	}
+	panic(SingletonError{Key: key, Type: t.Elem(), Values: values})
+}
+
+// WithBoolValues returns an Option that accepts the given spellings of true and
+// false for boolean fields, such as "yes" and "no", or "checked", in addition
+// to the ones Parse always accepts ("true", "1", and "on", and "false", "0",
+// and ""). It may be given several times.
+func WithBoolValues(truthy, falsy []string) Option {
+	return func(d *Decoder) {
+		if d.boolValues == nil {
+			d.boolValues = make(map[string]bool)
+		}
+		for _, v := range truthy {
+			d.addBoolValue(v, true)
+		}
+		for _, v := range falsy {
+			d.addBoolValue(v, false)
+		}
+	}
+}
+
+// WithCaseInsensitiveBools returns an Option that matches the spellings of
+// booleans case-insensitively, so that for instance "TRUE", "On", and (with
+// WithBoolValues) "Yes" are accepted. Spellings given to WithBoolValues that
+// differ only in case must then mean the same thing.
+func WithCaseInsensitiveBools() Option {
+	return func(d *Decoder) {
+		d.foldBools = true
+		if d.boolValues == nil {
+			return
+		}
+		values := make([]string, 0, len(d.boolValues))
+		for v := range d.boolValues {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		old := d.boolValues
+		d.boolValues = make(map[string]bool, len(old))
+		for _, v := range values {
+			d.addBoolValue(v, old[v])
+		}
+	}
+}
+
+// Adds a spelling of a boolean, which is folded to lower case if the Decoder
+// matches spellings case-insensitively, so that boolValue needn't.
+func (d *Decoder) addBoolValue(v string, b bool) {
+	if d.foldBools {
+		v = strings.ToLower(v)
+		if was, ok := d.boolValues[v]; ok && was != b {
+			pebkac("the boolean spelling %q is given as both true "+
+				"and false once case is ignored (see "+
+				"WithCaseInsensitiveBools).", v)
+		}
+	}
+	d.boolValues[v] = b
+}
+
+// Look up one of the Decoder's additional spellings of booleans, which boolOf
+// has already folded to lower case if need be.
+func (d *Decoder) boolValue(value string) (bool, bool) {
+	b, ok := d.boolValues[value]
+	return b, ok
+}
+
+// WithEmptyAsZero returns an Option that parses empty values given for integer
+// and floating point fields as zero, rather than rejecting them, since that is
+// what HTML forms submit for numeric inputs that are left blank. This also
+// applies to fields with tag options such as "percent" and "bytesize".
+func WithEmptyAsZero() Option {
+	return func(d *Decoder) {
+		d.emptyZero = true
+	}
+}
+
+// WithEmptyAsNil returns an Option that treats empty values given for pointer
+// fields as though they were not given at all, rather than allocating a value
+// and attempting to parse the empty string into it. This allows pointers to be
+// used for optional fields, such as filters, whose inputs may be left blank.
+// With WithTrimSpace, values that are nothing but white space are empty too.
+func WithEmptyAsNil() Option {
+	return func(d *Decoder) {
+		d.emptyNil = true
+	}
+}
+
+// WithRepeatedKeys returns an Option that allows slices to be given as a
+// repeated key without a trailing "[]", as in "tags=a&tags=b", as well as in the
+// usual way.
+func WithRepeatedKeys() Option {
+	return func(d *Decoder) {
+		d.repeatedKeys = true
+	}
+}
+
+// WithDottedKeys returns an Option that allows nested keys to be written with
+// dots instead of brackets: "user.address.city" is treated exactly like
+// "user[address][city]". Only the part of a key that precedes any brackets is
+// split on dots, so that map keys given in brackets may contain dots. If the
+// same key is given in more than one way, as in "user.name=a&user[name]=b", its
+// values are merged in the sorted order of the keys they were given under,
+// which puts the values of the fully bracketed key last, so that it is the one
+// that wins under DuplicatesLast.
+func WithDottedKeys() Option {
+	return func(d *Decoder) {
+		d.dottedKeys = true
+	}
+}
+
+// Rewrite dotted keys into the bracketed keys the rest of the parser expects,
+// merging the values of keys that are rewritten to the same key in a defined
+// order (see WithDottedKeys).
+func undotKeys(params url.Values) url.Values {
+	undotted := make(url.Values, len(params))
+	for _, key := range sortedKeys(params) {
+		values := params[key]
+		key = undotKey(key)
+		undotted[key] = append(undotted[key], values...)
+	}
+	return undotted
+}
+
+// Translates the given keys, which are in order and distinct, as undotKeys
+// does, preserving their order and distinctness.
+func undotOrder(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	undotted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = undotKey(key); !seen[key] {
+			seen[key] = true
+			undotted = append(undotted, key)
+		}
+	}
+	return undotted
+}
+
+func undotKey(key string) string {
+	head, tail := key, ""
+	if i := strings.IndexByte(key, '['); i >= 0 {
+		head, tail = key[:i], key[i:]
+	}
+	parts := strings.Split(head, ".")
+	if len(parts) == 1 {
+		return key
+	}
+	return parts[0] + "[" + strings.Join(parts[1:], "][") + "]" + tail
+}
+
+// The following Options configure a Decoder to accept parameters as they are
+// produced or understood by other software, to ease migrating to this package.
+// They may be combined with other Options, which take precedence if given
+// later, as in NewDecoder(param.ProfileRails, param.WithDuplicates(...)).
+
+// ProfileJQuery accepts parameters as serialized by jQuery.param, which is
+// what this package was originally written for, and so is equivalent to the
+// default behavior: bracketed keys, slices given with "[]", booleans spelled
+// "true" and "false", and an error for duplicate values.
+func ProfileJQuery(d *Decoder) {
+	if d.names != nil && d.names.protoJSON {
+		d.rename(func(n *naming) {
+			n.protoJSON = false
+		})
+	}
+	d.dottedKeys = false
+	d.repeatedKeys = false
+	d.boolValues = nil
+	d.foldBools = false
+	d.duplicates = DuplicatesError
+}
+
+// ProfileRails accepts parameters as Rack and Rails parse them: bracketed keys,
+// slices given with "[]", the last of duplicate values winning, and the
+// spellings of booleans recognized by Active Model.
+func ProfileRails(d *Decoder) {
+	ProfileJQuery(d)
+	d.duplicates = DuplicatesLast
+	WithBoolValues([]string{"t", "T", "TRUE", "ON"},
+		[]string{"f", "F", "FALSE", "off", "OFF"})(d)
+}
+
+// ProfilePHP accepts parameters as PHP parses them: bracketed keys, slices
+// given with "[]", the last of duplicate values winning, and the spellings of
+// booleans recognized by filter_var's FILTER_VALIDATE_BOOLEAN, which, as there,
+// are matched case-insensitively.
+func ProfilePHP(d *Decoder) {
+	ProfileJQuery(d)
+	d.duplicates = DuplicatesLast
+	d.foldBools = true
+	WithBoolValues([]string{"yes"}, []string{"no", "off"})(d)
+}
+
+// ProfileGorillaSchema accepts parameters as github.com/gorilla/schema does:
+// dotted keys, slices given as repeated keys, the last of duplicate values
+// winning, and the spellings of booleans recognized by strconv.ParseBool.
+func ProfileGorillaSchema(d *Decoder) {
+	ProfileJQuery(d)
+	d.dottedKeys = true
+	d.repeatedKeys = true
+	d.duplicates = DuplicatesLast
+	WithBoolValues([]string{"t", "T", "TRUE", "True"},
+		[]string{"f", "F", "FALSE", "False"})(d)
+}
+
+// ProfileGRPCGateway accepts query parameters as grpc-gateway does, so that the
+// structs generated by protoc-gen-go for a gateway's request messages can be
+// parsed from the same URLs: dotted keys for the fields of nested messages,
+// repeated keys for repeated fields, an error for duplicate values of other
+// fields, and the spellings of booleans recognized by strconv.ParseBool.
+// Fields may be given under their proto names, which protoc-gen-go puts in
+// their "json" tags, or under the lowerCamelCase JSON names given in their
+// "protobuf" tags, as in "user.display_name" or "user.displayName".
+//
+// Well-known types such as google.protobuf.Timestamp, and oneof fields, are not
+// supported.
+func ProfileGRPCGateway(d *Decoder) {
+	ProfileJQuery(d)
+	d.dottedKeys = true
+	d.repeatedKeys = true
+	WithBoolValues([]string{"t", "T", "TRUE", "True"},
+		[]string{"f", "F", "FALSE", "False"})(d)
+	d.rename(func(n *naming) {
+		n.protoJSON = true
+	})
+}
+
+// Adds the JSON name from the given struct field's "protobuf" tag, if it has
+// one that differs from name, to the field's aliases.
+func protoJSONAlias(sf reflect.StructField, name string, opts tagOptions) tagOptions {
+	var alias string
+	for _, part := range strings.Split(sf.Tag.Get("protobuf"), ",") {
+		if strings.HasPrefix(part, "json=") {
+			alias = part[len("json="):]
+		}
+	}
+	if alias == "" || alias == name {
+		return opts
+	}
+	if a, ok := opts["alias"]; ok {
+		alias = a + "|" + alias
+	}
+	opts["alias"] = alias
+	return opts
+}
@@ -0,0 +1,340 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDuplicates(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{"Int": {"1", "2"}, "Slice[]": {"1", "2"}}
+	for policy, expected := range map[DuplicatePolicy]int{
+		DuplicatesFirst: 1,
+		DuplicatesLast:  2,
+	} {
+		e := Everything{}
+		err := NewDecoder(WithDuplicates(policy)).Parse(params, &e)
+		if err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "e.Int", expected, e.Int)
+		assertEqual(t, "e.Slice", []int{1, 2}, e.Slice)
+	}
+
+	err := NewDecoder(WithDuplicates(DuplicatesError)).Parse(params,
+		&Everything{})
+	if _, ok := err.(SingletonError); !ok {
+		t.Errorf("Expected SingletonError, got %v", err)
+	}
+}
+
+func TestBoolValues(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithBoolValues([]string{"yes"}, []string{"no"}))
+	for value, expected := range map[string]bool{
+		"yes": true, "no": false, "on": true, "0": false,
+	} {
+		e := Everything{Bool: !expected}
+		if err := d.Parse(url.Values{"Bool": {value}}, &e); err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "e.Bool", expected, e.Bool)
+	}
+
+	err := Parse(url.Values{"Bool": {"yes"}}, &Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
+
+func TestCaseInsensitiveBools(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithBoolValues([]string{"Y", "checked"}, []string{"N"}),
+		WithCaseInsensitiveBools())
+	for value, expected := range map[string]bool{
+		"TRUE": true, "On": true, "y": true, "CHECKED": true,
+		"False": false, "n": false,
+	} {
+		e := Everything{Bool: !expected}
+		if err := d.Parse(url.Values{"Bool": {value}}, &e); err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "e.Bool", expected, e.Bool)
+	}
+
+	d = NewDecoder(WithBoolValues([]string{"Y"}, nil))
+	err := d.Parse(url.Values{"Bool": {"y"}}, &Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
+
+func TestEmptyAsZero(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithEmptyAsZero())
+	e := Everything{Int: 1, Uint: 2, Float: 3, PInt: new(int)}
+	err := d.Parse(url.Values{
+		"Int": {""}, "Uint": {""}, "Float": {""}, "PInt": {""},
+		"Slice[]": {"", "1"},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 0, e.Int)
+	assertEqual(t, "e.Uint", uint(0), e.Uint)
+	assertEqual(t, "e.Float", 0.0, e.Float)
+	assertEqual(t, "*e.PInt", 0, *e.PInt)
+	assertEqual(t, "e.Slice", []int{0, 1}, e.Slice)
+
+	var q Quota
+	if err := d.Parse(url.Values{"max": {""}}, &q); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	var r Register
+	if err := d.Parse(url.Values{"addr": {""}}, &r); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	err = Parse(url.Values{"Int": {""}}, &Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+	err = d.Parse(url.Values{"Int": {" "}}, &Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
+
+func TestEmptyAsNil(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithEmptyAsNil())
+	e := Everything{}
+	err := d.Parse(url.Values{
+		"PInt": {""}, "PTime": {""}, "PString": {""}, "PPInt": {""},
+		"PFloat": {"1.5"}, "String": {""},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.PInt", (*int)(nil), e.PInt)
+	assertEqual(t, "e.PTime", (*time.Time)(nil), e.PTime)
+	assertEqual(t, "e.PString", (*string)(nil), e.PString)
+	assertEqual(t, "e.PPInt", (**int)(nil), e.PPInt)
+	assertEqual(t, "*e.PFloat", 1.5, *e.PFloat)
+
+	err = Parse(url.Values{"PInt": {""}}, &Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+
+	// Values that are empty once trimmed count as empty.
+	e = Everything{}
+	d = NewDecoder(WithEmptyAsNil(), WithTrimSpace())
+	err = d.Parse(url.Values{"PInt": {" "}, "PString": {"\t"}}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.PInt", (*int)(nil), e.PInt)
+	assertEqual(t, "e.PString", (*string)(nil), e.PString)
+	err = NewDecoder(WithEmptyAsNil()).Parse(url.Values{"PInt": {" "}},
+		&Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
+
+func TestUndotKey(t *testing.T) {
+	t.Parallel()
+
+	for key, expected := range map[string]string{
+		"a":        "a",
+		"a.b":      "a[b]",
+		"a.b.c":    "a[b][c]",
+		"a.b[c.d]": "a[b][c.d]",
+		"a[b.c]":   "a[b.c]",
+		"a.b[]":    "a[b][]",
+	} {
+		assertEqual(t, key, expected, undotKey(key))
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		profile Option
+		params  url.Values
+	}{
+		{ProfileJQuery, url.Values{
+			"Bool": {"true"}, "Int": {"2"}, "Struct[A]": {"3"},
+			"Slice[]": {"4", "5"},
+		}},
+		{ProfileRails, url.Values{
+			"Bool": {"t"}, "Int": {"1", "2"}, "Struct[A]": {"3"},
+			"Slice[]": {"4", "5"},
+		}},
+		{ProfilePHP, url.Values{
+			"Bool": {"yes"}, "Int": {"1", "2"}, "Struct[A]": {"3"},
+			"Slice[]": {"4", "5"},
+		}},
+		{ProfileGorillaSchema, url.Values{
+			"Bool": {"True"}, "Int": {"1", "2"}, "Struct.A": {"3"},
+			"Slice": {"4", "5"},
+		}},
+	} {
+		e := Everything{}
+		err := NewDecoder(test.profile).Parse(test.params, &e)
+		if err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "e.Bool", true, e.Bool)
+		assertEqual(t, "e.Int", 2, e.Int)
+		assertEqual(t, "e.Struct.A", 3, e.Struct.A)
+		assertEqual(t, "e.Slice", []int{4, 5}, e.Slice)
+	}
+
+	// PHP matches booleans case-insensitively.
+	php := NewDecoder(ProfilePHP)
+	for value, expected := range map[string]bool{
+		"TRUE": true, "On": true, "Yes": true, "OFF": false, "No": false,
+	} {
+		e := Everything{}
+		if err := php.Parse(url.Values{"Bool": {value}}, &e); err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "e.Bool", expected, e.Bool)
+	}
+
+	// The bracketed spelling of a key given both ways always wins.
+	gorilla := NewDecoder(ProfileGorillaSchema)
+	for i := 0; i < 10; i++ {
+		e := Everything{}
+		err := gorilla.Parse(url.Values{
+			"Struct.A": {"1"}, "Struct[A]": {"2"},
+		}, &e)
+		if err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "e.Struct.A", 2, e.Struct.A)
+	}
+
+	// Profiles reset whatever came before them
+	d := NewDecoder(ProfileGorillaSchema, ProfileJQuery)
+	err := d.Parse(url.Values{"Struct.A": {"3"}}, &Everything{})
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
+
+func TestMapDuplicates(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{"Map[a]": {"1", "2"}}
+
+	e := Everything{}
+	err := NewDecoder(WithMapDuplicates(DuplicatesLast)).Parse(params, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Map", map[string]int{"a": 2}, e.Map)
+
+	err = NewDecoder(WithMapDuplicates(DuplicatesFirst)).Parse(params, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Map", map[string]int{"a": 1}, e.Map)
+
+	// The map policy overrides the Decoder's.
+	d := NewDecoder(WithDuplicates(DuplicatesLast),
+		WithMapDuplicates(DuplicatesError))
+	err = d.Parse(params, &e)
+	assertEqual(t, "err", SingletonError{
+		Key:    "Map[a]",
+		Type:   reflect.TypeOf(0),
+		Values: []string{"1", "2"},
+	}, err)
+	err = d.Parse(url.Values{"Int": {"1", "2"}}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	// Without one, the Decoder's policy applies as usual.
+	err = NewDecoder(WithDuplicates(DuplicatesLast)).Parse(params, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Map", map[string]int{"a": 2}, e.Map)
+
+	// Slices want all of their values.
+	s := struct {
+		Tags map[string][]string
+	}{}
+	d = NewDecoder(WithMapDuplicates(DuplicatesError), WithRepeatedKeys())
+	err = d.Parse(url.Values{"Tags[a]": {"x", "y"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.Tags", map[string][]string{"a": {"x", "y"}}, s.Tags)
+}
+
+// Shaped like the output of protoc-gen-go.
+type GetUserRequest struct {
+	state     int
+	sizeCache int
+
+	UserId  string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Fields  []string `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+	Verbose bool     `protobuf:"varint,3,opt,name=verbose,proto3" json:"verbose,omitempty"`
+	Page    *PageReq `protobuf:"bytes,4,opt,name=page,proto3" json:"page,omitempty"`
+}
+
+type PageReq struct {
+	PageSize  int32  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func TestProfileGRPCGateway(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(ProfileGRPCGateway)
+	r := GetUserRequest{}
+	err := d.Parse(url.Values{
+		"userId":         {"42"},
+		"fields":         {"name", "email"},
+		"verbose":        {"True"},
+		"page.page_size": {"10"},
+		"page.pageToken": {"abc"},
+	}, &r)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "r", GetUserRequest{
+		UserId:  "42",
+		Fields:  []string{"name", "email"},
+		Verbose: true,
+		Page:    &PageReq{PageSize: 10, PageToken: "abc"},
+	}, r)
+
+	err = d.Parse(url.Values{"user_id": {"1", "2"}}, &r)
+	if _, ok := err.(SingletonError); !ok {
+		t.Errorf("Expected SingletonError, got %v", err)
+	}
+	err = d.Parse(url.Values{"user_id": {"1"}, "userId": {"2"}}, &r)
+	if _, ok := err.(ConflictError); !ok {
+		t.Errorf("Expected ConflictError, got %v", err)
+	}
+
+	// JSON names are only understood with the profile.
+	err = NewDecoder(ProfileGRPCGateway, ProfileJQuery).Parse(
+		url.Values{"userId": {"1"}}, &r)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
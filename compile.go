@@ -0,0 +1,131 @@
+package param
+
+import (
+	"reflect"
+	"sync"
+)
+
+// The first time we see a given target type, we walk every type reachable from
+// it and build their struct caches up front. This means that a programming
+// error anywhere in the target is reported immediately, along with the path
+// from the target to the offending field (for instance,
+// "Order.Items[].Meta.Chan"), rather than whenever a request first happens to
+// reach it, by which time we've lost track of how we got there.
+var compiledLock sync.RWMutex
+var compiled = make(map[reflect.Type]bool)
+
+func compileStruct(t reflect.Type) {
+	compiledLock.RLock()
+	ok := compiled[t]
+	compiledLock.RUnlock()
+	if ok {
+		return
+	}
+
+	compileType(t, t.Name(), make(map[reflect.Type]bool))
+
+	compiledLock.Lock()
+	compiled[t] = true
+	compiledLock.Unlock()
+}
+
+// path is the path from the root to a value of type t, for the sake of error
+// messages.
+func compileType(t reflect.Type, path string, seen map[reflect.Type]bool) {
+	if isLeaf(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	case reflect.Ptr:
+		compileType(t.Elem(), path, seen)
+	case reflect.Slice:
+		compileType(t.Elem(), path+"[]", seen)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			pebkac("%s has map type %v, whose key isn't a string "+
+				"(it's a %v).", path, t, t.Key())
+		}
+		compileType(t.Elem(), path+"[]", seen)
+	case reflect.Struct:
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath != "" && !sf.Anonymous || ignored(sf) {
+				continue
+			}
+			if sf.Type.Kind() == reflect.Interface &&
+				extractOptions(sf).has("discriminator") {
+				continue
+			}
+			// Fields with the base64 option are opaque.
+			if extractOptions(sf).has("base64") {
+				continue
+			}
+			compileType(sf.Type, path+"."+sf.Name, seen)
+		}
+		cacheStruct(t)
+	default:
+		pebkac("%s has illegal type %v (kind %v).", path, t, t.Kind())
+	}
+}
+
+// Precompile builds, up front, everything Parse needs to know about the types of
+// the given targets, each of which must be a pointer to a struct or a map, as
+// for Parse. The targets themselves are left untouched. Otherwise this happens
+// the first time a type is parsed into, which adds latency to the first request
+// to use it, and means that programming errors in the type, such as fields of
+// unsupported types or invalid default values, only come to light then.
+// Calling Precompile during initialization makes them come to light at once.
+//
+// Unlike Parse, Precompile doesn't halt the program over programming errors,
+// but returns them as InvalidParseErrors, as it does anything else that goes
+// wrong, so that they can be reported however the program sees fit.
+func Precompile(targets ...interface{}) error {
+	return defaultDecoder.Precompile(targets...)
+}
+
+// Precompile is like the package-level Precompile, but also builds what the
+// Decoder needs to know about the targets' types, if it names fields
+// differently (see WithTagNames and SetNameMapper).
+func (d *Decoder) Precompile(targets ...interface{}) (err error) {
+	defer recoverError(&err)
+	defer func() {
+		if r := recover(); r != nil {
+			pe, ok := r.(pebkacError)
+			if !ok {
+				panic(r)
+			}
+			err = InvalidParseError{Value: pe.error}
+		}
+	}()
+
+	for _, target := range targets {
+		t := reflect.TypeOf(target)
+		if t == nil || t.Kind() != reflect.Ptr ||
+			t.Elem().Kind() != reflect.Struct &&
+				t.Elem().Kind() != reflect.Map {
+			pebkac("Targets of param.Precompile must be pointers to "+
+				"structs or maps. We instead were passed a %v", t)
+		}
+		t = t.Elem()
+		compileStruct(t)
+		if d.names == nil {
+			continue
+		}
+		seen := make(map[reflect.Type]bool)
+		compileType(t, t.Name(), seen)
+		for st := range seen {
+			d.cached(st)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Warm struct {
+	Account *Account
+	Items   []Place
+}
+
+func TestPrecompile(t *testing.T) {
+	t.Parallel()
+
+	err := Precompile(&Warm{}, &map[string]Sub{})
+	if err != nil {
+		t.Fatal("Precompile error: ", err)
+	}
+	compiledLock.RLock()
+	assertEqual(t, "compiled[Warm]", true,
+		compiled[reflect.TypeOf(Warm{})])
+	compiledLock.RUnlock()
+
+	d := NewDecoder()
+	d.SetNameMapper(SnakeCase)
+	if err := d.Precompile(&Warm{}); err != nil {
+		t.Fatal("Precompile error: ", err)
+	}
+	d.names.lock.RLock()
+	_, ok := d.names.cache[reflect.TypeOf(Place{})]
+	d.names.lock.RUnlock()
+	assertEqual(t, "cached Place", true, ok)
+}
+
+type Unwarmable struct {
+	Name  string
+	Ready chan bool
+}
+
+func TestPrecompileErrors(t *testing.T) {
+	t.Parallel()
+
+	// Programming errors are returned rather than halting the program.
+	for _, target := range []interface{}{&Unwarmable{}, Unwarmable{}} {
+		err := Precompile(target)
+		if _, ok := err.(InvalidParseError); !ok {
+			t.Fatalf("Expected InvalidParseError, got %v", err)
+		}
+		if !strings.HasSuffix(err.Error(), yourFault) {
+			t.Errorf("Expected programming error, got %v", err)
+		}
+	}
+}
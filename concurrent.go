@@ -0,0 +1,158 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Parsing concurrently only pays for itself once there are a good many keys.
+const minConcurrentKeys = 256
+
+// WithConcurrency returns an Option that makes the Decoder parse the keys of
+// large sets of parameters, such as those received by bulk import endpoints,
+// into different top-level fields of the target concurrently, using up to n
+// goroutines. All the keys of any one field, including the keys of the fields
+// and elements nested within it, are parsed by the same goroutine, in the
+// usual order, so that maps and slices are never shared between goroutines.
+// Keys that don't name a field of the target directly, such as those of
+// promoted fields, may overlap with any of them, and so are parsed once the
+// rest are done.
+//
+// Parsing is unchanged as far as the result is concerned: the same error is
+// returned as would be otherwise, except that the limit on how many slice
+// elements may be allocated for indices, as in "items[9999][id]", applies to
+// each goroutine separately. If parsing fails, however, more of the target may
+// have been filled in than otherwise. Anything the Decoder calls while
+// parsing, such as decode hooks and TextUnmarshalers, must be safe for
+// concurrent use.
+//
+// Parameters are parsed concurrently only if there are at least a few hundred
+// keys, and not by Decoders with limits on allocation (see WithMaxAlloc and
+// WithMaxNested), by ParseWithWarnings, or into targets with BeforeParamers or
+// AfterParamers, all of which keep track of the parse as a whole.
+func WithConcurrency(n int) Option {
+	return func(d *Decoder) {
+		d.concurrency = n
+	}
+}
+
+// Reports whether the given number of keys should be parsed concurrently.
+func (p *parser) concurrent(keys int) bool {
+	return p.concurrency > 1 && keys >= minConcurrentKeys &&
+		p.maxAlloc <= 0 && p.maxNested <= 0 && p.warnings == nil &&
+		p.begun == nil
+}
+
+// The keys of a single field, which are parsed by the same goroutine.
+type keyGroup struct {
+	// Indices of the keys in the order they're to be parsed.
+	keys []int
+	// The offset of the field, or -1 for keys that don't name one.
+	offset int
+	// The parser of the goroutine that parsed the group.
+	p *parser
+	// The index of the key that failed to parse, if any, and why.
+	failed int
+	panic  interface{}
+}
+
+// Parse the given keys of p.params into el concurrently, as decodeFields
+// would, marking the fields that were given values in seen.
+func (p *parser) decodeConcurrently(cache structCache, keys []string, flat bool, el reflect.Value, seen []bool) {
+	var groups []*keyGroup
+	byOffset := make(map[int]*keyGroup)
+	rest := &keyGroup{offset: -1, failed: -1}
+	for i, key := range keys {
+		sk := key
+		if j := strings.IndexByte(key, '['); j != -1 {
+			sk = sk[:j]
+		}
+		_, l, ok := cache.lookup(sk)
+		if !ok {
+			rest.keys = append(rest.keys, i)
+			continue
+		}
+		g, ok := byOffset[l.offset]
+		if !ok {
+			g = &keyGroup{offset: l.offset, failed: -1}
+			byOffset[l.offset] = g
+			groups = append(groups, g)
+		}
+		g.keys = append(g.keys, i)
+	}
+
+	next := make(chan *keyGroup)
+	var wg sync.WaitGroup
+	for i := 0; i < p.concurrency && i < len(groups); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range next {
+				g.p = p.fork()
+				g.p.decodeGroup(g, cache, keys, flat, el)
+			}
+		}()
+	}
+	for _, g := range groups {
+		next <- g
+	}
+	close(next)
+	wg.Wait()
+
+	rest.p = p
+	p.decodeGroup(rest, cache, keys, flat, el)
+
+	// Report the error the first key to fail would have caused had we
+	// parsed the keys one at a time.
+	failed := rest
+	for _, g := range groups {
+		p.join(g.p)
+		if g.failed >= 0 && (failed.failed < 0 || g.failed < failed.failed) {
+			failed = g
+		}
+		seen[g.offset] = true
+	}
+	if failed.failed >= 0 {
+		panic(failed.panic)
+	}
+}
+
+// Parse the keys of the given group, recording the first to fail.
+func (p *parser) decodeGroup(g *keyGroup, cache structCache, keys []string, flat bool, el reflect.Value) {
+	i := -1
+	defer func() {
+		if r := recover(); r != nil {
+			g.failed, g.panic = i, r
+		}
+	}()
+	for _, i = range g.keys {
+		p.decodeKey(cache, keys[i], flat, el)
+	}
+}
+
+// Returns a parser for use by another goroutine, which keeps track of what it
+// parses separately from p.
+func (p *parser) fork() *parser {
+	f := &parser{
+		Decoder: p.Decoder,
+		ctx:     p.ctx,
+		params:  p.params,
+		root:    p.root,
+	}
+	if p.unknownKeys != nil {
+		f.unknownKeys = []string{}
+	}
+	if p.fields != nil {
+		f.fields = make(FieldSet)
+	}
+	return f
+}
+
+// Adds what the given fork of p kept track of to p.
+func (p *parser) join(f *parser) {
+	p.unknownKeys = append(p.unknownKeys, f.unknownKeys...)
+	for key := range f.fields {
+		p.fields[key] = struct{}{}
+	}
+}
@@ -0,0 +1,85 @@
+package param
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+type Bulk struct {
+	Prices map[string]int
+	Stock  map[string]uint
+	Names  []string
+	Items  []Sub
+	Owner  string
+	Embed
+}
+
+type Embed struct {
+	Note string
+}
+
+func bulkParams(n int) url.Values {
+	params := url.Values{"Owner": {"me"}, "Note": {"hi"}}
+	for i := 0; i < n; i++ {
+		s := strconv.Itoa(i)
+		params["Prices["+s+"]"] = []string{s}
+		params["Stock["+s+"]"] = []string{s}
+		params["Items["+s+"][A]"] = []string{s}
+	}
+	names := make([]string, n)
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+	}
+	params["Names[]"] = names
+	return params
+}
+
+func TestConcurrency(t *testing.T) {
+	t.Parallel()
+
+	params := bulkParams(500)
+	var want, got Bulk
+	wantFields, err := ParseWithFields(params, &want)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	d := NewDecoder(WithConcurrency(4))
+	fields, err := d.ParseWithFields(params, &got)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "got", want, got)
+	assertEqual(t, "fields", wantFields, fields)
+
+	params["Stock[7]"] = []string{"-1"}
+	params["Prices[x]"] = []string{"x"}
+	params["Unknown"] = []string{"x"}
+	wantErr := Parse(params, &Bulk{})
+	assertEqual(t, "err", wantErr, d.Parse(params, &Bulk{}))
+
+	unknown, err := d.ParseWithReport(params, &Bulk{})
+	assertEqual(t, "err", wantErr, err)
+	delete(params, "Stock[7]")
+	delete(params, "Prices[x]")
+	unknown, err = d.ParseWithReport(params, &Bulk{})
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "unknown", []string{"Unknown"}, unknown)
+}
+
+func BenchmarkBulk(b *testing.B) {
+	params := bulkParams(5000)
+	b.Run("Sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Parse(params, &Bulk{})
+		}
+	})
+	b.Run("Concurrent", func(b *testing.B) {
+		d := NewDecoder(WithConcurrency(4))
+		for i := 0; i < b.N; i++ {
+			d.Parse(params, &Bulk{})
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package param
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Whether it's worth looking for conditionally required fields within values of
+// a given type, computed once per type as with isValidatable.
+var conditionalLock sync.RWMutex
+var conditional = make(map[reflect.Type]bool)
+
+func isConditional(t reflect.Type) bool {
+	conditionalLock.RLock()
+	c, ok := conditional[t]
+	conditionalLock.RUnlock()
+	if ok {
+		return c
+	}
+
+	c = findConditional(t, make(map[reflect.Type]bool))
+
+	conditionalLock.Lock()
+	conditional[t] = c
+	conditionalLock.Unlock()
+	return c
+}
+
+func findConditional(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] || isLeaf(t) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return findConditional(t.Elem(), seen)
+	case reflect.Struct:
+		for _, l := range cacheStruct(t) {
+			if l.opts.has("required_if") || l.opts.has("required_with") ||
+				findConditional(t.Field(l.offset).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The fields a conditionally required field's required_if and required_with
+// options refer to, resolved when its struct is cached.
+type conditions struct {
+	// The field required_if refers to, if the option is given, and the
+	// values (separated by "|") that make the field required.
+	ifField  *condField
+	ifValues string
+	// The fields required_with refers to.
+	with []condField
+}
+
+// A field referred to by a condition, by its offset and by its name as the
+// cache it was resolved for names it, which is empty if that cache ignores it.
+type condField struct {
+	name   string
+	offset int
+}
+
+// Resolve the conditions of the conditionally required fields of the given
+// struct, whose cache is given, complaining about any that refer to fields that
+// don't exist. Like the fields keyfield refers to, these are named as they are
+// without WithTagNames or SetNameMapper, so that the same tags mean the same
+// thing to every Decoder.
+func checkConditions(s reflect.Type, sc structCache) {
+	for name, l := range sc {
+		sf := s.Field(l.offset)
+		var c conditions
+		if cond, ok := l.opts["required_if"]; ok {
+			field, values, found := strings.Cut(cond, ":")
+			if !found {
+				pebkac("struct %v field %q has invalid required_if "+
+					"option %q: expected a field name and values, as "+
+					"in \"required_if=country:US\".", s, sf.Name, cond)
+			}
+			cf := checkCondition(s, sf, sc, field)
+			c.ifField, c.ifValues = &cf, values
+		}
+		if with, ok := l.opts["required_with"]; ok {
+			for _, field := range strings.Split(with, "|") {
+				c.with = append(c.with, checkCondition(s, sf, sc, field))
+			}
+		}
+		if c.ifField != nil || c.with != nil {
+			l.cond = &c
+			sc[name] = l
+		}
+	}
+}
+
+func checkCondition(s reflect.Type, sf reflect.StructField, sc structCache, name string) condField {
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		if f.PkgPath != "" && !f.Anonymous || ignored(f) {
+			continue
+		}
+		if extractName(f) == name ||
+			name != "" && inList(extractOptions(f)["alias"], name) {
+			cf := condField{offset: i}
+			for n, l := range sc {
+				if l.offset == i {
+					cf.name = n
+				}
+			}
+			return cf
+		}
+	}
+	pebkac("struct %v field %q is conditionally required on field "+
+		"%q, which does not exist.", s, sf.Name, name)
+	return condField{}
+}
+
+// Complain about the first conditionally required field reachable from v, whose
+// key is key, that is missing.
+func checkRequired(p *parser, key string, v reflect.Value) {
+	t := v.Type()
+	if !isConditional(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			checkRequired(p, key, v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			checkRequired(p, key+"["+strconv.Itoa(i)+"]", v.Index(i))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, mk := range keys {
+			checkRequired(p, key+"["+escapeKey(mk.String())+"]",
+				v.MapIndex(mk))
+		}
+	case reflect.Struct:
+		cache := p.cached(t)
+		names := make([]string, 0, len(cache))
+		for name := range cache {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			l := cache[name]
+			fkey := fieldKey(key, name)
+			if p.filtered() && !p.fieldEnabled(fkey, t, name, l) {
+				continue
+			}
+			f := v.Field(l.offset)
+			if !p.present(fkey, f) && p.requiredBy(key, l.cond, v) {
+				panic(RequiredError{Key: fkey, Type: f.Type()})
+			}
+			checkRequired(p, fkey, f)
+		}
+	}
+}
+
+// Returns the key of the field with the given name of the struct with the given
+// key.
+func fieldKey(key, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + "[" + name + "]"
+}
+
+// Reports whether the field with the given key, whose value is f, is present:
+// whether it was given, even if only its zero value, or the target already had
+// a value for it. Only fields that weren't given are judged by their values.
+func (p *parser) present(key string, f reflect.Value) bool {
+	return p.fields.Has(key) || !f.IsZero()
+}
+
+// Reports whether the given conditions, if any, hold in the given struct, whose
+// key is given.
+func (p *parser) requiredBy(key string, c *conditions, v reflect.Value) bool {
+	if c == nil {
+		return false
+	}
+	if c.ifField != nil {
+		f := v.Field(c.ifField.offset)
+		s, ok := conditionValue(f)
+		if ok && p.present(fieldKey(key, c.ifField.name), f) &&
+			inList(c.ifValues, s) {
+			return true
+		}
+	}
+	for _, cf := range c.with {
+		if p.present(fieldKey(key, cf.name), v.Field(cf.offset)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the given value as Encode would give it, if it has a value at all.
+func conditionValue(v reflect.Value) (s string, ok bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if isOptional(v.Type()) {
+		o := optionalOf(v)
+		if !o.Present() {
+			return "", false
+		}
+		v = o.elem()
+	}
+	// Values that can't be encoded can't match anything.
+	defer func() {
+		if recover() != nil {
+			s, ok = "", false
+		}
+	}()
+	return encodeLeaf("", v)
+}
@@ -0,0 +1,132 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Shipping struct {
+	Country string `param:"country"`
+	State   string `param:"state,required_if=country:US|CA"`
+	Address struct {
+		Line1 string `param:"line1"`
+		Line2 string `param:"line2"`
+		Zip   string `param:"zip,required_with=line1|line2"`
+	} `param:"address"`
+	Items []struct {
+		Gift    *bool  `param:"gift"`
+		Message string `param:"message,required_if=gift:true"`
+	} `param:"items"`
+}
+
+func TestRequiredIf(t *testing.T) {
+	t.Parallel()
+
+	s := Shipping{}
+	err := Parse(url.Values{"country": {"FR"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	err = Parse(url.Values{"country": {"US"}, "state": {"CA"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	err = Parse(url.Values{"country": {"CA"}}, &Shipping{})
+	assertEqual(t, "err", RequiredError{
+		Key:  "state",
+		Type: stringType,
+	}, err)
+
+	err = Parse(url.Values{
+		"items[0][gift]":    {"false"},
+		"items[1][gift]":    {"true"},
+		"items[1][message]": {"hi"},
+		"items[2][gift]":    {"true"},
+	}, &Shipping{})
+	assertEqual(t, "err", RequiredError{
+		Key:  "items[2][message]",
+		Type: stringType,
+	}, err)
+}
+
+func TestRequiredWith(t *testing.T) {
+	t.Parallel()
+
+	err := Parse(url.Values{"address[zip]": {"12345"}}, &Shipping{})
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	err = Parse(url.Values{"address[line2]": {"Apt 4"}}, &Shipping{})
+	assertEqual(t, "err", RequiredError{
+		Key:  "address[zip]",
+		Type: stringType,
+	}, err)
+
+	// Values the target already had count.
+	s := Shipping{}
+	s.Address.Zip = "12345"
+	err = Parse(url.Values{"address[line1]": {"1 Main St"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+}
+
+type LineItem struct {
+	Country string `param:"country"`
+	Qty     int    `param:"qty,required_with=country"`
+	Express bool   `param:"express"`
+	Slot    int    `param:"slot,required_if=express:false"`
+}
+
+func TestRequiredGivenZero(t *testing.T) {
+	t.Parallel()
+
+	// Zero values count as given.
+	err := Parse(url.Values{"country": {"US"}, "qty": {"0"}}, &LineItem{})
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	err = Parse(url.Values{"country": {"US"}}, &LineItem{})
+	assertEqual(t, "err", RequiredError{Key: "qty", Type: reflect.TypeOf(0)}, err)
+
+	// Conditions on zero values hold only if they were given.
+	err = Parse(url.Values{}, &LineItem{})
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	err = Parse(url.Values{"express": {"false"}}, &LineItem{})
+	assertEqual(t, "err", RequiredError{Key: "slot", Type: reflect.TypeOf(0)}, err)
+}
+
+type Region struct {
+	Country string
+	State   string `param:",required_if=Country:US"`
+	ZipCode string `param:",required_with=State"`
+}
+
+func TestRequiredMapped(t *testing.T) {
+	t.Parallel()
+
+	// Conditions name fields as they are named without a name mapper.
+	d := NewDecoder()
+	d.SetNameMapper(SnakeCase)
+	err := d.Parse(url.Values{"country": {"US"}}, &Region{})
+	assertEqual(t, "err", RequiredError{Key: "state", Type: stringType}, err)
+	err = d.Parse(url.Values{"country": {"US"}, "state": {""}}, &Region{})
+	assertEqual(t, "err", RequiredError{Key: "zip_code", Type: stringType},
+		err)
+	err = d.Parse(url.Values{
+		"country":  {"US"},
+		"state":    {"NY"},
+		"zip_code": {"10001"},
+	}, &Region{})
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	err = Parse(url.Values{"Country": {"US"}}, &Region{})
+	assertEqual(t, "err", RequiredError{Key: "State", Type: stringType}, err)
+}
@@ -0,0 +1,92 @@
+package param
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+type tenantKey struct{}
+
+// Each tenant has its own set of valid plans
+var tenantPlans = map[string][]string{
+	"acme":   {"gold", "silver"},
+	"globex": {"platinum"},
+}
+
+type Plan string
+
+func (p *Plan) UnmarshalTextContext(ctx context.Context, text []byte) error {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	for _, plan := range tenantPlans[tenant] {
+		if plan == string(text) {
+			*p = Plan(text)
+			return nil
+		}
+	}
+	return errors.New("unknown plan")
+}
+
+type Subscription struct {
+	Plan  Plan `param:"plan"`
+	Seats int  `param:"seats"`
+}
+
+func (s Subscription) ValidateContext(ctx context.Context) error {
+	if ctx.Value(tenantKey{}) == "globex" && s.Seats > 10 {
+		return errors.New("too many seats")
+	}
+	return nil
+}
+
+func TestParseContext(t *testing.T) {
+	t.Parallel()
+
+	acme := context.WithValue(context.Background(), tenantKey{}, "acme")
+	globex := context.WithValue(context.Background(), tenantKey{}, "globex")
+
+	s := Subscription{}
+	err := ParseContext(acme, url.Values{
+		"plan":  {"gold"},
+		"seats": {"20"},
+	}, &s)
+	if err != nil {
+		t.Error("Parse error: ", err)
+	}
+	assertEqual(t, "s.Plan", Plan("gold"), s.Plan)
+
+	err = ParseContext(globex, url.Values{"plan": {"gold"}}, &s)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+
+	err = ParseContext(globex, url.Values{"seats": {"20"}}, &s)
+	if _, ok := err.(ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+	}
+
+	err = Parse(url.Values{"plan": {"gold"}}, &s)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError without tenant, got %v", err)
+	}
+}
+
+func TestSetValidatorContext(t *testing.T) {
+	t.Parallel()
+
+	var got interface{}
+	d := NewDecoder()
+	d.SetValidatorContext(func(ctx context.Context, v interface{}) error {
+		got = ctx.Value(tenantKey{})
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	err := d.ParseContext(ctx, url.Values{"plan": {"silver"}},
+		&Subscription{})
+	if err != nil {
+		t.Error("Parse error: ", err)
+	}
+	assertEqual(t, "tenant", "acme", got)
+}
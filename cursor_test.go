@@ -0,0 +1,71 @@
+package param
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type PageKey struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type SignedPageKey struct {
+	ID int `json:"id"`
+}
+
+func (SignedPageKey) CursorKey() []byte {
+	return []byte("llama")
+}
+
+type Paginated struct {
+	After  Cursor[PageKey]        `param:"after"`
+	Before *Cursor[SignedPageKey] `param:"before"`
+}
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	after := Cursor[PageKey]{PageKey{ID: 42, Name: "bob"}}
+	before := Cursor[SignedPageKey]{SignedPageKey{ID: 7}}
+
+	p := Paginated{}
+	err := Parse(url.Values{
+		"after":  {after.String()},
+		"before": {before.String()},
+	}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.After", after, p.After)
+	assertEqual(t, "p.Before", &before, p.Before)
+}
+
+func TestCursorErrors(t *testing.T) {
+	t.Parallel()
+
+	unsigned := Cursor[PageKey]{PageKey{ID: 7}}.String()
+	for key, value := range map[string]string{
+		"after":  "not base64!",
+		"before": unsigned,
+	} {
+		p := Paginated{}
+		err := Parse(url.Values{key: {value}}, &p)
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %s=%q, got %v", key,
+				value, err)
+		}
+	}
+
+	// Tampering with a signed cursor invalidates it
+	signed := Cursor[SignedPageKey]{SignedPageKey{ID: 7}}.String()
+	sig := signed[strings.IndexByte(signed, '.'):]
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"id":8}`)) + sig
+	p := Paginated{}
+	err := Parse(url.Values{"before": {forged}}, &p)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError for forged cursor, got %v", err)
+	}
+}
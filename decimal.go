@@ -0,0 +1,51 @@
+package param
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var errDecimalPoint = errors.New("decimal point in number with decimal comma")
+
+// WithDecimalComma returns an Option that parses floating point numbers with a
+// comma as their decimal separator instead of a point, as in "12,50". Points
+// are rejected, since in locales that use decimal commas they usually separate
+// groups of digits instead.
+func WithDecimalComma() Option {
+	return func(d *Decoder) {
+		d.decimalComma = true
+	}
+}
+
+// Translate a number with a decimal comma into one strconv understands.
+func decimalComma(v string) (string, error) {
+	if strings.IndexByte(v, '.') >= 0 {
+		return v, errDecimalPoint
+	}
+	return strings.Replace(v, ",", ".", 1), nil
+}
+
+// The "decimal" tag option sets the decimal separator of a floating point field
+// (or slice of them, and so on) to a comma, as in `param:"price,decimal=comma"`,
+// as though the Decoder had been created with WithDecimalComma.
+func wrapDecimal(s reflect.Type, sf reflect.StructField, sep string, h parseFunc) parseFunc {
+	if k := baseType(sf.Type).Kind(); k != reflect.Float32 && k != reflect.Float64 {
+		pebkac("struct %v field %q has the decimal option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+	if sep != "comma" {
+		pebkac("struct %v field %q has unknown decimal separator %q.",
+			s, sf.Name, sep)
+	}
+
+	th := transformValues(h, decimalComma)
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		if p.decimalComma {
+			// parseFloat will take care of it.
+			h(p, key, keytail, values, target)
+		} else {
+			th(p, key, keytail, values, target)
+		}
+	}
+}
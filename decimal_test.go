@@ -0,0 +1,48 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Price struct {
+	Amount float64   `param:"amount,decimal=comma"`
+	Tiers  []float32 `param:"tiers,decimal=comma"`
+	Rate   float64   `param:"rate"`
+}
+
+func TestDecimalComma(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{
+		"amount":  {"12,50"},
+		"tiers[]": {"1", "-0,5"},
+	}
+	for _, d := range []*Decoder{NewDecoder(), NewDecoder(WithDecimalComma())} {
+		var p Price
+		if err := d.Parse(params, &p); err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "p", Price{Amount: 12.5, Tiers: []float32{1, -0.5}}, p)
+
+		err := d.Parse(url.Values{"amount": {"1.000,50"}}, &p)
+		assertEqual(t, "err", TypeError{
+			Key:  "amount",
+			Type: reflect.TypeOf(p.Amount),
+			Err:  errDecimalPoint,
+		}, err)
+	}
+
+	var p Price
+	err := NewDecoder(WithDecimalComma()).Parse(url.Values{"rate": {"0,25"}}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Rate", 0.25, p.Rate)
+
+	err = Parse(url.Values{"rate": {"0,25"}}, &p)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
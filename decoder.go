@@ -0,0 +1,210 @@
+package param
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// A Decoder parses parameters into structs like Parse does, but with behavior
+// that can be adjusted by the options it was created with. The zero Decoder
+// behaves exactly like Parse.
+//
+// A Decoder must not be modified once it is in use, but is otherwise safe for
+// concurrent use by multiple goroutines.
+type Decoder struct {
+	failureSink FailureSink
+	redact      func(key string) bool
+	jsonNulls   bool
+	validator   func(context.Context, interface{}) error
+	fieldFilter func(KeyInfo) bool
+	permitted   map[string]bool
+	only        map[string]bool
+	except      map[string]bool
+	group       string
+	formatError func(err error, lang string) string
+	zeroCopy    bool
+	semicolons  bool
+	maxBodySize int64
+
+	maxDepth       int
+	maxKeys        int
+	maxValueLength int
+	maxAlloc       int
+	maxNested      int
+	strictUTF8     bool
+
+	duplicates   DuplicatePolicy
+	mapPolicy    *DuplicatePolicy
+	boolValues   map[string]bool
+	foldBools    bool
+	prefixedInts bool
+	decimalComma bool
+	groupSeps    string
+	trimSpace    bool
+	emptyZero    bool
+	emptyNil     bool
+	nullSentinel *string
+	repeatedKeys bool
+	appendSlices bool
+	dottedKeys   bool
+	keySplitter  KeySplitter
+	stringFilter func(key, value string) (string, error)
+	zeroMissing  bool
+	fillZero     bool
+	unsafeFields bool
+	concurrency  int
+
+	jsonUnmarshalers bool
+
+	// Registered implementations of interfaces, by name.
+	interfaces map[reflect.Type]map[string]reflect.Type
+	// Decode hooks (see WithDecodeHook).
+	hooks []typeHook
+	// Struct caches naming fields as the Decoder does, if it doesn't name
+	// them as usual.
+	names *nameCache
+}
+
+// An Option configures a Decoder.
+type Option func(*Decoder)
+
+// The Decoder used by the package-level functions.
+var defaultDecoder = &Decoder{}
+
+// NewDecoder returns a new Decoder configured with the given options.
+func NewDecoder(opts ...Option) *Decoder {
+	d := &Decoder{}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// With returns a copy of the Decoder with the given options applied to it,
+// leaving the Decoder itself untouched. This allows options to be applied to a
+// single call, as in d.With(param.Except("Role")).Parse(params, &user). The
+// copy shares whatever it can with the Decoder, such as its struct caches, so
+// this is cheap.
+func (d *Decoder) With(opts ...Option) *Decoder {
+	c := *d
+	// Options modify these in place, so the copy needs its own.
+	c.hooks = d.hooks[:len(d.hooks):len(d.hooks)]
+	if d.boolValues != nil {
+		c.boolValues = make(map[string]bool, len(d.boolValues))
+		for v, b := range d.boolValues {
+			c.boolValues[v] = b
+		}
+	}
+	if d.interfaces != nil {
+		c.interfaces = make(map[reflect.Type]map[string]reflect.Type,
+			len(d.interfaces))
+		for iface, impls := range d.interfaces {
+			c.interfaces[iface] = make(map[string]reflect.Type, len(impls))
+			for name, impl := range impls {
+				c.interfaces[iface][name] = impl
+			}
+		}
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// Parse the given arguments into the given pointer to a struct object.
+func (d *Decoder) Parse(params url.Values, target interface{}) error {
+	return d.parseContext(context.Background(), "param.Decoder.Parse",
+		params, target)
+}
+
+// ParseContext is like Parse, but passes the given context on to the
+// ContextTextUnmarshalers and ContextValidators it encounters, and to the
+// Decoder's validation function.
+func (d *Decoder) ParseContext(ctx context.Context, params url.Values, target interface{}) error {
+	return d.parseContext(ctx, "param.Decoder.ParseContext", params,
+		target)
+}
+
+// ParseWithReport is like Parse, but leniently ignores keys that do not
+// correspond to any field instead of returning a KeyError, returning them
+// instead (in sorted order) so they can be logged or otherwise monitored.
+func (d *Decoder) ParseWithReport(params url.Values, target interface{}) (unknownKeys []string, err error) {
+	p := d.newParser(context.Background())
+	defer p.release()
+	p.unknownKeys = []string{}
+	err = d.run(p, "param.Decoder.ParseWithReport", params, target)
+	sort.Strings(p.unknownKeys)
+	return p.unknownKeys, err
+}
+
+func (d *Decoder) parseContext(ctx context.Context, fn string, params url.Values, target interface{}) error {
+	p := d.newParser(ctx)
+	defer p.release()
+	return d.run(p, fn, params, target)
+}
+
+func (d *Decoder) run(p *parser, fn string, params url.Values, target interface{}) error {
+	err := d.decode(p, fn, params, target)
+	if err != nil {
+		err = d.detachError(err)
+	}
+	if err != nil && d.failureSink != nil {
+		d.captureFailure(params, target, err)
+	}
+	return err
+}
+
+// A parser holds the state of a single call to Parse, and is passed to every
+// parse function.
+type parser struct {
+	*Decoder
+	ctx context.Context
+	// If non-nil, unknown keys are appended here instead of causing a
+	// KeyError.
+	unknownKeys []string
+	// If non-nil, Warnings are appended here.
+	warnings []Warning
+	// The parameters being parsed, for handlers that need to look at keys
+	// other than their own.
+	params url.Values
+	// The type of the target, if it is a struct (see goNames).
+	root reflect.Type
+	// If non-nil, the keys of the struct fields that are parsed into are
+	// added here.
+	fields FieldSet
+	// The keys of the aliased fields we have checked for conflicts, and
+	// the index of the keys of params we check them against (see
+	// checkAliasConflict).
+	aliasChecked map[string]bool
+	heads        map[string]bool
+	// The keys of fields given by their aliases, and their canonical keys
+	// (see canonicalPath).
+	aliased []aliasedKey
+	// If non-nil, the order in which to process the keys of params.
+	order []string
+	// If non-nil, the keys of the structs whose BeforeParam methods have
+	// been called (see BeforeParamer).
+	begun map[string]bool
+	// Roughly how many bytes have been allocated (see WithMaxAlloc).
+	allocated int
+	// How many nested values have been allocated (see WithMaxNested).
+	nested int
+	// How many slice elements have been allocated to accommodate indices
+	// (see growSlice).
+	indexed int
+	// The backing arrays of the slices we have grown (see growSlice).
+	grown map[uintptr]bool
+	// The pointers whose targets we have validated (see runValidators).
+	validated map[pointee]bool
+
+	// Scratch space for the sorted keys of params (see release).
+	keys []string
+}
+
+// Returns a parser for use outside of any particular call to Parse, for
+// instance when checking default values.
+func defaultParser() *parser {
+	return defaultDecoder.newParser(context.Background())
+}
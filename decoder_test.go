@@ -0,0 +1,38 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseWithReport(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	e := Everything{}
+	unknown, err := d.ParseWithReport(url.Values{
+		"Int":       {"4"},
+		"Llama":     {"1"},
+		"Struct[A]": {"1"},
+		"Struct[C]": {"2"},
+		"Alpaca[x]": {"3"},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 4, e.Int)
+	assertEqual(t, "e.Struct.A", 1, e.Struct.A)
+	assertEqual(t, "unknown", []string{"Alpaca[x]", "Llama", "Struct[C]"},
+		unknown)
+
+	unknown, err = d.ParseWithReport(url.Values{"Int": {"4"}}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "unknown", []string{}, unknown)
+
+	_, err = d.ParseWithReport(url.Values{"Int": {"llama"}}, &e)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package param
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Describe lists the patterns of the keys accepted by the struct pointed to by
+// target, in sorted order, as derived from the same struct metadata Parse uses.
+// Each pattern is a key in which parts that vary are given as a placeholder in
+// angle brackets: for instance, "user[name]", "tags[]", "items[<index>][id]",
+// and "prefs[<key>]". Slices of structs tagged with the "keyfield" option are
+// described in terms of their key field, as in "items[<id>][name]", and fields
+// tagged with the "oneof" option along with their choices, as in
+// "sort=<asc|desc>". Fields are listed under each of their aliases, and fields
+// promoted from embedded structs and flattened fields under the keys that reach
+// them directly, as in "limit" and "address_city". Parts of the target whose
+// keys can't be known in advance, such as the fields of recursive types,
+// interfaces, and fields tagged with "raw", are described as "[...]".
+//
+// The patterns are intended for humans, for instance in error responses and
+// developer tooling, and are not meant to be parsed.
+func Describe(target interface{}) (keys []string, err error) {
+	defer recoverError(&err)
+
+	t := targetStruct("param.Describe", target).Type()
+	keys = describeStruct(t, "", []reflect.Type{t}, nil)
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// seen holds the struct types we are in the middle of describing, so that
+// recursive types are described as "[...]" instead of recursing forever.
+func describeType(t reflect.Type, key string, seen []reflect.Type, keys []string) []string {
+	if isLeaf(t) || isBytes(t) {
+		return append(keys, key)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return describeType(t.Elem(), key, seen, keys)
+	case reflect.Slice:
+		// Only slices of structs and maps need their elements to be
+		// addressed individually.
+		et := structType(t.Elem())
+		if isLeaf(et) || et.Kind() != reflect.Struct && et.Kind() != reflect.Map {
+			return append(keys, key+"[]")
+		}
+		return describeType(et, key+"[<index>]", seen, keys)
+	case reflect.Map:
+		return describeType(t.Elem(), key+"[<key>]", seen, keys)
+	case reflect.Struct:
+		if seenType(seen, t) {
+			return append(keys, key+"[...]")
+		}
+		return describeStruct(t, key, append(seen, t), keys)
+	case reflect.Interface:
+		return append(keys, key+"[...]")
+	}
+	return append(keys, key)
+}
+
+// Describes the fields of the struct type t, whose key is prefix, under each of
+// their names, including those of the fields reached through its embedded and
+// flattened fields.
+func describeStruct(t reflect.Type, prefix string, seen []reflect.Type, keys []string) []string {
+	for _, f := range reachableFields(t, true, seen) {
+		for _, n := range f.names {
+			key := n
+			if prefix != "" {
+				key = prefix + "[" + n + "]"
+			}
+			keys = describeField(f.s, f.l, key, f.seen, keys)
+		}
+	}
+	return keys
+}
+
+func describeField(t reflect.Type, l cacheLine, key string, seen []reflect.Type, keys []string) []string {
+	ft := t.Field(l.offset).Type
+
+	if l.opts.has("base64") {
+		keys = append(keys, key)
+	} else if l.opts.has("raw") {
+		if ft.Kind() == reflect.Map {
+			keys = append(keys, key+"[...]")
+		} else {
+			keys = append(keys, key)
+		}
+	} else if kf, ok := l.opts["keyfield"]; ok {
+		et := structType(ft.Elem())
+		kf, _, _ := keyFieldOf(et, kf)
+		for name, el := range cacheStruct(et) {
+			if name != kf {
+				keys = describeType(et.Field(el.offset).Type,
+					key+"[<"+kf+">]["+name+"]", seen, keys)
+			}
+		}
+	} else if oneof, ok := l.opts["oneof"]; ok {
+		// Fields with choices are always simple values, or slices of
+		// them, and so are described by a single key.
+		keys = describeType(ft, key, seen, keys)
+		keys[len(keys)-1] += "=<" +
+			strings.Join(strings.Fields(oneof), "|") + ">"
+	} else {
+		keys = describeType(ft, key, seen, keys)
+	}
+	return keys
+}
@@ -0,0 +1,124 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Catalog struct {
+	Name     string            `param:"name"`
+	Tags     []string          `param:"tags"`
+	Prefs    map[string]bool   `param:"prefs"`
+	Owner    *Sub              `param:"owner"`
+	Products []Product         `param:"products"`
+	ByID     []Product         `param:"by_id,keyfield=id"`
+	Extra    url.Values        `param:"extra,raw"`
+	Parent   *Catalog          `param:"parent"`
+	Shape    Shape             `param:"shape,discriminator=name"`
+	Prices   map[string][]int  `param:"prices"`
+	Nested   map[string]*Child `param:"nested"`
+}
+
+type Product struct {
+	ID   string `param:"id"`
+	Name string `param:"name"`
+}
+
+type Child struct {
+	Age int `param:"age"`
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	keys, err := Describe(&Catalog{})
+	if err != nil {
+		t.Fatal("Describe error: ", err)
+	}
+	assertEqual(t, "keys", []string{
+		"by_id[<id>][name]",
+		"extra[...]",
+		"name",
+		"nested[<key>][age]",
+		"owner[A]",
+		"owner[B]",
+		"parent[...]",
+		"prefs[<key>]",
+		"prices[<key>][]",
+		"products[<index>][id]",
+		"products[<index>][name]",
+		"shape[...]",
+		"tags[]",
+	}, keys)
+}
+
+func TestDescribeNames(t *testing.T) {
+	t.Parallel()
+
+	keys, err := Describe(&Tenant{})
+	if err != nil {
+		t.Fatal("Describe error: ", err)
+	}
+	assertEqual(t, "Tenant keys", []string{
+		"City",
+		"Country",
+		"Editor",
+		"ID",
+		"Located[City]",
+		"Located[Country]",
+		"Stamped[City]",
+		"Stamped[Editor]",
+	}, keys)
+
+	keys, err = Describe(&SignupForm{})
+	if err != nil {
+		t.Fatal("Describe error: ", err)
+	}
+	assertEqual(t, "SignupForm keys", []string{
+		"home_[geo_][lat]",
+		"home_[geo_][lng]",
+		"home_[geo_lat]",
+		"home_[geo_lng]",
+		"home_[postcode]",
+		"home_[street]",
+		"home_[zip]",
+		"home_geo_[lat]",
+		"home_geo_[lng]",
+		"home_geo_lat",
+		"home_geo_lng",
+		"home_postcode",
+		"home_street",
+		"home_zip",
+		"name",
+		"work_[geo_][lat]",
+		"work_[geo_][lng]",
+		"work_[geo_lat]",
+		"work_[geo_lng]",
+		"work_[postcode]",
+		"work_[street]",
+		"work_[zip]",
+		"work_geo_[lat]",
+		"work_geo_[lng]",
+		"work_geo_lat",
+		"work_geo_lng",
+		"work_postcode",
+		"work_street",
+		"work_zip",
+	}, keys)
+
+	keys, err = Describe(&Paging{})
+	if err != nil {
+		t.Fatal("Describe error: ", err)
+	}
+	assertEqual(t, "Paging keys", []string{
+		"f[name]",
+		"f[title]",
+		"filter[name]",
+		"filter[title]",
+		"limit",
+		"order[]",
+		"page_size",
+		"per_page",
+		"sort[]",
+	}, keys)
+}
@@ -0,0 +1,51 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// Diff compares two values of the same struct type (or pointers to them), as
+// Encode would encode them, and returns the parameters of new whose values
+// differ from those of old. This is what needs to be sent to turn old into new
+// with Parse, as for a PATCH request, and can also serve as an audit log of
+// what changed.
+//
+// Slices are encoded as a single key, so if any of their elements changed, all
+// of the slice's new elements are returned. Keys that old has and new lacks,
+// such as those of map entries that were removed, or of pointers that became
+// nil, can't be expressed as parameters Parse would understand, and are
+// omitted.
+func Diff(old, new interface{}) (patch url.Values, err error) {
+	defer recoverError(&err)
+
+	ot, nt := reflect.TypeOf(old), reflect.TypeOf(new)
+	if ot != nil && ot.Kind() == reflect.Ptr {
+		ot = ot.Elem()
+	}
+	if nt != nil && nt.Kind() == reflect.Ptr {
+		nt = nt.Elem()
+	}
+	if ot != nt {
+		pebkac("Arguments to param.Diff must be of the same type. We "+
+			"instead were passed a %v and a %v", reflect.TypeOf(old),
+			reflect.TypeOf(new))
+	}
+
+	op, err := Encode(old)
+	if err != nil {
+		return nil, err
+	}
+	np, err := Encode(new)
+	if err != nil {
+		return nil, err
+	}
+
+	patch = make(url.Values)
+	for key, values := range np {
+		if !reflect.DeepEqual(op[key], values) {
+			patch[key] = values
+		}
+	}
+	return patch, nil
+}
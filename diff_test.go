@@ -0,0 +1,51 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	one := 1
+	old := Everything{
+		Int:    1,
+		String: "a",
+		Slice:  []int{1, 2},
+		Map:    map[string]int{"a": 1, "b": 2},
+		PInt:   &one,
+	}
+	new := old
+	new.String = "b"
+	new.Slice = []int{1, 3}
+	new.Map = map[string]int{"a": 1, "c": 3}
+	new.Struct.A = 4
+	new.PInt = nil
+
+	patch, err := Diff(old, &new)
+	if err != nil {
+		t.Fatal("Diff error: ", err)
+	}
+	assertEqual(t, "patch", url.Values{
+		"String":    {"b"},
+		"Slice[]":   {"1", "3"},
+		"Map[c]":    {"3"},
+		"Struct[A]": {"4"},
+	}, patch)
+
+	// Applying the patch gets us from old to new, except for what
+	// can't be expressed.
+	if err := Parse(patch, &old); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "old.Slice", new.Slice, old.Slice)
+	assertEqual(t, "old.Map", map[string]int{"a": 1, "b": 2, "c": 3},
+		old.Map)
+
+	patch, err = Diff(new, new)
+	if err != nil {
+		t.Fatal("Diff error: ", err)
+	}
+	assertEqual(t, "patch", url.Values{}, patch)
+}
@@ -0,0 +1,182 @@
+package param
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Untagged embedded structs, and pointers to them, have their fields promoted,
+// as with encoding/json: given
+//
+//	type ListParams struct {
+//		*CommonFilters
+//		Query string `param:"q"`
+//	}
+//
+// the fields of CommonFilters may be given directly, as in "limit=10", as well
+// as by way of the embedded struct's own name, as in "CommonFilters[limit]=10".
+// Pointers are allocated as necessary. Fields of the outer struct take
+// precedence over promoted fields of the same name, and fields promoted from
+// embedded structs declared earlier take precedence over those declared later.
+
+// Reports whether the given struct field is an embedded struct whose fields
+// are promoted. untagged is whether the field is named by its tags.
+func promotes(sf reflect.StructField, untagged bool) bool {
+	if !sf.Anonymous || !untagged || isLeaf(sf.Type) {
+		return false
+	}
+	switch sf.Type.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Ptr:
+		// We can't allocate pointers to unexported types.
+		return sf.PkgPath == "" && sf.Type.Elem().Kind() == reflect.Struct &&
+			!isLeaf(sf.Type.Elem())
+	}
+	return false
+}
+
+// Returns the cache lines of the embedded structs of the given cache whose
+// fields are promoted, in the order they are declared.
+func embeds(cache structCache) []cacheLine {
+	var lines []cacheLine
+	for _, l := range cache {
+		if l.promoted {
+			lines = append(lines, l)
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].offset < lines[j].offset
+	})
+	return lines
+}
+
+// Finds the embedded struct within target, a struct with the given cache, that
+// has a field named sk, either directly or by way of further embedded structs.
+// Returns the embedded struct's cache and value, allocating a pointer to it if
+// necessary.
+func (p *parser) promoted(cache structCache, sk string, target reflect.Value) (structCache, reflect.Value, bool) {
+	for _, l := range embeds(cache) {
+		et := structType(target.Type().Field(l.offset).Type)
+		ec := p.cached(et)
+		if !p.hasPromoted(ec, sk, []reflect.Type{target.Type(), et}) {
+			continue
+		}
+
+		f := target.Field(l.offset)
+		if f.Kind() == reflect.Ptr {
+			if f.IsNil() {
+				f.Set(reflect.New(et))
+			}
+			f = f.Elem()
+		}
+		return ec, f, true
+	}
+	return nil, reflect.Value{}, false
+}
+
+// Reports whether a struct with the given cache has a field named sk, either
+// directly or by way of its embedded structs. seen holds the types we have
+// already looked in, in case of cycles.
+func (p *parser) hasPromoted(cache structCache, sk string, seen []reflect.Type) bool {
+	if _, _, ok := cache.lookup(sk); ok {
+		return true
+	}
+	for _, l := range embeds(cache) {
+		et := structType(seen[len(seen)-1].Field(l.offset).Type)
+		found := false
+		for _, st := range seen {
+			found = found || st == et
+		}
+		if !found && p.hasPromoted(p.cached(et), sk, append(seen, et)) {
+			return true
+		}
+	}
+	return false
+}
+
+// A field that may be given by name in a struct: one of the struct's own
+// fields, or one reached through its embedded or flattened fields.
+type reachable struct {
+	// The struct the field belongs to, and the field's cache line.
+	s reflect.Type
+	l cacheLine
+	// The names the field may be given by, its canonical name before its
+	// aliases, less any that are shadowed by other fields.
+	names []string
+	// The struct types we are in the middle of looking at, as for seen
+	// below, including s.
+	seen []reflect.Type
+}
+
+// Returns the fields that may be given by name in the struct type t, just as
+// parseStructField resolves names: its own fields in the order they are
+// declared, then those promoted from its embedded structs, then, if flat is
+// set, those of its flattened fields under their flat names (see flatten.go).
+// Fields found earlier shadow later ones of the same name. seen holds the
+// struct types we are in the middle of looking at, including t, so that
+// recursive types are not looked at again.
+func reachableFields(t reflect.Type, flat bool, seen []reflect.Type) []reachable {
+	return addReachable(nil, t, "", flat, seen, make(map[string]bool))
+}
+
+// prefix is the flat name the names of the fields of t are appended to, and
+// taken holds the names of the fields already found.
+func addReachable(fields []reachable, t reflect.Type, prefix string, flat bool, seen []reflect.Type, taken map[string]bool) []reachable {
+	cache := cacheStruct(t)
+	start := len(fields)
+	for name, l := range cache {
+		var names []string
+		for _, n := range append([]string{name}, l.aliases...) {
+			if !taken[prefix+n] {
+				names = append(names, prefix+n)
+			}
+		}
+		if names != nil {
+			fields = append(fields, reachable{t, l, names, seen})
+		}
+	}
+	own := fields[start:]
+	sort.Slice(own, func(i, j int) bool {
+		return own[i].l.offset < own[j].l.offset
+	})
+	for _, f := range own {
+		for _, n := range f.names {
+			taken[n] = true
+		}
+	}
+
+	// Copy seen before appending to it, since it's shared by the fields
+	// we've found.
+	seen = seen[:len(seen):len(seen)]
+	// Fields promoted from embedded structs can't be reached by flat names.
+	if prefix == "" {
+		for _, l := range embeds(cache) {
+			et := structType(t.Field(l.offset).Type)
+			if !seenType(seen, et) {
+				fields = addReachable(fields, et, "", flat,
+					append(seen, et), taken)
+			}
+		}
+	}
+	if flat {
+		names, lines := flattened(cache)
+		for i, l := range lines {
+			et := structType(t.Field(l.offset).Type)
+			if !seenType(seen, et) {
+				fields = addReachable(fields, et, prefix+names[i],
+					flat, append(seen, et), taken)
+			}
+		}
+	}
+	return fields
+}
+
+func seenType(seen []reflect.Type, t reflect.Type) bool {
+	for _, st := range seen {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
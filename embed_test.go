@@ -0,0 +1,67 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type CommonFilters struct {
+	Limit  int    `param:"limit"`
+	Cursor string `param:"cursor"`
+	*PageFilter
+}
+
+type PageFilter struct {
+	Page int `param:"page"`
+}
+
+type ListParams struct {
+	*CommonFilters
+	Sub
+	Query  string `param:"q"`
+	Cursor string `param:"cursor"`
+	*Place `param:"place"`
+}
+
+func TestEmbedded(t *testing.T) {
+	t.Parallel()
+
+	l := ListParams{}
+	err := Parse(url.Values{
+		"limit":  {"10"},
+		"page":   {"2"},
+		"A":      {"1"},
+		"q":      {"x"},
+		"cursor": {"abc"},
+	}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l.Limit", 10, l.Limit)
+	assertEqual(t, "l.Page", 2, l.Page)
+	assertEqual(t, "l.A", 1, l.A)
+	assertEqual(t, "l.Query", "x", l.Query)
+	assertEqual(t, "l.Cursor", "abc", l.Cursor)
+	assertEqual(t, "l.CommonFilters.Cursor", "", l.CommonFilters.Cursor)
+
+	l = ListParams{}
+	err = Parse(url.Values{"CommonFilters[cursor]": {"def"}}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l.CommonFilters.Cursor", "def", l.CommonFilters.Cursor)
+
+	l = ListParams{}
+	err = Parse(url.Values{"q": {"x"}}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	if l.CommonFilters != nil {
+		t.Error("Expected l.CommonFilters not to be allocated")
+	}
+
+	err = Parse(url.Values{"StreetName": {"Main"}}, &l)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
@@ -0,0 +1,329 @@
+package param
+
+import (
+	"encoding"
+	"encoding/base64"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+
+// Marshaler is implemented by types that know how to encode themselves as a
+// single parameter value, such as IDs, amounts of money, and enumerations.
+// Encode uses MarshalParam in preference to MarshalText. Errors it returns are
+// returned by Encode as EncodeErrors.
+type Marshaler interface {
+	MarshalParam() (string, error)
+}
+
+// Encode serializes the given struct, or pointer to a struct, into parameters
+// that Parse would parse back into an equal struct. Names are derived from
+// struct tags in the same way as for Parse.
+//
+// Types implementing Marshaler are encoded with MarshalParam, and types
+// implementing encoding.TextMarshaler with MarshalText. Nil pointers are
+// omitted entirely, including when they are elements of slices, as are
+// Optionals that are not present. Byte slices are encoded as base64. Since
+// Parse can only parse slices of simple values, Encode complains loudly about
+// slices of structs, maps, and slices, unless the slice is tagged with the
+// "keyfield" option, in which case it is encoded as a map keyed by the key
+// field.
+//
+// Fields tagged with the "omitempty" option, as in `param:"q,omitempty"`, are
+// omitted if they are empty by the rules encoding/json uses: false, 0, nil
+// pointers and interfaces, and empty strings, slices, arrays, and maps are
+// empty. (Parse ignores the option.)
+func Encode(v interface{}) (params url.Values, err error) {
+	defer recoverError(&err)
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		pebkac("Target of param.Encode must be a struct or a pointer "+
+			"to a struct. We instead were passed a %v", rv.Type())
+	}
+
+	params = make(url.Values)
+	encodeStruct(params, "", rv)
+	return params, nil
+}
+
+// EncodeToString is like Encode, but returns the parameters as a query string,
+// such as "a=1&b%5B%5D=2&b%5B%5D=3". Keys are sorted, the values of each key are
+// in the order Encode gives them (slices in order, for instance), and
+// everything is percent-encoded, so that equal values always produce the same
+// string, as signed URLs and cache keys need.
+func EncodeToString(v interface{}) (string, error) {
+	params, err := Encode(v)
+	if err != nil {
+		return "", err
+	}
+	return params.Encode(), nil
+}
+
+// Encode the given value with the given key into params. The key is the same as
+// the key Parse would expect, except for slices, which have no trailing "[]".
+func encode(params url.Values, key string, v reflect.Value) {
+	t := v.Type()
+	if isSQLNull(t) {
+		if v.Field(1).Bool() {
+			encode(params, key, v.Field(0))
+		}
+		return
+	}
+	if isOptional(t) {
+		if o := optionalOf(v); o.Present() {
+			encode(params, key, o.elem())
+		}
+		return
+	}
+	if s, ok := encodeLeaf(key, v); ok {
+		params[key] = append(params[key], s)
+		return
+	}
+	if isBytes(t) {
+		encodeBase64(params, key, base64.StdEncoding, v)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			encode(params, key, v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			e := v.Index(i)
+			for e.Kind() == reflect.Ptr && !e.IsNil() {
+				e = e.Elem()
+			}
+			if e.Kind() == reflect.Ptr {
+				continue
+			}
+			s, ok := encodeLeaf(key, e)
+			if !ok {
+				pebkac("unable to encode slice %q of type %v: "+
+					"param can only parse slices of simple "+
+					"values.", key, t)
+			}
+			params[key+"[]"] = append(params[key+"[]"], s)
+		}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			pebkac("key for map %v isn't a string (it's a %v).", t,
+				t.Key())
+		}
+		for _, mk := range v.MapKeys() {
+			encode(params, key+"["+escapeKey(mk.String())+"]",
+				v.MapIndex(mk))
+		}
+	case reflect.Struct:
+		encodeStruct(params, key, v)
+	case reflect.Interface:
+		if !v.IsNil() {
+			encode(params, key, v.Elem())
+		}
+	default:
+		pebkac("unable to encode key %q of type %v and kind %v.", key,
+			t, t.Kind())
+	}
+}
+
+func encodeStruct(params url.Values, prefix string, v reflect.Value) {
+	for name, l := range cacheStruct(v.Type()) {
+		key := name
+		if prefix != "" {
+			key = prefix + "[" + name + "]"
+		}
+
+		f := v.Field(l.offset)
+		if l.opts.has("omitempty") && isEmptyValue(f) {
+			continue
+		}
+		if l.opts.has("raw") {
+			encodeRaw(params, key, f)
+		} else if enc, ok := l.opts["base64"]; ok {
+			encodeBase64(params, key, base64Encodings[enc], f)
+		} else if kf, ok := l.opts["keyfield"]; ok {
+			encodeKeyField(params, key, kf, f)
+		} else {
+			encode(params, key, f)
+		}
+	}
+}
+
+// Reports whether v is empty by the rules encoding/json uses for the
+// "omitempty" option: false, 0, nil pointers and interfaces, and empty strings,
+// slices, arrays, and maps are empty. Structs never are.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Encodes a slice tagged with the "keyfield" option as a map from the key field
+// of each element to the rest of the element.
+func encodeKeyField(params url.Values, key, keyField string, v reflect.Value) {
+	et := structType(v.Type().Elem())
+	cache := cacheStruct(et)
+	keyField, kl, _ := keyFieldOf(et, keyField)
+
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if e.Kind() == reflect.Ptr {
+			if e.IsNil() {
+				continue
+			}
+			e = e.Elem()
+		}
+
+		kv, _ := encodeLeaf(key, e.Field(kl.offset))
+		ekey := key + "[" + escapeKey(kv) + "]"
+		for name, l := range cache {
+			f := e.Field(l.offset)
+			if name == keyField ||
+				l.opts.has("omitempty") && isEmptyValue(f) {
+				continue
+			}
+			encode(params, ekey+"["+name+"]", f)
+		}
+	}
+}
+
+// Encode the given value as a single string, if it's the sort of value that
+// Parse expects to be given as a single string.
+func encodeLeaf(key string, v reflect.Value) (string, bool) {
+	t := v.Type()
+	if m, ok := implementer(v, marshalerType); ok {
+		if m == nil {
+			return "", false
+		}
+		s, err := m.(Marshaler).MarshalParam()
+		if err != nil {
+			panic(EncodeError{Key: key, Type: t, Err: err})
+		}
+		return s, true
+	}
+	if tm, ok := implementer(v, textMarshalerType); ok {
+		if tm == nil {
+			return "", false
+		}
+		text, err := tm.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			panic(EncodeError{Key: key, Type: t, Err: err})
+		}
+		return string(text), true
+	}
+	if s, ok := encodeFlagValue(v); ok {
+		return s, true
+	}
+
+	switch t {
+	case rawMessageType:
+		if v.IsNil() {
+			return "", false
+		}
+		return string(v.Bytes()), true
+	case tcpAddrType:
+		addr := v.Interface().(net.TCPAddr)
+		return addr.String(), true
+	case udpAddrType:
+		addr := v.Interface().(net.UDPAddr)
+		return addr.String(), true
+	case urlType:
+		u := v.Interface().(url.URL)
+		return u.String(), true
+	case mailAddressType:
+		addr := v.Interface().(mail.Address)
+		return addr.String(), true
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, t.Bits()), true
+	case reflect.String:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// If v, or a pointer to it, implements the given interface, returns the value
+// that does, or nil if v is a nil pointer.
+func implementer(v reflect.Value, iface reflect.Type) (interface{}, bool) {
+	t := v.Type()
+	if !t.Implements(iface) && !reflect.PtrTo(t).Implements(iface) {
+		return nil, false
+	}
+	if t.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, true
+	}
+	if v.CanAddr() && reflect.PtrTo(t).Implements(iface) {
+		return v.Addr().Interface(), true
+	} else if t.Implements(iface) {
+		return v.Interface(), true
+	}
+	// We have a pointer receiver but can't take our address, so we'll have
+	// to make a copy.
+	c := reflect.New(t)
+	c.Elem().Set(v)
+	return c.Interface(), true
+}
+
+func encodeBase64(params url.Values, key string, enc *base64.Encoding, v reflect.Value) {
+	if isBytes(v.Type()) {
+		if !v.IsNil() {
+			params.Add(key, enc.EncodeToString(v.Bytes()))
+		}
+		return
+	}
+
+	// Otherwise v is, or points to, an encoding.BinaryMarshaler.
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	var bm encoding.BinaryMarshaler
+	if v.Type().Implements(binaryMarshalerType) {
+		bm = v.Interface().(encoding.BinaryMarshaler)
+	} else if reflect.PtrTo(v.Type()).Implements(binaryMarshalerType) {
+		c := reflect.New(v.Type())
+		c.Elem().Set(v)
+		bm = c.Interface().(encoding.BinaryMarshaler)
+	} else {
+		pebkac("unable to encode key %q of type %v: it has the base64 "+
+			"option, but isn't an encoding.BinaryMarshaler.", key,
+			v.Type())
+	}
+	b, err := bm.MarshalBinary()
+	if err != nil {
+		panic(EncodeError{Key: key, Type: v.Type(), Err: err})
+	}
+	params.Add(key, enc.EncodeToString(b))
+}
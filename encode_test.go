@@ -0,0 +1,246 @@
+package param
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	two := 2
+	ptwo := &two
+	b := true
+	s := "llama"
+	in := Everything{
+		Bool:    true,
+		Int:     -4,
+		Uint:    4,
+		Float:   4.25,
+		Map:     map[string]int{"a": 1, "b": 2},
+		Slice:   []int{3, 1, 4},
+		String:  "hello",
+		Struct:  Sub{A: 1, B: 2},
+		Time:    testTime,
+		PBool:   &b,
+		PString: &s,
+		PStruct: &Sub{B: 5},
+		PTime:   &testTime,
+		PPInt:   &ptwo,
+		AMap:    MyMap{"c": 3},
+		APtr:    MyPtr(new(MyInt)),
+		ASlice:  MySlice{1, 2},
+		AString: "alpaca",
+	}
+
+	params, err := Encode(&in)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params[Slice[]]", []string{"3", "1", "4"},
+		params["Slice[]"])
+	assertEqual(t, "params[Map[b]]", []string{"2"}, params["Map[b]"])
+	if _, ok := params["PInt"]; ok {
+		t.Error("Expected nil pointer to be omitted")
+	}
+
+	out := Everything{}
+	if err := Parse(params, &out); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Expected %v to round trip, got %v", in, out)
+	}
+}
+
+func TestEncodeKeyField(t *testing.T) {
+	t.Parallel()
+
+	c := Cart{
+		Items: []Item{
+			{ID: "abc123", Qty: 2},
+			{ID: "def456", Qty: 1, Note: "hi"},
+		},
+		Numbered: []*NumberedItem{{ID: 7, Qty: 3}, nil},
+	}
+	params, err := Encode(c)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{
+		"items[abc123][qty]":  {"2"},
+		"items[abc123][note]": {""},
+		"items[def456][qty]":  {"1"},
+		"items[def456][note]": {"hi"},
+		"numbered[7][qty]":    {"3"},
+	}, params)
+}
+
+type BadMarshaler struct{}
+
+func (BadMarshaler) MarshalText() ([]byte, error) {
+	return nil, errors.New("llama")
+}
+
+func (*BadMarshaler) UnmarshalText([]byte) error {
+	return nil
+}
+
+func TestEncodeError(t *testing.T) {
+	t.Parallel()
+
+	_, err := Encode(struct{ Bad BadMarshaler }{})
+	if eerr, ok := err.(EncodeError); !ok {
+		t.Errorf("Expected EncodeError, got %v", err)
+	} else {
+		assertEqual(t, "eerr.Key", "Bad", eerr.Key)
+	}
+}
+
+type Sparse struct {
+	Query   string            `param:"q,omitempty"`
+	Page    int               `param:"page,omitempty"`
+	Ratio   float64           `param:"ratio,omitempty"`
+	Exact   bool              `param:"exact,omitempty"`
+	Tags    []string          `param:"tags,omitempty"`
+	Filters map[string]string `param:"filters,omitempty"`
+	Since   *time.Time        `param:"since,omitempty"`
+	Range   Sub               `param:"range,omitempty"`
+	Always  string            `param:"always"`
+	Items   []SparseItem      `param:"items,keyfield=id"`
+}
+
+type SparseItem struct {
+	ID   string `param:"id"`
+	Note string `param:"note,omitempty"`
+}
+
+func TestEncodeOmitEmpty(t *testing.T) {
+	t.Parallel()
+
+	params, err := Encode(Sparse{
+		Tags:    []string{},
+		Filters: map[string]string{},
+		Items:   []SparseItem{{ID: "a"}, {ID: "b", Note: "hi"}},
+	})
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	// Structs are never empty.
+	assertEqual(t, "params", url.Values{
+		"range[A]":       {"0"},
+		"range[B]":       {"0"},
+		"always":         {""},
+		"items[b][note]": {"hi"},
+	}, params)
+
+	now := time.Now().UTC()
+	s := Sparse{
+		Query:   "llamas",
+		Page:    2,
+		Ratio:   0.5,
+		Exact:   true,
+		Tags:    []string{"a"},
+		Filters: map[string]string{"b": "c"},
+		Since:   &now,
+	}
+	params, err = Encode(s)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	u := Sparse{}
+	if err := Parse(params, &u); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "u", s, u)
+}
+
+func TestEncodeToString(t *testing.T) {
+	t.Parallel()
+
+	e := Everything{
+		Int:   4,
+		Map:   map[string]int{"b": 2, "a": 1, "c d": 3},
+		Slice: []int{3, 1, 2},
+	}
+	want := "ABool=false&AFloat=0&AInt=0&AString=&AUint=0&Bool=false&" +
+		"Float=0&Int=4&Map%5Ba%5D=1&Map%5Bb%5D=2&Map%5Bc+d%5D=3&" +
+		"Slice%5B%5D=3&Slice%5B%5D=1&Slice%5B%5D=2&String=&" +
+		"Struct%5BA%5D=0&Struct%5BB%5D=0&Time=0001-01-01T00%3A00%3A00Z&" +
+		"Uint=0"
+	for i := 0; i < 10; i++ {
+		s, err := EncodeToString(e)
+		if err != nil {
+			t.Fatal("Encode error: ", err)
+		}
+		assertEqual(t, "s", want, s)
+	}
+}
+
+type Cents int64
+
+func (c Cents) MarshalParam() (string, error) {
+	if c < 0 {
+		return "", errors.New("negative amount")
+	}
+	return fmt.Sprintf("%d.%02d", c/100, c%100), nil
+}
+
+func (c *Cents) UnmarshalText(text []byte) error {
+	var whole, frac int64
+	if _, err := fmt.Sscanf(string(text), "%d.%02d", &whole, &frac); err != nil {
+		return err
+	}
+	*c = Cents(whole*100 + frac)
+	return nil
+}
+
+// MarshalParam takes precedence over MarshalText.
+func (c Cents) MarshalText() ([]byte, error) {
+	return nil, errors.New("unreachable")
+}
+
+type Invoice struct {
+	Total  Cents   `param:"total"`
+	Lines  []Cents `param:"lines"`
+	Credit *Cents  `param:"credit"`
+}
+
+func TestEncodeMarshaler(t *testing.T) {
+	t.Parallel()
+
+	credit := Cents(5)
+	i := Invoice{Total: 1250, Lines: []Cents{1000, 250}, Credit: &credit}
+	params, err := Encode(i)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{
+		"total":   {"12.50"},
+		"lines[]": {"10.00", "2.50"},
+		"credit":  {"0.05"},
+	}, params)
+
+	u := Invoice{}
+	if err := Parse(params, &u); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "u", i, u)
+
+	params, err = Encode(Invoice{})
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{"total": {"0.00"}}, params)
+
+	_, err = Encode(Invoice{Total: -1})
+	assertEqual(t, "err", EncodeError{
+		Key:  "total",
+		Type: reflect.TypeOf(Cents(0)),
+		Err:  errors.New("negative amount"),
+	}, err)
+}
@@ -15,11 +15,26 @@ const yourFault = " This is a bug in your use of the param library."
 // Problem exists between keyboard and chair. This function is used in cases of
 // programmer error, i.e. an inappropriate use of the param library, to
 // immediately force the program to halt with a hopefully helpful error message.
+// It does so by panicking with a pebkacError, which the entry points that
+// recover from panics (see recoverError) pass to halt, unless they have a better
+// idea, as Precompile does.
 func pebkac(format string, a ...interface{}) {
-	err := errors.New(errPrefix + fmt.Sprintf(format, a...) + yourFault)
-	if pebkacTesting {
-		panic(err)
-	} else {
-		log.Fatal(err)
+	panic(pebkacError{
+		errors.New(errPrefix + fmt.Sprintf(format, a...) + yourFault),
+	})
+}
+
+// The value pebkac panics with, so that programmer errors can be told apart
+// from the errors the parser signals by panicking.
+type pebkacError struct {
+	error
+}
+
+// Halts the program with the given programmer error, unless we're testing, in
+// which case the error is returned instead.
+func (p pebkacError) halt() error {
+	if !pebkacTesting {
+		log.Fatal(p.error)
 	}
+	return p.error
 }
@@ -1,8 +1,30 @@
 package param
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+)
+
+// Each of the error types in this package matches one of these errors when
+// compared with errors.Is, allowing callers to check what sort of error they
+// have without caring about its details. For example, errors.Is(err,
+// ErrUnknownKey) reports whether err is (or wraps) a KeyError.
+var (
+	ErrType       = errors.New("param: type error")
+	ErrSingleton  = errors.New("param: singleton error")
+	ErrNesting    = errors.New("param: nesting error")
+	ErrSyntax     = errors.New("param: syntax error")
+	ErrUnknownKey = errors.New("param: unknown key")
+	ErrRequired   = errors.New("param: missing required key")
+	ErrValidation = errors.New("param: validation error")
+	ErrEncode     = errors.New("param: encoding error")
+	ErrLimit      = errors.New("param: limit exceeded")
+	ErrConflict   = errors.New("param: conflicting keys")
+	ErrFilter     = errors.New("param: rejected by filter")
+	ErrBatch      = errors.New("param: batch error")
+	ErrInvalid    = errors.New("param: internal error")
 )
 
 // TypeError is an error type returned when param has difficulty deserializing a
@@ -22,6 +44,73 @@ func (t TypeError) Error() string {
 		t.Err)
 }
 
+// Unwrap returns the underlying error, so that for instance errors.Is(err,
+// strconv.ErrRange) reports whether a number was out of range.
+func (t TypeError) Unwrap() error {
+	return t.Err
+}
+
+// Is reports whether target is ErrType.
+func (t TypeError) Is(target error) bool {
+	return target == ErrType
+}
+
+// Code returns "type" (see ErrorCode).
+func (t TypeError) Code() string {
+	return "type"
+}
+
+// RangeError is the underlying error of a TypeError returned when a number is
+// out of range for the numeric type it is parsed into, as when "300" is parsed
+// into an int8, or for the bounds given by the field's "min" and "max" tag
+// options. It gives the bounds, so that they can be reported to whoever gave
+// the number.
+type RangeError struct {
+	// The smallest and largest values allowed: int64s for signed integer
+	// types, uint64s for unsigned integer types, and float64s for
+	// floating-point types. Bounds not given by tag options are those of
+	// the type.
+	Min, Max interface{}
+	// The error produced by strconv, which wraps strconv.ErrRange, or for
+	// the min and max options, an error saying the value is out of bounds.
+	Err error
+}
+
+func (r RangeError) Error() string {
+	return fmt.Sprintf("%v (the minimum is %v and the maximum is %v)",
+		r.Err, r.Min, r.Max)
+}
+
+// Unwrap returns the error produced by strconv.
+func (r RangeError) Unwrap() error {
+	return r.Err
+}
+
+// Code returns "range" (see ErrorCode).
+func (r RangeError) Code() string {
+	return "range"
+}
+
+// ChoiceError is the underlying error of a TypeError returned when a value is
+// not one of the choices given by its field's "oneof" tag option. It gives the
+// choices, so that they can be reported to whoever gave the value.
+type ChoiceError struct {
+	// The value that was given, as parsed.
+	Value string
+	// The values that are allowed, in the order the tag gives them.
+	Choices []string
+}
+
+func (c ChoiceError) Error() string {
+	return fmt.Sprintf("%q is not one of %s", c.Value,
+		strings.Join(c.Choices, ", "))
+}
+
+// Code returns "choice" (see ErrorCode).
+func (c ChoiceError) Code() string {
+	return "choice"
+}
+
 // SingletonError is an error type returned when a parameter is passed multiple
 // times when only a single value is expected. For example, for a struct with
 // integer field "foo", "foo=1&foo=2" will return a SingletonError with key
@@ -40,6 +129,16 @@ func (s SingletonError) Error() string {
 		"value but was given %d: %v", s.Key, len(s.Values), s.Values)
 }
 
+// Is reports whether target is ErrSingleton.
+func (s SingletonError) Is(target error) bool {
+	return target == ErrSingleton
+}
+
+// Code returns "singleton" (see ErrorCode).
+func (s SingletonError) Code() string {
+	return "singleton"
+}
+
 // NestingError is an error type returned when a key is nested when the target
 // type does not support nesting of the given type. For example, deserializing
 // the parameter key "anint[foo]" into a struct that defines an integer param
@@ -59,12 +158,24 @@ func (n NestingError) Error() string {
 		"%q on %s key %q", n.Key+n.Nesting, n.Nesting, n.Type, n.Key)
 }
 
+// Is reports whether target is ErrNesting.
+func (n NestingError) Is(target error) bool {
+	return target == ErrNesting
+}
+
+// Code returns "nesting" (see ErrorCode).
+func (n NestingError) Code() string {
+	return "nesting"
+}
+
 // SyntaxErrorSubtype describes what sort of syntax error was encountered.
 type SyntaxErrorSubtype int
 
 const (
 	MissingOpeningBracket SyntaxErrorSubtype = iota + 1
 	MissingClosingBracket
+	// The Decoder's KeySplitter rejected the key.
+	InvalidKey
 )
 
 // SyntaxError is an error type returned when a key is incorrectly formatted.
@@ -76,22 +187,54 @@ type SyntaxError struct {
 	Subtype SyntaxErrorSubtype
 	// The part of the key (generally the suffix) that was in error.
 	ErrorPart string
+	// The entire key that was being parsed.
+	FullKey string
+	// The byte offset into FullKey at which the error was found. For
+	// MissingOpeningBracket, this is the offset of the character that
+	// should have been an opening bracket, and for MissingClosingBracket,
+	// it is the offset of the opening bracket that was never closed.
+	Offset int
+	// The portion of FullKey, starting at Offset, that was not parsed.
+	Remainder string
+	// For InvalidKey, the error returned by the KeySplitter.
+	Err error
 }
 
 func (s SyntaxError) Error() string {
 	prefix := fmt.Sprintf("param: syntax error while parsing key %q: ",
 		s.Key)
 
+	var msg string
 	switch s.Subtype {
 	case MissingOpeningBracket:
-		return prefix + fmt.Sprintf("expected opening bracket, got %q",
-			s.ErrorPart)
+		msg = fmt.Sprintf("expected opening bracket, got %q", s.ErrorPart)
 	case MissingClosingBracket:
-		return prefix + fmt.Sprintf("expected closing bracket in %q",
-			s.ErrorPart)
+		msg = fmt.Sprintf("expected closing bracket in %q", s.ErrorPart)
+	case InvalidKey:
+		msg = s.Err.Error()
 	default:
 		panic("switch is not exhaustive!")
 	}
+
+	if s.FullKey != "" {
+		msg += fmt.Sprintf(" at offset %d of %q", s.Offset, s.FullKey)
+	}
+	return prefix + msg
+}
+
+// Is reports whether target is ErrSyntax.
+func (s SyntaxError) Is(target error) bool {
+	return target == ErrSyntax
+}
+
+// Code returns "syntax" (see ErrorCode).
+func (s SyntaxError) Code() string {
+	return "syntax"
+}
+
+// Unwrap returns the error returned by the KeySplitter, if any.
+func (s SyntaxError) Unwrap() error {
+	return s.Err
 }
 
 // KeyError is an error type returned when an unknown field is set on a struct.
@@ -104,9 +247,335 @@ type KeyError struct {
 	Type reflect.Type
 	// The name of the field which was not present.
 	Field string
+	// The name of a field the struct does have that is similar enough to
+	// Field that it might have been intended instead, or the empty string
+	// if there is no such field.
+	Suggestion string
 }
 
 func (k KeyError) Error() string {
-	return fmt.Sprintf("param: error parsing key %q: unknown field %q on "+
+	msg := fmt.Sprintf("param: error parsing key %q: unknown field %q on "+
 		"struct %q of type %v", k.FullKey, k.Field, k.Key, k.Type)
+	if k.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", k.Suggestion)
+	}
+	return msg
+}
+
+// Is reports whether target is ErrUnknownKey.
+func (k KeyError) Is(target error) bool {
+	return target == ErrUnknownKey
+}
+
+// Code returns "unknown_key" (see ErrorCode).
+func (k KeyError) Code() string {
+	return "unknown_key"
+}
+
+// RequiredError is an error type returned when a field tagged "required" is
+// not present in the parameters, or when a field tagged "required_if" or
+// "required_with" is neither given nor already set once parsing is complete,
+// and its condition holds.
+type RequiredError struct {
+	// The key that was missing.
+	Key string
+	// The type that was expected for that key.
+	Type reflect.Type
+}
+
+func (r RequiredError) Error() string {
+	return fmt.Sprintf("param: missing required key %q of type %v", r.Key,
+		r.Type)
+}
+
+// Is reports whether target is ErrRequired.
+func (r RequiredError) Is(target error) bool {
+	return target == ErrRequired
+}
+
+// Code returns "required" (see ErrorCode).
+func (r RequiredError) Code() string {
+	return "required"
+}
+
+// ValidationError is an error type returned when a Validator reports that a
+// parsed value is invalid.
+type ValidationError struct {
+	// The key of the value that was invalid. This is the empty string if
+	// the target of the parse itself was invalid.
+	Key string
+	// The type of the value that was invalid.
+	Type reflect.Type
+	// The error returned by the value's Validate method.
+	Err error
+}
+
+func (v ValidationError) Error() string {
+	if v.Key == "" {
+		return fmt.Sprintf("param: invalid %v: %v", v.Type, v.Err)
+	}
+	return fmt.Sprintf("param: invalid value for key %q of type %v: %v",
+		v.Key, v.Type, v.Err)
+}
+
+// Unwrap returns the error returned by the value's Validate method.
+func (v ValidationError) Unwrap() error {
+	return v.Err
+}
+
+// Is reports whether target is ErrValidation.
+func (v ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Code returns "validation" (see ErrorCode).
+func (v ValidationError) Code() string {
+	return "validation"
+}
+
+// ValidationErrors is an error type returned when a Decoder's validation
+// function (see Decoder.SetValidator) reports errors in one or more fields.
+type ValidationErrors []ValidationError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, err := range v {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual errors.
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i, err := range v {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Is reports whether target is ErrValidation.
+func (v ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Code returns "validation" (see ErrorCode).
+func (v ValidationErrors) Code() string {
+	return "validation"
+}
+
+// EncodeError is an error type returned when param has difficulty serializing a
+// value, because its MarshalText method returned an error.
+type EncodeError struct {
+	// The key of the value that could not be serialized.
+	Key string
+	// The type of the value that could not be serialized.
+	Type reflect.Type
+	// The error returned by the value's MarshalText method.
+	Err error
+}
+
+func (e EncodeError) Error() string {
+	return fmt.Sprintf("param: error encoding key %q of type %v: %v", e.Key,
+		e.Type, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e EncodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrEncode.
+func (e EncodeError) Is(target error) bool {
+	return target == ErrEncode
+}
+
+// Code returns "encode" (see ErrorCode).
+func (e EncodeError) Code() string {
+	return "encode"
+}
+
+// LimitError is an error type returned when parameters exceed one of the limits
+// a Decoder was configured with (see, for instance, WithMaxDepth).
+type LimitError struct {
+	// The key that exceeded the limit, or the empty string if the limit
+	// applies to the parameters as a whole.
+	Key string
+	// The limit that was exceeded, such as "depth".
+	Limit string
+	// The maximum the limit allows.
+	Max int
+}
+
+func (l LimitError) Error() string {
+	if l.Key == "" {
+		return fmt.Sprintf("param: too many %s (the maximum is %d)",
+			l.Limit, l.Max)
+	}
+	return fmt.Sprintf("param: error parsing key %q: %s exceeds the "+
+		"maximum of %d", l.Key, l.Limit, l.Max)
+}
+
+// Is reports whether target is ErrLimit.
+func (l LimitError) Is(target error) bool {
+	return target == ErrLimit
+}
+
+// Code returns "limit" (see ErrorCode).
+func (l LimitError) Code() string {
+	return "limit"
+}
+
+// ConflictError is an error type returned when a field with aliases (see the
+// "alias" tag option) is given values under more than one of its names, and by
+// Decoder.ParseSources when several sources give a key different values.
+type ConflictError struct {
+	// The key of the field, under its canonical name.
+	Key string
+	// The keys under which the field was given values, in sorted order.
+	// (For Decoder.ParseSources, this is just Key.)
+	Keys []string
+}
+
+func (c ConflictError) Error() string {
+	return fmt.Sprintf("param: error parsing key %q: conflicting values "+
+		"were given for keys %q", c.Key, c.Keys)
+}
+
+// Is reports whether target is ErrConflict.
+func (c ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Code returns "conflict" (see ErrorCode).
+func (c ConflictError) Code() string {
+	return "conflict"
+}
+
+// FilterError is an error type returned when a Decoder's string filter (see
+// Decoder.SetStringFilter) rejects a value. Values are filtered before they are
+// matched up with the fields they are parsed into, so FilterErrors give only
+// the key.
+type FilterError struct {
+	// The key whose value was rejected.
+	Key string
+	// The error returned by the filter.
+	Err error
+}
+
+func (f FilterError) Error() string {
+	return fmt.Sprintf("param: error parsing key %q: value rejected: %v",
+		f.Key, f.Err)
+}
+
+// Unwrap returns the error returned by the filter.
+func (f FilterError) Unwrap() error {
+	return f.Err
+}
+
+// Is reports whether target is ErrFilter.
+func (f FilterError) Is(target error) bool {
+	return target == ErrFilter
+}
+
+// Code returns "filter" (see ErrorCode).
+func (f FilterError) Code() string {
+	return "filter"
+}
+
+// InvalidParseError is an error type returned when something panics while
+// parsing for reasons that have nothing to do with the parameters: reflect
+// objecting to an unusual target type, for instance, or a bug in a
+// TextUnmarshaler. Rather than crashing the program, such panics are recovered
+// and returned as errors.
+type InvalidParseError struct {
+	// The key being parsed when the panic occurred, or the empty string if
+	// it didn't occur while parsing any particular key.
+	Key string
+	// The type of the field being parsed into, if there is one.
+	Type reflect.Type
+	// The value the code that panicked passed to panic.
+	Value interface{}
+}
+
+func (i InvalidParseError) Error() string {
+	if i.Key == "" {
+		return fmt.Sprintf("param: internal error: %v", i.Value)
+	}
+	return fmt.Sprintf("param: internal error parsing key %q as %v: %v",
+		i.Key, i.Type, i.Value)
+}
+
+// Is reports whether target is ErrInvalid.
+func (i InvalidParseError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Code returns "internal" (see ErrorCode).
+func (i InvalidParseError) Code() string {
+	return "internal"
+}
+
+// Unwrap returns the value passed to panic, if it was an error.
+func (i InvalidParseError) Unwrap() error {
+	err, _ := i.Value.(error)
+	return err
+}
+
+// BatchError describes the failure of a single record passed to ParseBatch.
+type BatchError struct {
+	// The index of the record that failed to parse.
+	Index int
+	// The error that parsing the record produced.
+	Err error
+}
+
+func (b BatchError) Error() string {
+	return fmt.Sprintf("param: error parsing record %d: %v", b.Index, b.Err)
+}
+
+// Unwrap returns the error that parsing the record produced.
+func (b BatchError) Unwrap() error {
+	return b.Err
+}
+
+// Is reports whether target is ErrBatch.
+func (b BatchError) Is(target error) bool {
+	return target == ErrBatch
+}
+
+// Code returns "batch" (see ErrorCode).
+func (b BatchError) Code() string {
+	return "batch"
+}
+
+// BatchErrors is an error type returned by ParseBatch when one or more records
+// fail to parse. The errors are in order of index.
+type BatchErrors []BatchError
+
+func (b BatchErrors) Error() string {
+	msgs := make([]string, len(b))
+	for i, err := range b {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual errors.
+func (b BatchErrors) Unwrap() []error {
+	errs := make([]error, len(b))
+	for i, err := range b {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Is reports whether target is ErrBatch.
+func (b BatchErrors) Is(target error) bool {
+	return target == ErrBatch
+}
+
+// Code returns "batch" (see ErrorCode).
+func (b BatchErrors) Code() string {
+	return "batch"
 }
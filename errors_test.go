@@ -0,0 +1,135 @@
+package param
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestErrorsIs(t *testing.T) {
+	t.Parallel()
+
+	for sentinel, params := range map[error]url.Values{
+		ErrType:       {"Int": {"llama"}},
+		ErrSingleton:  {"Int": {"1", "2"}},
+		ErrNesting:    {"Int[llama]": {"1"}},
+		ErrSyntax:     {"Struct": {"1"}},
+		ErrUnknownKey: {"Llama": {"1"}},
+	} {
+		e := Everything{}
+		err := Parse(params, &e)
+		if !errors.Is(err, sentinel) {
+			t.Errorf("Expected %v to be %v", err, sentinel)
+		}
+		if errors.Is(err, ErrRequired) {
+			t.Errorf("Expected %v not to be %v", err, ErrRequired)
+		}
+	}
+
+	err := Parse(url.Values{}, &Defaulted{})
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("Expected %v to be %v", err, ErrRequired)
+	}
+
+	err = Parse(url.Values{"Range[Min]": {"2"}}, &Validated{})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected %v to be %v", err, ErrValidation)
+	}
+}
+
+func TestErrorsUnwrap(t *testing.T) {
+	t.Parallel()
+
+	e := Everything{}
+	err := Parse(url.Values{"Int": {"99999999999999999999"}}, &e)
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Expected %v to wrap %v", err, strconv.ErrRange)
+	}
+
+	var terr TypeError
+	if !errors.As(err, &terr) {
+		t.Fatalf("Expected %v to be a TypeError", err)
+	}
+	assertEqual(t, "terr.Key", "Int", terr.Key)
+
+	d := NewDecoder()
+	d.SetValidator(func(interface{}) error {
+		return fieldErrors{{"Library.Name", "required"}}
+	})
+	err = d.Parse(url.Values{}, &Library{})
+	var ferr fieldError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("Expected %v to wrap a fieldError", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected %v to be %v", err, ErrValidation)
+	}
+}
+
+func TestSyntaxErrorOffset(t *testing.T) {
+	t.Parallel()
+
+	for key, offset := range map[string]int{
+		"Struct":    6,
+		"Struct[":   6,
+		"Map":       3,
+		"PStruct[A": 7,
+		"PMap[a":    4,
+	} {
+		e := Everything{}
+		err := Parse(url.Values{key: {"1"}}, &e)
+		var serr SyntaxError
+		if !errors.As(err, &serr) {
+			t.Errorf("Expected SyntaxError for %q, got %v", key, err)
+			continue
+		}
+		assertEqual(t, "serr.FullKey", key, serr.FullKey)
+		assertEqual(t, "serr.Offset for "+key, offset, serr.Offset)
+		assertEqual(t, "serr.Remainder for "+key, key[offset:],
+			serr.Remainder)
+	}
+}
+
+type Bounded struct {
+	Small int8    `param:"small"`
+	Byte  uint8   `param:"byte"`
+	Ratio float32 `param:"ratio"`
+}
+
+func TestRangeError(t *testing.T) {
+	t.Parallel()
+
+	for key, bounds := range map[string][2]interface{}{
+		"small": {int64(-128), int64(127)},
+		"byte":  {uint64(0), uint64(255)},
+		"ratio": {-math.MaxFloat32, math.MaxFloat32},
+	} {
+		err := Parse(url.Values{key: {"1e300"}}, &Bounded{})
+		if key != "ratio" {
+			err = Parse(url.Values{key: {"300"}}, &Bounded{})
+		}
+		var rerr RangeError
+		if !errors.As(err, &rerr) {
+			t.Errorf("Expected RangeError for %q, got %v", key, err)
+			continue
+		}
+		assertEqual(t, "rerr.Min for "+key, bounds[0], rerr.Min)
+		assertEqual(t, "rerr.Max for "+key, bounds[1], rerr.Max)
+		if !errors.Is(err, strconv.ErrRange) {
+			t.Errorf("Expected %v to wrap %v", err, strconv.ErrRange)
+		}
+	}
+
+	err := Parse(url.Values{"small": {"-300"}}, &Bounded{})
+	assertEqual(t, "err", `param: error parsing key "small" as int8: `+
+		`strconv.ParseInt: parsing "-300": value out of range `+
+		`(the minimum is -128 and the maximum is 127)`, err.Error())
+
+	// Other errors are left alone.
+	err = Parse(url.Values{"small": {"x"}}, &Bounded{})
+	if errors.As(err, new(RangeError)) {
+		t.Errorf("Expected %v not to be a RangeError", err)
+	}
+}
@@ -0,0 +1,205 @@
+package param
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// WithUnsafeFields returns an Option that makes the Decoder set fields of
+// primitive types (bools, integers, floats, and strings, and named types based
+// on them) by writing to them directly, rather than by way of reflection. For
+// structs made up of such fields, reflection otherwise accounts for much of the
+// time spent parsing. Fields of any other type, fields with tag options that
+// change how they are parsed, and Decoders with options such as
+// WithDecodeHook and WithFillZero that need to see every field, go on using
+// reflection. Either way, the results are the same.
+func WithUnsafeFields() Option {
+	return func(d *Decoder) {
+		d.unsafeFields = true
+	}
+}
+
+// A fastSetter parses values into the field of type t at ptr, as the field's
+// parse function would.
+type fastSetter func(p *parser, key string, t reflect.Type, values []string, ptr unsafe.Pointer)
+
+// Returns the fastSetter for the given struct field, or nil if it must be set
+// with reflection.
+func fastSetterFor(sf reflect.StructField, opts tagOptions) fastSetter {
+	for opt := range opts {
+		switch opt {
+		case "required", "default", "omitempty", "alias", "groups",
+			"required_if", "required_with":
+		default:
+			return nil
+		}
+	}
+	if isLeaf(sf.Type) || isBig(sf.Type) {
+		return nil
+	}
+
+	switch sf.Type.Kind() {
+	case reflect.Bool:
+		return setBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return setInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return setUint
+	case reflect.Float32, reflect.Float64:
+		return setFloat
+	case reflect.String:
+		return setString
+	}
+	return nil
+}
+
+// Set the given field of target, a struct, with its fastSetter, if the Decoder
+// and the key allow it. Reports whether it did.
+func (p *parser) setFast(l cacheLine, key, keytail, path string, values []string, target reflect.Value) bool {
+	if l.fast == nil || keytail != "" || p.hooks != nil || p.fillZero {
+		return false
+	}
+	if p.fields != nil {
+		p.fields[path] = struct{}{}
+	}
+	sf := target.Type().Field(l.offset)
+	ptr := unsafe.Add(unsafe.Pointer(target.UnsafeAddr()), sf.Offset)
+	l.fast(p, key, sf.Type, values, ptr)
+	return true
+}
+
+func setBool(p *parser, key string, t reflect.Type, values []string, ptr unsafe.Pointer) {
+	*(*bool)(ptr) = p.boolOf(key, "", t, values)
+}
+
+func setInt(p *parser, key string, t reflect.Type, values []string, ptr unsafe.Pointer) {
+	i := p.intOf(key, "", t, values)
+	switch t.Kind() {
+	case reflect.Int:
+		*(*int)(ptr) = int(i)
+	case reflect.Int8:
+		*(*int8)(ptr) = int8(i)
+	case reflect.Int16:
+		*(*int16)(ptr) = int16(i)
+	case reflect.Int32:
+		*(*int32)(ptr) = int32(i)
+	case reflect.Int64:
+		*(*int64)(ptr) = i
+	}
+}
+
+func setUint(p *parser, key string, t reflect.Type, values []string, ptr unsafe.Pointer) {
+	i := p.uintOf(key, "", t, values)
+	switch t.Kind() {
+	case reflect.Uint:
+		*(*uint)(ptr) = uint(i)
+	case reflect.Uint8:
+		*(*uint8)(ptr) = uint8(i)
+	case reflect.Uint16:
+		*(*uint16)(ptr) = uint16(i)
+	case reflect.Uint32:
+		*(*uint32)(ptr) = uint32(i)
+	case reflect.Uint64:
+		*(*uint64)(ptr) = i
+	}
+}
+
+func setFloat(p *parser, key string, t reflect.Type, values []string, ptr unsafe.Pointer) {
+	f := p.floatOf(key, "", t, values)
+	if t.Kind() == reflect.Float32 {
+		*(*float32)(ptr) = float32(f)
+	} else {
+		*(*float64)(ptr) = f
+	}
+}
+
+func setString(p *parser, key string, t reflect.Type, values []string, ptr unsafe.Pointer) {
+	value := p.primitive(key, "", t, values)
+	p.alloc(key, len(value))
+	*(*string)(ptr) = value
+}
+
+// A struct is flat if all of its fields are of primitive types with no tag
+// options that change how they are parsed, as is the case for most structs
+// describing the parameters of a request. Flat structs can't be given keys
+// with brackets in them, which allows decodeFields to parse their fields
+// without going through parseStructField. As with isValidatable, we work this
+// out once per type, and for Decoders with their own tag names, which may
+// ignore different fields, once per type in their nameCache.
+var flatLock sync.RWMutex
+var flat = make(map[reflect.Type]bool)
+
+// Returns whether the struct type t, whose cache as d sees it is given, is
+// flat.
+func (d *Decoder) isFlat(t reflect.Type, cache structCache) bool {
+	if n := d.names; n != nil {
+		n.lock.RLock()
+		f, ok := n.flat[t]
+		n.lock.RUnlock()
+		if !ok {
+			f = flatCache(cache)
+			n.lock.Lock()
+			n.flat[t] = f
+			n.lock.Unlock()
+		}
+		return f
+	}
+
+	flatLock.RLock()
+	f, ok := flat[t]
+	flatLock.RUnlock()
+	if !ok {
+		f = flatCache(cache)
+		flatLock.Lock()
+		flat[t] = f
+		flatLock.Unlock()
+	}
+	return f
+}
+
+func flatCache(cache structCache) bool {
+	for _, l := range cache {
+		if l.fast == nil || l.aliases != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether decodeFields may take the fast path for flat structs: the Decoder
+// mustn't have any options that need to see every field.
+func (p *parser) flatPath() bool {
+	return !p.filtered() && p.hooks == nil && !p.fillZero
+}
+
+// Parse the given values into the field of el, a flat struct, described by l,
+// as parseStructField would.
+func (p *parser) setFlat(key string, l cacheLine, values []string, el reflect.Value) {
+	if p.fields != nil {
+		p.fields[key] = struct{}{}
+	}
+	if p.unsafeFields {
+		sf := el.Type().Field(l.offset)
+		l.fast(p, key, sf.Type, values,
+			unsafe.Add(unsafe.Pointer(el.UnsafeAddr()), sf.Offset))
+		return
+	}
+
+	f := el.Field(l.offset)
+	t := f.Type()
+	switch t.Kind() {
+	case reflect.Bool:
+		f.SetBool(p.boolOf(key, "", t, values))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.SetInt(p.intOf(key, "", t, values))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.SetUint(p.uintOf(key, "", t, values))
+	case reflect.Float32, reflect.Float64:
+		f.SetFloat(p.floatOf(key, "", t, values))
+	case reflect.String:
+		value := p.primitive(key, "", t, values)
+		p.alloc(key, len(value))
+		f.SetString(value)
+	}
+}
@@ -0,0 +1,200 @@
+package param
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Flat struct {
+	Bool    bool
+	Int     int
+	Int8    int8
+	Int16   int16
+	Int32   int32
+	Int64   int64
+	Uint    uint
+	Uint8   uint8
+	Uint16  uint16
+	Uint32  uint32
+	Uint64  uint64
+	Float32 float32
+	Float64 float64
+	String  string `param:"string,required"`
+	MyInt   MyInt
+	Trimmed string   `param:"trimmed,trim"`
+	Tags    []string `param:"tags"`
+}
+
+var flatParams = url.Values{
+	"Bool":    {"true"},
+	"Int":     {"-1"},
+	"Int8":    {"-8"},
+	"Int16":   {"-16"},
+	"Int32":   {"-32"},
+	"Int64":   {"-64"},
+	"Uint":    {"1"},
+	"Uint8":   {"8"},
+	"Uint16":  {"16"},
+	"Uint32":  {"32"},
+	"Uint64":  {"64"},
+	"Float32": {"3.5"},
+	"Float64": {"6.25"},
+	"string":  {"hello"},
+	"MyInt":   {"7"},
+	"trimmed": {" x "},
+	"tags[]":  {"a", "b"},
+}
+
+func TestUnsafeFields(t *testing.T) {
+	t.Parallel()
+
+	var slow, fast Flat
+	if err := Parse(flatParams, &slow); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	d := NewDecoder(WithUnsafeFields())
+	if err := d.Parse(flatParams, &fast); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "fast", slow, fast)
+	assertEqual(t, "fast.Int8", int8(-8), fast.Int8)
+	assertEqual(t, "fast.Float32", float32(3.5), fast.Float32)
+
+	for _, params := range []url.Values{
+		{"Int8": {"128"}, "string": {""}},
+		{"Bool": {"maybe"}, "string": {""}},
+		{"Uint": {"1", "2"}, "string": {""}},
+		{"Int[x]": {"1"}, "string": {""}},
+		{},
+	} {
+		var f Flat
+		want := Parse(params, &f)
+		got := d.Parse(params, &f)
+		assertEqual(t, "err", want, got)
+	}
+
+	d = NewDecoder(WithUnsafeFields(), WithEmptyAsZero())
+	fast = Flat{Int: 5}
+	err := d.Parse(url.Values{"Int": {""}, "string": {""}}, &fast)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "fast.Int", 0, fast.Int)
+}
+
+func BenchmarkFlat(b *testing.B) {
+	d := NewDecoder()
+	for i := 0; i < b.N; i++ {
+		var f Flat
+		if err := d.Parse(flatParams, &f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFlatUnsafe(b *testing.B) {
+	d := NewDecoder(WithUnsafeFields())
+	for i := 0; i < b.N; i++ {
+		var f Flat
+		if err := d.Parse(flatParams, &f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParseAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are unreliable under the race detector")
+	}
+	params := url.Values{
+		"Int":       {"1"},
+		"String":    {"a"},
+		"Struct[A]": {"2"},
+		"Struct[B]": {"3"},
+	}
+	var e Everything
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := Parse(params, &e); err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
+type Simple struct {
+	Query  string  `param:"q,required"`
+	Page   int     `param:"page,default=1"`
+	Limit  uint16  `param:"limit"`
+	Exact  bool    `param:"exact"`
+	Weight float32 `param:"weight"`
+}
+
+func TestFlatStructs(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "isFlat(Simple)", true, defaultDecoder.isFlat(
+		reflect.TypeOf(Simple{}), cacheStruct(reflect.TypeOf(Simple{}))))
+	assertEqual(t, "isFlat(Flat)", false, defaultDecoder.isFlat(
+		reflect.TypeOf(Flat{}), cacheStruct(reflect.TypeOf(Flat{}))))
+
+	for _, d := range []*Decoder{NewDecoder(), NewDecoder(WithUnsafeFields())} {
+		var s Simple
+		fields, err := d.ParseWithFields(url.Values{
+			"q":      {"shoes"},
+			"limit":  {"10"},
+			"exact":  {"on"},
+			"weight": {"0.5"},
+		}, &s)
+		if err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "s", Simple{
+			Query:  "shoes",
+			Page:   1,
+			Limit:  10,
+			Exact:  true,
+			Weight: 0.5,
+		}, s)
+		assertEqual(t, "fields", FieldSet{
+			"q": {}, "limit": {}, "exact": {}, "weight": {},
+		}, fields)
+
+		err = d.Parse(url.Values{"q": {"a"}, "limit": {"65536"}}, &s)
+		assertEqual(t, "err", TypeError{
+			Key:  "limit",
+			Type: reflect.TypeOf(uint16(0)),
+			Err: RangeError{
+				Min: uint64(0),
+				Max: uint64(65535),
+				Err: err.(TypeError).Err.(RangeError).Err,
+			},
+		}, err)
+		err = d.Parse(url.Values{"q": {"a"}, "page[x]": {"1"}}, &s)
+		if _, ok := err.(NestingError); !ok {
+			t.Errorf("Expected NestingError, got %v", err)
+		}
+		err = d.Parse(url.Values{"nope": {"1"}}, &s)
+		if _, ok := err.(KeyError); !ok {
+			t.Errorf("Expected KeyError, got %v", err)
+		}
+	}
+}
+
+func BenchmarkSimple(b *testing.B) {
+	params := url.Values{
+		"q":      {"shoes"},
+		"page":   {"2"},
+		"limit":  {"10"},
+		"exact":  {"on"},
+		"weight": {"0.5"},
+	}
+	for i := 0; i < b.N; i++ {
+		var s Simple
+		if err := Parse(params, &s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
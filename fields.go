@@ -0,0 +1,193 @@
+package param
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A FieldSet is the set of struct fields that were given values by a call to
+// ParseWithFields, identified by their keys, such as "address[city]". A nested
+// field's enclosing fields are members of the set too, so if "address[city]" is
+// in the set, so is "address". Fields set from their default values are not
+// members of the set. Fields given by an alias (see the "alias" tag option) are
+// members by their canonical keys, so if "per_page" is an alias of "limit",
+// giving "per_page" adds "limit" to the set.
+type FieldSet map[string]struct{}
+
+// Has reports whether the field with the given key was given a value.
+func (s FieldSet) Has(key string) bool {
+	_, ok := s[key]
+	return ok
+}
+
+// Keys returns the keys of the fields in the set, in sorted order.
+func (s FieldSet) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Columns returns the values of the top-level fields in the set, as found in
+// the struct pointed to by target, keyed by the column names given in the
+// fields' tag with the given name (such as "db"). The name may be followed by
+// a comma and options, which are ignored. Fields with no such tag, or with the
+// name "-", are omitted. Fields promoted from embedded structs are included,
+// whether they were given directly or by way of the embedded struct's key.
+// This is intended to be used with ParseWithFields to build partial UPDATE
+// statements:
+//
+//	fields, err := param.ParseWithFields(params, &user)
+//	...
+//	cols, err := fields.Columns(&user, "db")
+//
+// Fields are named as the package-level functions name them. Sets returned by
+// Decoders that name fields differently should be given to Decoder.Columns.
+func (s FieldSet) Columns(target interface{}, tag string) (map[string]interface{}, error) {
+	return defaultDecoder.columns("param.FieldSet.Columns", s, target, tag)
+}
+
+// Columns is like FieldSet.Columns, but names fields as the Decoder does, as it
+// must for the sets returned by Decoders given WithTagNames or SetNameMapper.
+func (d *Decoder) Columns(fields FieldSet, target interface{}, tag string) (map[string]interface{}, error) {
+	return d.columns("param.Decoder.Columns", fields, target, tag)
+}
+
+func (d *Decoder) columns(fn string, s FieldSet, target interface{}, tag string) (cols map[string]interface{}, err error) {
+	defer recoverError(&err)
+
+	el := targetStruct(fn, target)
+	t := el.Type()
+
+	cols = make(map[string]interface{})
+	d.addColumns(cols, s, "", el, tag, make(map[string]bool), []reflect.Type{t})
+	return cols, nil
+}
+
+// Adds the columns of the fields of v, a struct, that are in s to cols. The
+// fields of its embedded structs are added too, just as they are promoted when
+// parsing (see embed.go), whether they were given directly or by way of the
+// embedded struct's key, which is key. taken holds the names of the fields that
+// shadow them, and seen the types we have already looked in, in case of cycles.
+func (d *Decoder) addColumns(cols map[string]interface{}, s FieldSet, key string, v reflect.Value, tag string, taken map[string]bool, seen []reflect.Type) {
+	t := v.Type()
+	cache := d.cached(t)
+	var names []string
+	for name := range cache {
+		if !taken[name] {
+			taken[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return cache[names[i]].offset < cache[names[j]].offset
+	})
+
+	for _, name := range names {
+		l := cache[name]
+		if !s.Has(name) && (key == "" || !s.Has(key+"["+name+"]")) {
+			continue
+		}
+		col := t.Field(l.offset).Tag.Get(tag)
+		if i := strings.IndexByte(col, ','); i >= 0 {
+			col = col[:i]
+		}
+		if col == "" || col == "-" {
+			continue
+		}
+		cols[col] = v.Field(l.offset).Interface()
+	}
+
+	for _, name := range names {
+		l := cache[name]
+		if !l.promoted {
+			continue
+		}
+		f := v.Field(l.offset)
+		if f.Kind() == reflect.Ptr {
+			if f.IsNil() {
+				continue
+			}
+			f = f.Elem()
+		}
+		found := false
+		for _, st := range seen {
+			found = found || st == f.Type()
+		}
+		if found {
+			continue
+		}
+		ekey := name
+		if key != "" {
+			ekey = key + "[" + name + "]"
+		}
+		d.addColumns(cols, s, ekey, f, tag, taken, append(seen, f.Type()))
+	}
+}
+
+// ParseWithFields is like Parse, but also returns the set of fields that were
+// given values. This allows fields that were given their zero values to be
+// told apart from fields that were not given at all, as PATCH endpoints need.
+func ParseWithFields(params url.Values, target interface{}) (FieldSet, error) {
+	return defaultDecoder.parseWithFields("param.ParseWithFields", params,
+		target)
+}
+
+// ParseWithFields is like Parse, but also returns the set of fields that were
+// given values.
+func (d *Decoder) ParseWithFields(params url.Values, target interface{}) (FieldSet, error) {
+	return d.parseWithFields("param.Decoder.ParseWithFields", params, target)
+}
+
+// A key of a field given by an alias, or nested within one, and the same key
+// with the aliases replaced by the names they alias.
+type aliasedKey struct {
+	key, canonical string
+}
+
+// Returns the canonical key of the field with the given key, whose own name as
+// given is sk and whose canonical name is name, remembering it if it differs, so
+// that the fields nested within it have canonical keys too.
+func (p *parser) canonicalField(path, sk, name string) string {
+	if len(p.aliased) == 0 && name == sk {
+		return path
+	}
+	prefix := structPrefix(path, sk)
+	if strings.HasSuffix(prefix, "[") {
+		prefix = p.canonicalPath(prefix[:len(prefix)-1]) + "["
+	}
+	canonical := subkey(prefix, name)
+	if canonical != path && p.canonicalPath(path) != canonical {
+		p.aliased = append(p.aliased, aliasedKey{path, canonical})
+	}
+	return canonical
+}
+
+// Returns the given key with the aliases of the fields it is nested within
+// replaced by their canonical names.
+func (p *parser) canonicalPath(path string) string {
+	// Later entries are nested deeper, so we try them first.
+	for i := len(p.aliased) - 1; i >= 0; i-- {
+		a := p.aliased[i]
+		if path == a.key {
+			return a.canonical
+		}
+		if rest, ok := strings.CutPrefix(path, a.key); ok && rest[0] == '[' {
+			return a.canonical + rest
+		}
+	}
+	return path
+}
+
+func (d *Decoder) parseWithFields(fn string, params url.Values, target interface{}) (FieldSet, error) {
+	p := d.newParser(context.Background())
+	defer p.release()
+	p.fields = make(FieldSet)
+	err := d.run(p, fn, params, target)
+	return p.fields, err
+}
@@ -0,0 +1,170 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Patch struct {
+	Name    string  `param:"name"`
+	Age     int     `param:"age"`
+	Limit   int     `param:"limit,default=25"`
+	Address Address `param:"address"`
+	Items   []Sub   `param:"items"`
+}
+
+type Address struct {
+	City string `param:"city"`
+	Zip  string `param:"zip"`
+}
+
+func TestParseWithFields(t *testing.T) {
+	t.Parallel()
+
+	p := Patch{Name: "Alice"}
+	fields, err := ParseWithFields(url.Values{
+		"age":           {"0"},
+		"address[city]": {""},
+		"items[1][A]":   {"1"},
+	}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Name", "Alice", p.Name)
+	assertEqual(t, "p.Limit", 25, p.Limit)
+	assertEqual(t, "fields", []string{
+		"address", "address[city]", "age", "items", "items[1][A]",
+	}, fields.Keys())
+	assertEqual(t, "fields.Has(age)", true, fields.Has("age"))
+	assertEqual(t, "fields.Has(name)", false, fields.Has("name"))
+	assertEqual(t, "fields.Has(limit)", false, fields.Has("limit"))
+
+	fields, err = NewDecoder().ParseWithFields(url.Values{
+		"age": {"llama"},
+	}, &p)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+	assertEqual(t, "fields", []string{"age"}, fields.Keys())
+}
+
+type UserRow struct {
+	ID    int64   `param:"-" db:"id"`
+	Name  string  `param:"name" db:"name"`
+	Email *string `param:"email" db:"email,omitempty"`
+	Age   int     `param:"age" db:"-"`
+	Notes string  `param:"notes"`
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+
+	u := UserRow{ID: 7, Name: "Alice"}
+	fields, err := ParseWithFields(url.Values{
+		"email": {"bob@example.com"},
+		"age":   {"30"},
+		"notes": {"hi"},
+	}, &u)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	cols, err := fields.Columns(&u, "db")
+	if err != nil {
+		t.Fatal("Columns error: ", err)
+	}
+	assertEqual(t, "cols", map[string]interface{}{"email": u.Email}, cols)
+}
+
+type PageRow struct {
+	Limit  int     `param:"limit,alias=per_page" db:"limit"`
+	Filter Renamed `param:"filter,alias=f"`
+}
+
+func TestFieldsAliases(t *testing.T) {
+	t.Parallel()
+
+	r := PageRow{}
+	fields, err := ParseWithFields(url.Values{
+		"per_page": {"10"},
+		"f[title]": {"x"},
+	}, &r)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "fields", []string{"filter", "filter[name]", "limit"},
+		fields.Keys())
+
+	cols, err := fields.Columns(&r, "db")
+	if err != nil {
+		t.Fatal("Columns error: ", err)
+	}
+	assertEqual(t, "cols", map[string]interface{}{"limit": 10}, cols)
+}
+
+type MappedRow struct {
+	UserID int    `db:"user_id"`
+	Name   string `db:"name"`
+}
+
+func TestDecoderColumns(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	d.SetNameMapper(SnakeCase)
+	r := MappedRow{Name: "Alice"}
+	fields, err := d.ParseWithFields(url.Values{"user_id": {"7"}}, &r)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	cols, err := d.Columns(fields, &r, "db")
+	if err != nil {
+		t.Fatal("Columns error: ", err)
+	}
+	assertEqual(t, "cols", map[string]interface{}{"user_id": 7}, cols)
+
+	// The package-level names don't know about user_id.
+	cols, err = fields.Columns(&r, "db")
+	if err != nil {
+		t.Fatal("Columns error: ", err)
+	}
+	assertEqual(t, "cols", map[string]interface{}{}, cols)
+}
+
+type Located struct {
+	City    string `db:"city"`
+	Country string `db:"country"`
+}
+
+type Stamped struct {
+	Editor string `db:"editor"`
+	City   string `db:"stamped_city"`
+}
+
+type Tenant struct {
+	ID int `db:"id"`
+	Located
+	*Stamped
+}
+
+func TestColumnsPromoted(t *testing.T) {
+	t.Parallel()
+
+	var r Tenant
+	fields, err := ParseWithFields(url.Values{
+		"City":             {"Paris"},
+		"Located[Country]": {"FR"},
+		"Editor":           {"bob"},
+	}, &r)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	cols, err := fields.Columns(&r, "db")
+	if err != nil {
+		t.Fatal("Columns error: ", err)
+	}
+	assertEqual(t, "cols", map[string]interface{}{
+		"city":    "Paris",
+		"country": "FR",
+		"editor":  "bob",
+	}, cols)
+}
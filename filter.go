@@ -0,0 +1,84 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+)
+
+// KeyInfo describes a struct field that parameters may be parsed into.
+type KeyInfo struct {
+	// The key that addresses the field, such as "address[city]".
+	Key string
+	// The name of the field, as derived from its struct tags, such as
+	// "city".
+	Name string
+	// The type of the struct the field belongs to.
+	Struct reflect.Type
+	// The field itself.
+	Field reflect.StructField
+}
+
+// WithFieldFilter returns an Option that allows fields to be enabled and
+// disabled at runtime, for instance by feature flags. Whenever a field is about
+// to be parsed into, filter is called with a description of the field, and if it
+// returns false, the field is treated as though it did not exist: parameters
+// that address it are unknown keys, and its required and default options are
+// ignored. filter may be called several times per field in each parse, and from
+// several goroutines at once.
+func WithFieldFilter(filter func(field KeyInfo) bool) Option {
+	return func(d *Decoder) {
+		d.fieldFilter = filter
+	}
+}
+
+// Reports whether the given field, which has the given key, of the given struct
+// type, is enabled according to the Decoder's field filter, its Permit, Only,
+// and Except lists, and its group.
+func (p *parser) fieldEnabled(key string, t reflect.Type, name string, l cacheLine) bool {
+	field := KeyInfo{
+		Key:    key,
+		Name:   name,
+		Struct: t,
+		Field:  t.Field(l.offset),
+	}
+	if p.permitted != nil && !p.permits(key) ||
+		p.excludes(key, field.Field.Name) || !p.inGroup(l) {
+		return false
+	}
+	return p.fieldFilter == nil || p.fieldFilter(field)
+}
+
+// Reports whether the setter bound to the given key, whose name is sk, is
+// enabled according to the Decoder's Permit, Only, and Except lists.
+func (p *parser) setterEnabled(key, sk string) bool {
+	if p.permitted != nil && !p.permits(key) {
+		return false
+	}
+	return !p.excludes(key, sk)
+}
+
+// Reports whether any fields might be disabled, whether by a field filter, or
+// by Permit, Only, Except, or WithGroup.
+func (d *Decoder) filtered() bool {
+	return d.fieldFilter != nil || d.permitted != nil || d.only != nil ||
+		d.except != nil || d.group != ""
+}
+
+// Returns the prefix of the given key of a struct field with the given name,
+// for use with subkey.
+func structPrefix(key, name string) string {
+	if strings.HasSuffix(key, "]") {
+		return key[:len(key)-len(name)-1]
+	}
+	return key[:len(key)-len(name)]
+}
+
+// Returns the key of the field with the given name within the struct that has
+// the given prefix, which is either empty or ends in an opening bracket, except
+// that the names of flattened structs (see the "flatten" tag option) may follow.
+func subkey(prefix, name string) string {
+	if !strings.Contains(prefix, "[") {
+		return prefix + name
+	}
+	return prefix + name + "]"
+}
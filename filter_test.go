@@ -0,0 +1,68 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Flagged struct {
+	Name    string `param:"name"`
+	Beta    string `param:"beta,required"`
+	Limit   int    `param:"limit,default=10"`
+	Address struct {
+		City  string `param:"city"`
+		Beta  string `param:"beta"`
+		Betas string `param:"betas"`
+	} `param:"address"`
+}
+
+func noBeta(field KeyInfo) bool {
+	return field.Name != "beta" && field.Key != "limit"
+}
+
+func TestFieldFilter(t *testing.T) {
+	t.Parallel()
+
+	var keys []string
+	d := NewDecoder(WithFieldFilter(func(field KeyInfo) bool {
+		keys = append(keys, field.Key)
+		return noBeta(field)
+	}))
+
+	f := Flagged{}
+	err := d.Parse(url.Values{"address[city]": {"Paris"}}, &f)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "f.Address.City", "Paris", f.Address.City)
+	assertEqual(t, "f.Limit", 0, f.Limit)
+	for _, key := range []string{"address", "address[city]", "beta"} {
+		found := false
+		for _, k := range keys {
+			found = found || k == key
+		}
+		if !found {
+			t.Errorf("Expected filter to be called for %q", key)
+		}
+	}
+}
+
+func TestFieldFilterErrors(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithFieldFilter(noBeta))
+	for key, suggestion := range map[string]string{
+		"beta":          "",
+		"address[beta]": "betas",
+	} {
+		f := Flagged{}
+		err := d.Parse(url.Values{key: {"1"}}, &f)
+		kerr, ok := err.(KeyError)
+		if !ok {
+			t.Errorf("Expected KeyError for %q, got %v", key, err)
+			continue
+		}
+		assertEqual(t, "suggestion for "+key, suggestion,
+			kerr.Suggestion)
+	}
+}
@@ -0,0 +1,39 @@
+package param
+
+import (
+	"flag"
+	"reflect"
+)
+
+var flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+
+// Types implementing flag.Value, but neither encoding.TextUnmarshaler nor
+// ContextTextUnmarshaler, are parsed with their Set methods, and encoded with
+// their String methods. This allows types written for the flag package to be
+// used as they are.
+func parseFlagValue(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+
+	fv := target.Addr().Interface().(flag.Value)
+	if err := fv.Set(value); err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  err,
+		})
+	}
+}
+
+// Encodes the given value with its String method, if it implements flag.Value.
+func encodeFlagValue(v reflect.Value) (string, bool) {
+	t := v.Type()
+	if !reflect.PtrTo(t).Implements(flagValueType) {
+		return "", false
+	}
+	if v.CanAddr() {
+		return v.Addr().Interface().(flag.Value).String(), true
+	}
+	c := reflect.New(t)
+	c.Elem().Set(v)
+	return c.Interface().(flag.Value).String(), true
+}
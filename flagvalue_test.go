@@ -0,0 +1,82 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Level implements flag.Value, but not encoding.TextUnmarshaler.
+type Level int
+
+func (l *Level) String() string {
+	return [...]string{"debug", "info", "error"}[*l]
+}
+
+func (l *Level) Set(s string) error {
+	switch s {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+// StringList implements flag.Value by splitting on commas.
+type StringList []string
+
+func (s *StringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *StringList) Set(v string) error {
+	*s = strings.Split(v, ",")
+	return nil
+}
+
+type Logging struct {
+	Level  Level      `param:"level,default=info"`
+	Levels []Level    `param:"levels"`
+	Tags   StringList `param:"tags"`
+	PLevel *Level     `param:"plevel"`
+}
+
+func TestFlagValue(t *testing.T) {
+	t.Parallel()
+
+	l := Logging{}
+	err := Parse(url.Values{
+		"levels[]": {"debug", "error"},
+		"tags":     {"a,b"},
+		"plevel":   {"error"},
+	}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l.Level", Level(1), l.Level)
+	assertEqual(t, "l.Levels", []Level{0, 2}, l.Levels)
+	assertEqual(t, "l.Tags", StringList{"a", "b"}, l.Tags)
+	assertEqual(t, "*l.PLevel", Level(2), *l.PLevel)
+
+	params, err := Encode(l)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{
+		"level":    {"info"},
+		"levels[]": {"debug", "error"},
+		"tags":     {"a,b"},
+		"plevel":   {"error"},
+	}, params)
+
+	err = Parse(url.Values{"level": {"loud"}}, &l)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package param
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The "flatten" tag option, as in `param:"address_,flatten"`, allows the fields
+// of a nested struct (or pointer to a struct) to be given as flat keys made up
+// of the field's name followed by the name of the nested field: "address_city"
+// is treated like "address_[city]", which may also still be used. This suits
+// HTML forms, whose inputs often have flat names. Fields of the outer struct
+// take precedence over flattened fields, and fields declared earlier over those
+// declared later.
+
+// Complain if the given field of struct s has the flatten option but isn't a
+// struct.
+func checkFlatten(s reflect.Type, sf reflect.StructField) {
+	if st := structType(sf.Type); st.Kind() != reflect.Struct || isLeaf(st) {
+		pebkac("struct %v field %q has the flatten option, but is of "+
+			"type %v, not a struct or a pointer to one.", s, sf.Name,
+			sf.Type)
+	}
+}
+
+// Returns the cache lines of the flattened fields of the given cache, along
+// with their names, in the order they are declared.
+func flattened(cache structCache) ([]string, []cacheLine) {
+	var names []string
+	for name, l := range cache {
+		if l.opts.has("flatten") {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return cache[names[i]].offset < cache[names[j]].offset
+	})
+	lines := make([]cacheLine, len(names))
+	for i, name := range names {
+		lines[i] = cache[name]
+	}
+	return names, lines
+}
+
+// Finds the flattened struct within target, a struct with the given cache, that
+// has a field named by the flat name sk. Returns the flattened struct's cache
+// and value, allocating a pointer to it if necessary, and the name of the field
+// within it.
+func (p *parser) flattened(cache structCache, sk string, target reflect.Value) (structCache, reflect.Value, string, bool) {
+	names, lines := flattened(cache)
+	for i, l := range lines {
+		rest, ok := strings.CutPrefix(sk, names[i])
+		if !ok || rest == "" {
+			continue
+		}
+		ft := structType(target.Type().Field(l.offset).Type)
+		if !p.hasFlattened(ft, rest) {
+			continue
+		}
+
+		f := target.Field(l.offset)
+		if f.Kind() == reflect.Ptr {
+			if f.IsNil() {
+				f.Set(reflect.New(ft))
+			}
+			f = f.Elem()
+		}
+		return p.cached(ft), f, rest, true
+	}
+	return nil, reflect.Value{}, "", false
+}
+
+// Reports whether the struct type t has a field named sk, either directly or as
+// a flat name within its flattened fields. Since the names of flattened fields
+// are never empty, this terminates even for recursive types.
+func (p *parser) hasFlattened(t reflect.Type, sk string) bool {
+	cache := p.cached(t)
+	if _, _, ok := cache.lookup(sk); ok {
+		return true
+	}
+	names, lines := flattened(cache)
+	for i, l := range lines {
+		rest, ok := strings.CutPrefix(sk, names[i])
+		if ok && rest != "" &&
+			p.hasFlattened(structType(t.Field(l.offset).Type), rest) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Geo struct {
+	Lat float64 `param:"lat"`
+	Lng float64 `param:"lng"`
+}
+
+type Location struct {
+	Street string `param:"street"`
+	Zip    string `param:"zip,alias=postcode"`
+	Geo    *Geo   `param:"geo_,flatten"`
+}
+
+type SignupForm struct {
+	Name    string    `param:"name"`
+	Home    Location  `param:"home_,flatten"`
+	Work    *Location `param:"work_,flatten"`
+	HomeZip string    `param:"home_zip"`
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	f := SignupForm{}
+	err := Parse(url.Values{
+		"name":             {"Alice"},
+		"home_street":      {"1 Main St"},
+		"home_geo_lat":     {"1.5"},
+		"home_[geo_][lng]": {"2.5"},
+		"home_zip":         {"12345"},
+		"work_postcode":    {"54321"},
+	}, &f)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "f.Home.Street", "1 Main St", f.Home.Street)
+	assertEqual(t, "f.Home.Geo", &Geo{Lat: 1.5, Lng: 2.5}, f.Home.Geo)
+	// Fields of the outer struct take precedence.
+	assertEqual(t, "f.HomeZip", "12345", f.HomeZip)
+	assertEqual(t, "f.Home.Zip", "", f.Home.Zip)
+	assertEqual(t, "f.Work.Zip", "54321", f.Work.Zip)
+
+	err = Parse(url.Values{"home_llama": {"1"}}, &SignupForm{})
+	if kerr, ok := err.(KeyError); !ok || kerr.Key != "home_llama" {
+		t.Errorf("Expected KeyError for home_llama, got %v", err)
+	}
+
+	// Aliases in flattened structs still conflict.
+	err = Parse(url.Values{
+		"work_zip":      {"1"},
+		"work_postcode": {"2"},
+	}, &SignupForm{})
+	assertEqual(t, "err", ConflictError{
+		Key:  "work_zip",
+		Keys: []string{"work_postcode", "work_zip"},
+	}, err)
+
+	// Flattened structs may themselves be nested.
+	s := struct {
+		Signup SignupForm `param:"signup"`
+	}{}
+	err = Parse(url.Values{
+		"signup[work_geo_lng]":  {"3"},
+		"signup[work_zip]":      {"1"},
+		"signup[work_postcode]": {"2"},
+	}, &s)
+	assertEqual(t, "err", ConflictError{
+		Key:  "signup[work_zip]",
+		Keys: []string{"signup[work_postcode]", "signup[work_zip]"},
+	}, err)
+	assertEqual(t, "s.Signup.Work.Geo.Lng", 3.0, s.Signup.Work.Geo.Lng)
+}
@@ -0,0 +1,59 @@
+package param
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorCode returns a short, stable, machine-readable code for the kind of the
+// given error, or the empty string if it is not (and does not wrap) one of this
+// package's errors. The codes are "type", "range", "choice", "singleton",
+// "nesting", "syntax", "unknown_key", "required", "validation", "encode",
+// "limit", "conflict", "filter", "batch", and "internal", one for each kind of
+// error, and will not change, so they may be used, for instance, to look up
+// translations of error messages.
+//
+// Errors are examined outermost first: a TypeError whose underlying error is a
+// RangeError has code "type". Use errors.As to look deeper.
+func ErrorCode(err error) string {
+	var c interface{ Code() string }
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+	return ""
+}
+
+// SetErrorFormatter sets the function FormatError uses to render errors for
+// display, for instance in a user's language. format is passed each error and
+// the language it is to be rendered in, in whatever form the caller of
+// FormatError chooses (such as a BCP 47 tag like "fr-CA"). The formatter may
+// return the empty string to fall back on the error's own message.
+//
+// Formatters typically switch on ErrorCode, using errors.As to get at the
+// details of the error, such as the key it concerns.
+//
+// SetErrorFormatter must not be called once the Decoder is in use.
+func (d *Decoder) SetErrorFormatter(format func(err error, lang string) string) {
+	d.formatError = format
+}
+
+// FormatError renders the given error, as returned by the Decoder, in the given
+// language using the Decoder's error formatter (see SetErrorFormatter), or as
+// err.Error() if it has none. Errors made up of several errors, such as
+// ValidationErrors, are rendered one at a time, and the results joined with
+// semicolons.
+func (d *Decoder) FormatError(err error, lang string) string {
+	if errs, ok := err.(interface{ Unwrap() []error }); ok {
+		msgs := make([]string, 0, len(errs.Unwrap()))
+		for _, err := range errs.Unwrap() {
+			msgs = append(msgs, d.FormatError(err, lang))
+		}
+		return strings.Join(msgs, "; ")
+	}
+	if d.formatError != nil {
+		if msg := d.formatError(err, lang); msg != "" {
+			return msg
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,66 @@
+package param
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	t.Parallel()
+
+	for code, params := range map[string]url.Values{
+		"type":        {"Int": {"llama"}},
+		"singleton":   {"Int": {"1", "2"}},
+		"nesting":     {"Int[llama]": {"1"}},
+		"syntax":      {"Struct": {"1"}},
+		"unknown_key": {"Llama": {"1"}},
+	} {
+		err := Parse(params, &Everything{})
+		assertEqual(t, "ErrorCode for "+code, code, ErrorCode(err))
+	}
+
+	err := Parse(url.Values{"small": {"300"}}, &Bounded{})
+	assertEqual(t, "ErrorCode", "type", ErrorCode(err))
+	var terr TypeError
+	errors.As(err, &terr)
+	assertEqual(t, "ErrorCode", "range", ErrorCode(terr.Err))
+
+	err = fmt.Errorf("wrapped: %w", RequiredError{Key: "a"})
+	assertEqual(t, "ErrorCode", "required", ErrorCode(err))
+	assertEqual(t, "ErrorCode", "", ErrorCode(errors.New("llama")))
+}
+
+func TestFormatError(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	err := d.Parse(url.Values{"Int": {"llama"}}, &Everything{})
+	assertEqual(t, "FormatError", err.Error(), d.FormatError(err, "fr"))
+
+	d.SetErrorFormatter(func(err error, lang string) string {
+		var terr TypeError
+		if lang != "fr" || !errors.As(err, &terr) {
+			return ""
+		}
+		return fmt.Sprintf("valeur invalide pour %q", terr.Key)
+	})
+	assertEqual(t, "FormatError", `valeur invalide pour "Int"`,
+		d.FormatError(err, "fr"))
+	assertEqual(t, "FormatError", err.Error(), d.FormatError(err, "en"))
+
+	errs := ValidationErrors{
+		{Key: "a", Err: errors.New("bad")},
+		{Key: "b", Err: errors.New("worse")},
+	}
+	d.SetErrorFormatter(func(err error, lang string) string {
+		return ErrorCode(err) + ":" + err.(ValidationError).Key
+	})
+	assertEqual(t, "FormatError", "validation:a; validation:b",
+		d.FormatError(errs, "en"))
+	assertEqual(t, "ErrorCode", "validation", ErrorCode(errs))
+	if !errors.Is(errs, ErrValidation) {
+		t.Errorf("Expected %v to be ErrValidation", errs)
+	}
+}
@@ -0,0 +1,38 @@
+package param
+
+import "strings"
+
+// WithGroupSeparators returns an Option that allows the digits of numbers to be
+// grouped with any of the given separators, as in "1_000_000" (for a seps of
+// "_") or "1,000" (for ","). Separators are only accepted between two digits.
+// seps must not contain the decimal separator.
+func WithGroupSeparators(seps string) Option {
+	return func(d *Decoder) {
+		d.groupSeps = seps
+	}
+}
+
+// Remove group separators from the given number. If any of them is out of
+// place, the number is returned as is, so that strconv rejects it.
+func (d *Decoder) ungroup(v string) string {
+	if d.groupSeps == "" || strings.IndexAny(v, d.groupSeps) < 0 {
+		return v
+	}
+
+	var b strings.Builder
+	b.Grow(len(v))
+	for i := 0; i < len(v); i++ {
+		if strings.IndexByte(d.groupSeps, v[i]) < 0 {
+			b.WriteByte(v[i])
+			continue
+		}
+		if i == 0 || i == len(v)-1 || !isDigit(v[i-1]) || !isDigit(v[i+1]) {
+			return v
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
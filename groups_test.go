@@ -0,0 +1,43 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestGroupSeparators(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithGroupSeparators("_,"))
+	e := Everything{}
+	err := d.Parse(url.Values{
+		"Int":   {"-1_000_000"},
+		"Uint":  {"1,000"},
+		"Float": {"12,345.5"},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", -1000000, e.Int)
+	assertEqual(t, "e.Uint", uint(1000), e.Uint)
+	assertEqual(t, "e.Float", 12345.5, e.Float)
+
+	for _, value := range []string{"_1", "1_", "1__0", "1,_0", "-_1"} {
+		err := d.Parse(url.Values{"Int": {value}}, &Everything{})
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %q, got %v", value, err)
+		}
+	}
+
+	// Points group digits in locales with decimal commas
+	d = NewDecoder(WithGroupSeparators("."), WithDecimalComma())
+	if err := d.Parse(url.Values{"Float": {"1.000,5"}}, &e); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Float", 1000.5, e.Float)
+
+	err = Parse(url.Values{"Int": {"1_000"}}, &Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
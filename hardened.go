@@ -0,0 +1,132 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithMaxDepth returns an Option that limits how deeply keys may be nested:
+// keys with more than n bracketed components, such as "a[b][c]" when n is 1,
+// are rejected with a LimitError.
+func WithMaxDepth(n int) Option {
+	return func(d *Decoder) {
+		d.maxDepth = n
+	}
+}
+
+// WithMaxKeys returns an Option that rejects parameters with more than n
+// distinct keys with a LimitError.
+func WithMaxKeys(n int) Option {
+	return func(d *Decoder) {
+		d.maxKeys = n
+	}
+}
+
+// WithMaxValueLength returns an Option that rejects values longer than n bytes
+// with a LimitError.
+func WithMaxValueLength(n int) Option {
+	return func(d *Decoder) {
+		d.maxValueLength = n
+	}
+}
+
+// WithStrictUTF8 returns an Option that rejects keys and values that are not
+// valid UTF-8 with a TypeError, instead of passing them through to string
+// fields as is.
+func WithStrictUTF8() Option {
+	return func(d *Decoder) {
+		d.strictUTF8 = true
+	}
+}
+
+var errInvalidUTF8 = errors.New("invalid UTF-8")
+
+var stringType = reflect.TypeOf("")
+
+// The limits NewHardenedDecoder imposes.
+const (
+	hardenedMaxDepth       = 10
+	hardenedMaxKeys        = 1000
+	hardenedMaxValueLength = 64 << 10
+)
+
+// NewHardenedDecoder returns a new Decoder configured with options recommended
+// for parsing parameters from untrusted sources, followed by the given options,
+// which may override them. A hardened Decoder:
+//
+//   - rejects keys nested more than 10 levels deep, more than 1000 distinct
+//     keys, and values longer than 64KiB (see WithMaxDepth, WithMaxKeys, and
+//     WithMaxValueLength);
+//   - rejects invalid UTF-8 (see WithStrictUTF8);
+//   - redacts likely credentials from captured failures (see WithRedaction
+//     and RedactSecrets).
+//
+// Like every Decoder, it also rejects unknown keys.
+func NewHardenedDecoder(opts ...Option) *Decoder {
+	hardened := []Option{
+		WithMaxDepth(hardenedMaxDepth),
+		WithMaxKeys(hardenedMaxKeys),
+		WithMaxValueLength(hardenedMaxValueLength),
+		WithStrictUTF8(),
+		WithRedaction(RedactSecrets),
+	}
+	return NewDecoder(append(hardened, opts...)...)
+}
+
+// Substrings of the keys RedactSecrets redacts.
+var secretKeys = []string{
+	"password", "passwd", "secret", "token", "apikey", "api_key",
+	"authorization", "credential",
+}
+
+// RedactSecrets is a redaction policy (see WithRedaction) that redacts the
+// values of keys that look like they might hold credentials, such as
+// "password" or "user[api_key]". Keys are matched case-insensitively.
+func RedactSecrets(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check the given parameters against the Decoder's limits.
+func (d *Decoder) checkLimits(params url.Values) {
+	if d.maxKeys > 0 && len(params) > d.maxKeys {
+		panic(LimitError{Limit: "keys", Max: d.maxKeys})
+	}
+	if d.maxDepth <= 0 && d.maxValueLength <= 0 && !d.strictUTF8 {
+		return
+	}
+	// We go in order so that the same parameters always give the same error.
+	for _, key := range sortedKeys(params) {
+		values := params[key]
+		if d.maxDepth > 0 && strings.Count(key, "[") > d.maxDepth {
+			panic(LimitError{Key: key, Limit: "depth", Max: d.maxDepth})
+		}
+		if d.strictUTF8 && !utf8.ValidString(key) {
+			panic(TypeError{Key: key, Type: stringType, Err: errInvalidUTF8})
+		}
+		for _, v := range values {
+			if d.maxValueLength > 0 && len(v) > d.maxValueLength {
+				panic(LimitError{
+					Key:   key,
+					Limit: "value length",
+					Max:   d.maxValueLength,
+				})
+			}
+			if d.strictUTF8 && !utf8.ValidString(v) {
+				panic(TypeError{
+					Key:  key,
+					Type: stringType,
+					Err:  errInvalidUTF8,
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHardenedDecoder(t *testing.T) {
+	t.Parallel()
+
+	d := NewHardenedDecoder()
+	e := Everything{}
+	err := d.Parse(url.Values{
+		"String":    {"héllo"},
+		"Map[a]":    {"1"},
+		"Struct[A]": {"2"},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.String", "héllo", e.String)
+
+	deep := "Map" + strings.Repeat("[a]", hardenedMaxDepth+1)
+	many := make(url.Values)
+	for i := 0; i <= hardenedMaxKeys; i++ {
+		many["Map["+strconv.Itoa(i)+"]"] = []string{"1"}
+	}
+
+	for _, test := range []struct {
+		params url.Values
+		err    error
+	}{
+		{url.Values{deep: {"1"}},
+			LimitError{Key: deep, Limit: "depth", Max: hardenedMaxDepth}},
+		{many, LimitError{Limit: "keys", Max: hardenedMaxKeys}},
+		{url.Values{"String": {strings.Repeat("a", hardenedMaxValueLength+1)}},
+			LimitError{Key: "String", Limit: "value length",
+				Max: hardenedMaxValueLength}},
+		{url.Values{"String": {"\xff"}},
+			TypeError{Key: "String", Type: stringType, Err: errInvalidUTF8}},
+		{url.Values{"Map[\xff]": {"1"}},
+			TypeError{Key: "Map[\xff]", Type: stringType, Err: errInvalidUTF8}},
+	} {
+		err := d.Parse(test.params, &Everything{})
+		assertEqual(t, "err", test.err, err)
+	}
+
+	err = d.Parse(url.Values{"Int": {"x"}, "Uint": {"x"}, "Bool": {"x"}}, &e)
+	if terr, ok := err.(TypeError); !ok || terr.Key != "Bool" {
+		t.Errorf("Expected TypeError for Bool, got %v", err)
+	}
+	if !errors.Is(LimitError{}, ErrLimit) {
+		t.Error("Expected LimitError to be ErrLimit")
+	}
+
+	// Whichever key breaks a limit first, in sorted order, is the one
+	// reported, however the map happens to be iterated.
+	long := strings.Repeat("a", hardenedMaxValueLength+1)
+	for i := 0; i < 20; i++ {
+		err = d.Parse(url.Values{
+			"String": {long},
+			"Map[b]": {long},
+			"Map[a]": {long},
+			"Int":    {long},
+		}, &Everything{})
+		assertEqual(t, "err", LimitError{Key: "Int", Limit: "value length",
+			Max: hardenedMaxValueLength}, err)
+	}
+
+	// Options given to NewHardenedDecoder override the defaults
+	d = NewHardenedDecoder(WithMaxDepth(0))
+	err = d.Parse(url.Values{deep: {"1"}}, &Everything{})
+	if _, ok := err.(NestingError); !ok {
+		t.Errorf("Expected NestingError, got %v", err)
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	t.Parallel()
+
+	for key, redact := range map[string]bool{
+		"password":            true,
+		"user[Password]":      true,
+		"api_key":             true,
+		"csrf_token":          true,
+		"user[name]":          false,
+		"keyboard[layout]":    false,
+		"oauth[ClientSecret]": true,
+	} {
+		assertEqual(t, key, redact, RedactSecrets(key))
+	}
+}
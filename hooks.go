@@ -0,0 +1,101 @@
+package param
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The signatures of the github.com/mitchellh/mapstructure DecodeHookFuncs we
+// support, DecodeHookFuncType and DecodeHookFuncKind.
+type (
+	typeHook func(from, to reflect.Type, data interface{}) (interface{}, error)
+	kindHook func(from, to reflect.Kind, data interface{}) (interface{}, error)
+)
+
+var typeHookType = reflect.TypeOf(typeHook(nil))
+var kindHookType = reflect.TypeOf(kindHook(nil))
+
+// WithDecodeHook returns an Option that passes every value through the given
+// hook before it is parsed, to ease migration from
+// github.com/mitchellh/mapstructure. hook must be a function with the
+// signature of either mapstructure.DecodeHookFuncType,
+//
+//	func(from, to reflect.Type, data interface{}) (interface{}, error)
+//
+// or mapstructure.DecodeHookFuncKind,
+//
+//	func(from, to reflect.Kind, data interface{}) (interface{}, error)
+//
+// (such as the hooks returned by mapstructure.StringToTimeDurationHookFunc).
+// Hooks are called once for every value that is addressed by a key on its own,
+// with data set to the value (a string) and to set to the type of the field,
+// slice element, or map value being parsed (looking through pointers, but not
+// through Optionals or the nullable types of database/sql). A hook may return
+// a string, which is parsed as usual, or a value that is assignable or
+// convertible to the target type, which is used as is. Errors returned by hooks
+// are reported as TypeErrors. If the option is given several times, the hooks
+// are run in order, each being passed the previous one's result.
+func WithDecodeHook(hook interface{}) Option {
+	hv := reflect.ValueOf(hook)
+	var h typeHook
+	switch {
+	case hv.Type().ConvertibleTo(typeHookType):
+		h = hv.Convert(typeHookType).Interface().(typeHook)
+	case hv.Type().ConvertibleTo(kindHookType):
+		kh := hv.Convert(kindHookType).Interface().(kindHook)
+		h = func(from, to reflect.Type, data interface{}) (interface{}, error) {
+			return kh(from.Kind(), to.Kind(), data)
+		}
+	default:
+		pebkac("WithDecodeHook was passed %v, which is not a supported "+
+			"hook signature.", hv.Type())
+	}
+
+	return func(d *Decoder) {
+		d.hooks = append(d.hooks, h)
+	}
+}
+
+// Runs the Decoder's hooks on the value that is about to be parsed into
+// target, which must not be a pointer. If they produce a value of the target's
+// type, it is set and true is returned. Otherwise, the values to be parsed are
+// returned.
+func (p *parser) runHooks(key, keytail string, values []string, target reflect.Value) ([]string, bool) {
+	if keytail != "" || len(values) != 1 {
+		return values, false
+	}
+
+	t := target.Type()
+	var data interface{} = values[0]
+	for _, hook := range p.hooks {
+		var err error
+		data, err = hook(reflect.TypeOf(data), t, data)
+		if err != nil {
+			panic(TypeError{Key: kpath(key, keytail), Type: t, Err: err})
+		}
+	}
+
+	if s, ok := data.(string); ok {
+		return []string{s}, false
+	}
+	v := reflect.ValueOf(data)
+	switch {
+	case data == nil:
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: t,
+			Err:  fmt.Errorf("decode hook returned nil"),
+		})
+	case v.Type().AssignableTo(t):
+		target.Set(v)
+	case v.Type().ConvertibleTo(t):
+		target.Set(v.Convert(t))
+	default:
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: t,
+			Err:  fmt.Errorf("decode hook returned a %v", v.Type()),
+		})
+	}
+	return nil, true
+}
@@ -0,0 +1,132 @@
+package param
+
+import (
+	"database/sql"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Legacy struct {
+	Timeout  time.Duration  `mapstructure:"timeout"`
+	Retry    *time.Duration `mapstructure:"retry,omitempty"`
+	Name     string         `mapstructure:"name" json:"user_name"`
+	Tags     []string       `mapstructure:"tags"`
+	Limits   map[string]int `mapstructure:"limits"`
+	Override string         `param:"override" mapstructure:"ignored"`
+}
+
+// Like mapstructure.StringToTimeDurationHookFunc
+type durationHook func(reflect.Type, reflect.Type, interface{}) (interface{}, error)
+
+func stringToDuration() durationHook {
+	return func(f, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(time.Duration(0)) {
+			return data, nil
+		}
+		return time.ParseDuration(data.(string))
+	}
+}
+
+// Like mapstructure.DecodeHookFuncKind
+func upperStrings(f, t reflect.Kind, data interface{}) (interface{}, error) {
+	if f == reflect.String && t == reflect.String {
+		return strings.ToUpper(data.(string)), nil
+	}
+	return data, nil
+}
+
+func TestDecodeHook(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithDecodeHook(stringToDuration()),
+		WithDecodeHook(upperStrings))
+	var l Legacy
+	err := d.Parse(url.Values{
+		"timeout":   {"1m"},
+		"retry":     {"2s"},
+		"name":      {"bob"},
+		"tags[]":    {"a", "b"},
+		"limits[x]": {"3"},
+		"override":  {"c"},
+	}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	retry := 2 * time.Second
+	assertEqual(t, "l", Legacy{
+		Timeout:  time.Minute,
+		Retry:    &retry,
+		Name:     "BOB",
+		Tags:     []string{"A", "B"},
+		Limits:   map[string]int{"x": 3},
+		Override: "C",
+	}, l)
+
+	err = d.Parse(url.Values{"timeout": {"soon"}}, &l)
+	var terr TypeError
+	if !errors.As(err, &terr) || terr.Key != "timeout" {
+		t.Errorf("Expected TypeError for timeout, got %v", err)
+	}
+
+	d = NewDecoder(WithDecodeHook(func(f, t reflect.Type, data interface{}) (interface{}, error) {
+		return 4.5, nil
+	}))
+	err = d.Parse(url.Values{"tags[]": {"a"}}, &l)
+	if !errors.As(err, &terr) || terr.Key != "tags[0]" {
+		t.Errorf("Expected TypeError for tags[0], got %v", err)
+	}
+}
+
+func TestMapstructureTags(t *testing.T) {
+	t.Parallel()
+
+	var l Legacy
+	err := Parse(url.Values{"name": {"bob"}, "timeout": {"5"}}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l.Name", "bob", l.Name)
+	assertEqual(t, "l.Timeout", time.Duration(5), l.Timeout)
+
+	err = Parse(url.Values{"ignored": {"x"}}, &l)
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
+
+type Hooked struct {
+	Count Optional[int]    `param:"count"`
+	Score sql.NullInt64    `param:"score"`
+	Names []sql.NullString `param:"names"`
+}
+
+func TestDecodeHookCalls(t *testing.T) {
+	t.Parallel()
+
+	var to []reflect.Type
+	d := NewDecoder(WithDecodeHook(func(f, t reflect.Type, data interface{}) (interface{}, error) {
+		to = append(to, t)
+		return data, nil
+	}))
+	var n Hooked
+	err := d.Parse(url.Values{
+		"count":   {"1"},
+		"score":   {"2"},
+		"names[]": {"a"},
+	}, &n)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "n.Count", 1, n.Count.Value())
+	assertEqual(t, "n.Score.Int64", int64(2), n.Score.Int64)
+	// Each value is passed to the hook once, as the type holding it.
+	assertEqual(t, "to", []reflect.Type{
+		reflect.TypeOf(Optional[int]{}),
+		reflect.TypeOf(sql.NullString{}),
+		reflect.TypeOf(sql.NullInt64{}),
+	}, to)
+}
@@ -0,0 +1,100 @@
+package param
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var errUnknownImpl = errors.New("no type is registered under this name")
+
+// RegisterInterface registers concreteType as the implementation of the
+// interface type ifaceType that is named name. Fields of interface type are
+// parsed with the help of a discriminator: a sibling field, named by the
+// "discriminator" tag option, whose value is the name of the type to allocate.
+// For instance, given
+//
+//	type Shape interface{ Area() float64 }
+//	type Circle struct{ Radius float64 }
+//	type Rect struct{ Width, Height float64 }
+//
+//	type Drawing struct {
+//		Kind  string `param:"kind"`
+//		Shape Shape  `param:"shape,discriminator=kind"`
+//	}
+//
+//	d.RegisterInterface(reflect.TypeOf((*Shape)(nil)).Elem(), "circle",
+//		reflect.TypeOf(Circle{}))
+//
+// the parameters "kind=circle&shape[Radius]=2" set Shape to a Circle with
+// radius 2. If the interface field already holds a value of the right type, it
+// is updated in place. A missing discriminator is reported as a RequiredError,
+// and one that does not name a registered type as a TypeError.
+//
+// concreteType may be a pointer type. RegisterInterface must not be called once
+// the Decoder is in use.
+func (d *Decoder) RegisterInterface(ifaceType reflect.Type, name string, concreteType reflect.Type) {
+	if ifaceType.Kind() != reflect.Interface {
+		pebkac("RegisterInterface was passed %v, which is not an "+
+			"interface type.", ifaceType)
+	}
+	if !concreteType.Implements(ifaceType) {
+		pebkac("RegisterInterface was passed %v, which does not "+
+			"implement %v.", concreteType, ifaceType)
+	}
+
+	if d.interfaces == nil {
+		d.interfaces = make(map[reflect.Type]map[string]reflect.Type)
+	}
+	if d.interfaces[ifaceType] == nil {
+		d.interfaces[ifaceType] = make(map[string]reflect.Type)
+	}
+	d.interfaces[ifaceType][name] = concreteType
+}
+
+func discriminatorHandler(s reflect.Type, sf reflect.StructField, discriminator string) parseFunc {
+	t := sf.Type
+	if t.Kind() != reflect.Interface {
+		pebkac("struct %v field %q has the discriminator option, but is "+
+			"not an interface (it's a %v).", s, sf.Name, t)
+	}
+	found := false
+	for i := 0; i < s.NumField(); i++ {
+		if extractName(s.Field(i)) == discriminator {
+			found = true
+		}
+	}
+	if !found {
+		pebkac("struct %v field %q has discriminator %q, but %v has no "+
+			"such field.", s, sf.Name, discriminator, s)
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		dkey := siblingKey(kpath(key, keytail), discriminator)
+		dvalues, ok := p.params[dkey]
+		if !ok {
+			panic(RequiredError{Key: dkey, Type: stringType})
+		}
+		name := p.primitive(dkey, "", stringType, dvalues)
+		ct, ok := p.interfaces[t][name]
+		if !ok {
+			panic(TypeError{Key: dkey, Type: t, Err: errUnknownImpl})
+		}
+
+		v := reflect.New(ct).Elem()
+		if !target.IsNil() && target.Elem().Type() == ct {
+			v.Set(target.Elem())
+		}
+		parse(p, key, keytail, values, v)
+		target.Set(v)
+	}
+}
+
+// Returns the key of the field named name that is a sibling of the field with
+// the given key: for instance, the sibling "kind" of "a[b]" is "a[kind]".
+func siblingKey(key, name string) string {
+	if !strings.HasSuffix(key, "]") {
+		return name
+	}
+	return key[:strings.LastIndexByte(key, '[')] + "[" + name + "]"
+}
@@ -0,0 +1,113 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+func (c Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Rect struct {
+	Width  float64
+	Height float64
+}
+
+func (r *Rect) Area() float64 { return r.Width * r.Height }
+
+type Drawing struct {
+	Kind  string `param:"kind"`
+	Shape Shape  `param:"shape,discriminator=kind"`
+	Layer Layer  `param:"layer"`
+}
+
+type Layer struct {
+	Kind  string `param:"kind"`
+	Shape Shape  `param:"shape,discriminator=kind"`
+}
+
+var shapeType = reflect.TypeOf((*Shape)(nil)).Elem()
+
+func shapeDecoder() *Decoder {
+	d := NewDecoder()
+	d.RegisterInterface(shapeType, "circle", reflect.TypeOf(Circle{}))
+	d.RegisterInterface(shapeType, "rect", reflect.TypeOf(&Rect{}))
+	return d
+}
+
+func TestInterface(t *testing.T) {
+	t.Parallel()
+
+	var drawing Drawing
+	err := shapeDecoder().Parse(url.Values{
+		"kind":                 {"circle"},
+		"shape[Radius]":        {"2"},
+		"layer[kind]":          {"rect"},
+		"layer[shape][Width]":  {"3"},
+		"layer[shape][Height]": {"4"},
+	}, &drawing)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "drawing.Shape", Shape(Circle{Radius: 2}), drawing.Shape)
+	assertEqual(t, "drawing.Layer", Layer{
+		Kind:  "rect",
+		Shape: &Rect{Width: 3, Height: 4},
+	}, drawing.Layer)
+
+	// Values of the right type are updated in place
+	rect := &Rect{Width: 5, Height: 6}
+	drawing = Drawing{Shape: rect}
+	err = shapeDecoder().Parse(url.Values{
+		"kind":         {"rect"},
+		"shape[Width]": {"7"},
+	}, &drawing)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "drawing.Shape", Shape(&Rect{Width: 7, Height: 6}),
+		drawing.Shape)
+}
+
+func TestInterfaceErrors(t *testing.T) {
+	t.Parallel()
+
+	d := shapeDecoder()
+	err := d.Parse(url.Values{"shape[Radius]": {"2"}}, &Drawing{})
+	assertEqual(t, "err", RequiredError{Key: "kind", Type: stringType}, err)
+
+	err = d.Parse(url.Values{
+		"layer[kind]":        {"hexagon"},
+		"layer[shape][Side]": {"2"},
+	}, &Drawing{})
+	assertEqual(t, "err", TypeError{
+		Key:  "layer[kind]",
+		Type: shapeType,
+		Err:  errUnknownImpl,
+	}, err)
+
+	err = d.Parse(url.Values{
+		"kind":          {"rect"},
+		"shape[Radius]": {"2"},
+	}, &Drawing{})
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
+
+func TestSiblingKey(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "a", "kind", siblingKey("a", "kind"))
+	assertEqual(t, "a[b]", "a[kind]", siblingKey("a[b]", "kind"))
+	assertEqual(t, "a[b][c]", "a[b][kind]", siblingKey("a[b][c]", "kind"))
+}
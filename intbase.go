@@ -0,0 +1,60 @@
+package param
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// WithPrefixedIntegers returns an Option that parses integers as Go integer
+// literals (see strconv.ParseInt with a base of 0), so that "0x1F", "0o17",
+// "0b11", and "1_000" are all accepted. Note that, as in Go, this means that
+// integers with a leading "0", such as "017", are octal.
+func WithPrefixedIntegers() Option {
+	return func(d *Decoder) {
+		d.prefixedInts = true
+	}
+}
+
+// The base integers are parsed in.
+func (d *Decoder) intBase() int {
+	if d.prefixedInts {
+		return 0
+	}
+	return 10
+}
+
+// The "base" tag option parses integer fields (or slices of them, and so on) in
+// the given base, as in `param:"mask,base=16"`. A base of 0 parses integers
+// as Go integer literals, like WithPrefixedIntegers.
+func wrapBase(s reflect.Type, sf reflect.StructField, base string, h parseFunc) parseFunc {
+	b, err := strconv.Atoi(base)
+	if err != nil || b == 1 || b < 0 || b > 36 {
+		pebkac("struct %v field %q has invalid base %q.", s, sf.Name,
+			base)
+	}
+
+	var unsigned bool
+	switch baseType(sf.Type).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		unsigned = true
+	default:
+		pebkac("struct %v field %q has the base option, but is of type "+
+			"%v.", s, sf.Name, sf.Type)
+	}
+
+	// We translate values into base 10, and let the usual handler take
+	// care of complaining about values out of range for the field's type.
+	return transformValues(h, func(v string) (string, error) {
+		if v == "" {
+			// Let the usual handler decide what to make of it.
+			return v, nil
+		}
+		if unsigned {
+			i, err := strconv.ParseUint(v, b, 64)
+			return strconv.FormatUint(i, 10), err
+		}
+		i, err := strconv.ParseInt(v, b, 64)
+		return strconv.FormatInt(i, 10), err
+	})
+}
@@ -0,0 +1,69 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+type Register struct {
+	Addr  uint16  `param:"addr,base=0"`
+	Mask  []uint8 `param:"mask,base=16"`
+	Delta int     `param:"delta,base=2"`
+	Plain int     `param:"plain"`
+}
+
+func TestPrefixedIntegers(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithPrefixedIntegers())
+	for value, expected := range map[string]int{
+		"0x1F": 31, "0o17": 15, "017": 15, "0b11": 3, "-1_000": -1000,
+		"12": 12,
+	} {
+		e := Everything{}
+		if err := d.Parse(url.Values{"Int": {value}}, &e); err != nil {
+			t.Fatal("Parse error: ", err)
+		}
+		assertEqual(t, "e.Int", expected, e.Int)
+	}
+
+	err := Parse(url.Values{"Int": {"0x1F"}}, &Everything{})
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Expected syntax error, got %v", err)
+	}
+}
+
+func TestBase(t *testing.T) {
+	t.Parallel()
+
+	var r Register
+	err := Parse(url.Values{
+		"addr":   {"0xbeef"},
+		"mask[]": {"ff", "0F"},
+		"delta":  {"-101"},
+		"plain":  {"010"},
+	}, &r)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "r", Register{
+		Addr:  0xbeef,
+		Mask:  []uint8{0xff, 0x0f},
+		Delta: -5,
+		Plain: 10,
+	}, r)
+
+	for key, value := range map[string]string{
+		"addr":   "0x10000",
+		"mask[]": "100",
+		"delta":  "2",
+	} {
+		err := Parse(url.Values{key: {value}}, &Register{})
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %s=%s, got %v", key,
+				value, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package param
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// WithJSONNulls returns an Option that eases the reuse of structs written for
+// encoding/json by mirroring its treatment of null. For pointer and time.Time
+// fields that take their name from a "json" tag (i.e., that have no name in a
+// "param" tag), the values "" and "null" leave the field set to its zero value:
+// nil for pointers, and the zero time for times. Without this option such
+// values are parsed like any other, which is usually an error.
+func WithJSONNulls() Option {
+	return func(d *Decoder) {
+		d.jsonNulls = true
+	}
+}
+
+// Reports whether the given struct field takes its name from its "json" tag.
+func jsonNamed(sf reflect.StructField) bool {
+	if name, _ := splitTag(sf.Tag.Get("param")); name != "" {
+		return false
+	}
+	if name, _ := splitTag(sf.Tag.Get("mapstructure")); name != "" {
+		return false
+	}
+	name, _ := splitTag(sf.Tag.Get("json"))
+	return name != "" && !ignored(sf)
+}
+
+// Reports whether the given values represent JSON's null, assuming they are
+// bound to a field for which that is meaningful.
+func (p *parser) jsonNull(keytail string, values []string) bool {
+	return p.jsonNulls && keytail == "" && len(values) == 1 &&
+		(values[0] == "" || values[0] == "null")
+}
+
+// WithJSONUnmarshalers returns an Option that allows types implementing
+// json.Unmarshaler, but none of the interfaces Parse otherwise looks for, to be
+// given as a single value. The value is quoted as a JSON string and passed to
+// UnmarshalJSON. This applies to types of every kind, so that a named int
+// implementing json.Unmarshaler, for instance, is no longer parsed as an int.
+// Structs, maps, and slices may still be given as nested keys, as in
+// "duration[Duration]=5".
+func WithJSONUnmarshalers() Option {
+	return func(d *Decoder) {
+		d.jsonUnmarshalers = true
+	}
+}
+
+// Reports whether values of the given type may be parsed with UnmarshalJSON,
+// if the Decoder allows it.
+func isJSONUnmarshaler(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(jsonUnmarshalerType) && !isLeaf(t) &&
+		!isBytes(t)
+}
+
+// Wraps the handler of a field whose type is a json.Unmarshaler so that it
+// uses UnmarshalJSON when the Decoder allows it.
+func wrapJSONUnmarshaler(h parseFunc) parseFunc {
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		if p.jsonUnmarshalers && keytail == "" {
+			parseJSONUnmarshaler(p, key, keytail, values, target)
+			return
+		}
+		h(p, key, keytail, values, target)
+	}
+}
+
+func parseJSONUnmarshaler(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+
+	// Marshaling a string can't fail.
+	quoted, _ := json.Marshal(value)
+	ju := target.Addr().Interface().(json.Unmarshaler)
+	if err := ju.UnmarshalJSON(quoted); err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  err,
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package param
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type JSONish struct {
+	Time   time.Time  `json:"time"`
+	PTime  *time.Time `json:"ptime,omitempty"`
+	PInt   *int       `json:"pint"`
+	Int    int        `json:"int"`
+	Tagged *int       `json:"tagged" param:"tagged"`
+}
+
+func TestJSONNulls(t *testing.T) {
+	t.Parallel()
+
+	one := 1
+	j := JSONish{Time: testTime, PTime: &testTime, PInt: &one}
+	d := NewDecoder(WithJSONNulls())
+	err := d.Parse(url.Values{
+		"time":  {""},
+		"ptime": {"null"},
+		"pint":  {""},
+	}, &j)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "j.Time", time.Time{}, j.Time)
+	assertEqual(t, "j.PTime", (*time.Time)(nil), j.PTime)
+	assertEqual(t, "j.PInt", (*int)(nil), j.PInt)
+
+	err = d.Parse(url.Values{"pint": {"4"}}, &j)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "*j.PInt", 4, *j.PInt)
+
+	for _, key := range []string{"int", "tagged"} {
+		err = d.Parse(url.Values{key: {"null"}}, &j)
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %s=null, got %v", key,
+				err)
+		}
+	}
+}
+
+func TestJSONNullsDisabled(t *testing.T) {
+	t.Parallel()
+
+	j := JSONish{}
+	err := Parse(url.Values{"ptime": {"null"}}, &j)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
+
+// Window implements json.Unmarshaler, expecting a JSON string such as
+// "09:00-17:00".
+type Window struct {
+	Start, End string
+}
+
+func (w *Window) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	i := strings.IndexByte(s, '-')
+	if i < 0 {
+		return errors.New("expected a range")
+	}
+	w.Start, w.End = s[:i], s[i+1:]
+	return nil
+}
+
+type Schedule struct {
+	Open    Window            `param:"open"`
+	Windows []Window          `param:"windows"`
+	ByDay   map[string]Window `param:"by_day"`
+	Late    *Window           `param:"late"`
+}
+
+func TestJSONUnmarshalers(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithJSONUnmarshalers())
+	s := Schedule{}
+	err := d.Parse(url.Values{
+		"open":        {"09:00-17:00"},
+		"windows[]":   {"1-2", "3-4"},
+		"by_day[mon]": {"5-6"},
+		"late":        {"22:00-23:00"},
+	}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s", Schedule{
+		Open:    Window{"09:00", "17:00"},
+		Windows: []Window{{"1", "2"}, {"3", "4"}},
+		ByDay:   map[string]Window{"mon": {"5", "6"}},
+		Late:    &Window{"22:00", "23:00"},
+	}, s)
+
+	// Nested keys still work.
+	err = d.Parse(url.Values{"open[Start]": {"08:00"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.Open.Start", "08:00", s.Open.Start)
+
+	err = d.Parse(url.Values{"open": {"noon"}}, &s)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+
+	// Without the option, such types are parsed as usual.
+	err = Parse(url.Values{"open": {"09:00-17:00"}}, &s)
+	if _, ok := err.(SyntaxError); !ok {
+		t.Errorf("Expected SyntaxError, got %v", err)
+	}
+}
+
+// Severity implements json.Unmarshaler, expecting a JSON string such as "low".
+type Severity int
+
+func (l *Severity) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown severity")
+	}
+	return nil
+}
+
+// Shout implements json.Unmarshaler, upper-casing JSON strings.
+type Shout string
+
+func (s *Shout) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*s = Shout(strings.ToUpper(v))
+	return nil
+}
+
+type Alarm struct {
+	Severity   Severity   `param:"severity"`
+	Severities []Severity `param:"severities"`
+	Shout      Shout      `param:"shout"`
+}
+
+func TestJSONUnmarshalerKinds(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithJSONUnmarshalers())
+	var a Alarm
+	err := d.Parse(url.Values{
+		"severity":     {"high"},
+		"severities[]": {"low", "high"},
+		"shout":        {"hey"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a", Alarm{Severity: 2, Severities: []Severity{1, 2}, Shout: "HEY"},
+		a)
+
+	// Without the option, they're parsed as their kinds are.
+	a = Alarm{}
+	err = Parse(url.Values{"severity": {"2"}, "shout": {"hey"}}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a", Alarm{Severity: 2, Shout: "hey"}, a)
+}
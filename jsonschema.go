@@ -0,0 +1,221 @@
+package param
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// The JSON Schema dialect JSONSchema produces.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	Minimum              interface{}            `json:"minimum,omitempty"`
+	Maximum              interface{}            `json:"maximum,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// JSONSchema produces a JSON Schema (draft 2020-12) document describing the
+// shape of the parameters accepted by the struct pointed to by target, as
+// though the nested keys Parse accepts (e.g., "user[address][city]") were
+// nested JSON objects. It is derived from the same struct metadata Parse uses,
+// and includes the constraints Parse enforces: the range of each integer type,
+// the min, max, oneof, pattern, maxlen, and maxitems options, and the required
+// and default options of top-level fields. Fields promoted from embedded
+// structs are described in place of the embedded structs themselves, and fields
+// with aliases under each of their names, though only their canonical names are
+// ever required.
+//
+// Nested struct types are described once, in the document's "$defs", and
+// referred to by name. This allows recursive types to be described.
+func JSONSchema(target interface{}) (doc []byte, err error) {
+	defer recoverError(&err)
+
+	t := targetStruct("param.JSONSchema", target).Type()
+	g := jsonSchemaGen{
+		root:  t,
+		names: make(map[reflect.Type]string),
+		defs:  make(map[string]*jsonSchema),
+	}
+
+	s := g.object(t)
+	for _, f := range reachableFields(t, false, []reflect.Type{t}) {
+		name, l := f.names[0], f.l
+		if l.promoted {
+			continue
+		}
+		if requiredIn(l.opts, "") {
+			s.Required = append(s.Required, name)
+		}
+		// The names of a field share its schema.
+		if def, ok := l.opts["default"]; ok {
+			s.Properties[name].Default = typedDefault(
+				f.s.Field(l.offset).Type, name, def, l)
+		}
+	}
+	sort.Strings(s.Required)
+
+	s.Schema = jsonSchemaDialect
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	return json.Marshal(s)
+}
+
+type jsonSchemaGen struct {
+	root  reflect.Type
+	names map[reflect.Type]string
+	defs  map[string]*jsonSchema
+}
+
+func (g *jsonSchemaGen) schema(t reflect.Type) *jsonSchema {
+	if isSQLNull(t) || isOptional(t) {
+		return g.schema(t.Field(0).Type)
+	}
+	if t == timeType {
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	}
+	if t == urlType {
+		return &jsonSchema{Type: "string", Format: "uri-reference"}
+	}
+	if isLeaf(t) {
+		return &jsonSchema{Type: "string"}
+	}
+	if isBytes(t) {
+		return &jsonSchema{Type: "string", ContentEncoding: "base64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := uint(t.Bits())
+		return &jsonSchema{
+			Type:    "integer",
+			Minimum: int64(-1) << (bits - 1),
+			Maximum: int64(1)<<(bits-1) - 1,
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{
+			Type:    "integer",
+			Minimum: 0,
+			Maximum: uint64(math.MaxUint64) >> (64 - uint(t.Bits())),
+		}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Slice:
+		return &jsonSchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &jsonSchema{
+			Type:                 "object",
+			AdditionalProperties: g.schema(t.Elem()),
+		}
+	case reflect.Struct:
+		return g.ref(t)
+	}
+
+	// cacheStruct has already complained about anything else.
+	return &jsonSchema{}
+}
+
+// Returns a reference to the definition of the given struct type, generating
+// the definition if necessary. Anonymous struct types can't be referred to
+// recursively, so they are described inline instead.
+func (g *jsonSchemaGen) ref(t reflect.Type) *jsonSchema {
+	if t == g.root {
+		return &jsonSchema{Ref: "#"}
+	}
+	if t.Name() == "" {
+		return g.object(t)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = t.String()
+		for i := 2; g.defs[name] != nil; i++ {
+			name = t.String() + strconv.Itoa(i)
+		}
+		g.names[t] = name
+		// Reserve the name before recursing, in case we come across
+		// this type again while describing it.
+		g.defs[name] = &jsonSchema{}
+		*g.defs[name] = *g.object(t)
+	}
+	return &jsonSchema{Ref: "#/$defs/" + name}
+}
+
+// Narrow the schema of a field of type t, or of the elements of a slice, to the
+// bounds given by the field's min and max options, the choices given by its
+// oneof option, the pattern given by its pattern option, and the lengths given
+// by its maxlen and maxitems options.
+func (s *jsonSchema) constrain(t reflect.Type, opts tagOptions) {
+	if max, ok := opts["maxitems"]; ok && s.Type == "array" {
+		s.MaxItems = optionInt(max)
+	}
+	for s.Items != nil {
+		s = s.Items
+	}
+	s.Enum = enumValues(baseType(t), opts)
+	s.Pattern = opts["pattern"]
+	if max, ok := opts["maxlen"]; ok {
+		s.MaxLength = optionInt(max)
+	}
+	if min, ok := opts["min"]; ok {
+		s.Minimum = json.Number(min)
+	}
+	if max, ok := opts["max"]; ok {
+		s.Maximum = json.Number(max)
+	}
+}
+
+func (g *jsonSchemaGen) object(t reflect.Type) *jsonSchema {
+	s := &jsonSchema{
+		Type:       "object",
+		Properties: make(map[string]*jsonSchema),
+	}
+	for _, f := range reachableFields(t, false, []reflect.Type{t}) {
+		l, ft := f.l, f.s.Field(f.l.offset).Type
+		if l.promoted {
+			continue
+		}
+		var fs *jsonSchema
+		if l.opts.has("base64") {
+			fs = &jsonSchema{Type: "string", ContentEncoding: "base64"}
+		} else {
+			fs = g.schema(ft)
+			if l.opts.has("keyfield") {
+				// These are given as objects keyed by the key
+				// field, not as arrays.
+				fs = &jsonSchema{
+					Type:                 "object",
+					AdditionalProperties: fs.Items,
+				}
+			}
+			fs.constrain(ft, l.opts)
+		}
+		for _, name := range f.names {
+			s.Properties[name] = fs
+		}
+	}
+	return s
+}
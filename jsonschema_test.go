@@ -0,0 +1,153 @@
+package param
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+type Schematic struct {
+	Limit int8   `param:"limit,default=25"`
+	Query string `param:"q,required"`
+	Tags  []uint16
+	Sub   Sub
+	Crazy *Crazy
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&Schematic{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+
+	var s map[string]interface{}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal("Unmarshal error: ", err)
+	}
+
+	assertEqual(t, "$schema", jsonSchemaDialect, s["$schema"])
+	assertEqual(t, "required", []interface{}{"q"}, s["required"])
+
+	props := s["properties"].(map[string]interface{})
+	assertEqual(t, "limit", map[string]interface{}{
+		"type":    "integer",
+		"minimum": -128.0,
+		"maximum": 127.0,
+		"default": 25.0,
+	}, props["limit"])
+	assertEqual(t, "Tags", map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 0.0,
+			"maximum": 65535.0,
+		},
+	}, props["Tags"])
+	assertEqual(t, "Sub", map[string]interface{}{
+		"$ref": "#/$defs/param.Sub",
+	}, props["Sub"])
+
+	defs := s["$defs"].(map[string]interface{})
+	crazy := defs["param.Crazy"].(map[string]interface{})
+	crazyProps := crazy["properties"].(map[string]interface{})
+	assertEqual(t, "Crazy.A", map[string]interface{}{
+		"$ref": "#/$defs/param.Crazy",
+	}, crazyProps["A"])
+}
+
+func TestJSONSchemaRecursiveRoot(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&Crazy{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+
+	var s map[string]interface{}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal("Unmarshal error: ", err)
+	}
+	props := s["properties"].(map[string]interface{})
+	assertEqual(t, "A", map[string]interface{}{"$ref": "#"}, props["A"])
+	if _, ok := s["$defs"]; ok {
+		t.Error("Expected no definitions")
+	}
+}
+
+func TestJSONSchemaBounds(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&Page{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+
+	var s map[string]interface{}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal("Unmarshal error: ", err)
+	}
+	props := s["properties"].(map[string]interface{})
+	assertEqual(t, "limit", map[string]interface{}{
+		"type":    "integer",
+		"minimum": 1.0,
+		"maximum": 100.0,
+		"default": 25.0,
+	}, props["limit"])
+	assertEqual(t, "scores", map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":    "integer",
+			"minimum": -5.0,
+			"maximum": 5.0,
+		},
+	}, props["scores"])
+}
+
+func TestJSONSchemaPromoted(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&ListParams{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+
+	var s map[string]interface{}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal("Unmarshal error: ", err)
+	}
+	var names []string
+	for name := range s["properties"].(map[string]interface{}) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assertEqual(t, "properties", []string{
+		"A", "B", "cursor", "limit", "page", "place", "q",
+	}, names)
+}
+
+func TestJSONSchemaAliases(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&Paging{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+
+	var s map[string]interface{}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal("Unmarshal error: ", err)
+	}
+	props := s["properties"].(map[string]interface{})
+	var names []string
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assertEqual(t, "properties", []string{
+		"f", "filter", "limit", "order", "page_size", "per_page", "sort",
+	}, names)
+	assertEqual(t, "per_page", props["limit"], props["per_page"])
+	assertEqual(t, "required", []interface{}{"sort"}, s["required"])
+}
@@ -0,0 +1,96 @@
+package param
+
+import (
+	"reflect"
+)
+
+// The "keyfield" tag option allows a slice of structs to be given as though it
+// were a map from some identifying field of the struct to the rest of the
+// struct. For instance, a field tagged `param:"items,keyfield=ID"` of type
+// []Item, where Item has a field named ID, accepts keys of the form
+// "items[abc123][qty]". Each distinct key ("abc123") corresponds to a single
+// element of the slice, which has its ID field set to the key. Like maps, the
+// slice is not cleared before parsing: elements with keys that are already
+// present are updated in place, and elements are otherwise appended to the
+// slice in the order their keys are first encountered.
+//
+// The key field is named by its Go name, or failing that, by its name as a
+// parameter, as in "keyfield=id". The field itself, given with no key at all,
+// is parsed as it would be without the option.
+func keyFieldHandler(s reflect.Type, sf reflect.StructField, keyField string, h parseFunc) parseFunc {
+	t := sf.Type
+	if t.Kind() != reflect.Slice || structType(t.Elem()).Kind() != reflect.Struct {
+		pebkac("struct %v field %q has the keyfield option, but is not "+
+			"a slice of structs (it's a %v).", s, sf.Name, t)
+	}
+	et := structType(t.Elem())
+	_, kl, ok := keyFieldOf(et, keyField)
+	if !ok {
+		pebkac("struct %v field %q has key field %q, but %v has no "+
+			"such field.", s, sf.Name, keyField, et)
+	}
+	if !et.Field(kl.offset).Type.Comparable() {
+		pebkac("struct %v field %q has key field %q, but its type %v "+
+			"is not comparable.", s, sf.Name, keyField,
+			et.Field(kl.offset).Type)
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		if keytail == "" {
+			h(p, key, keytail, values, target)
+			return
+		}
+		mapkey, rest := keyed(t, key, keytail)
+
+		kv := reflect.New(et.Field(kl.offset).Type).Elem()
+		kl.parse(p, key[:len(key)-len(rest)], "", []string{mapkey}, kv)
+
+		var elem reflect.Value
+		for i := 0; i < target.Len(); i++ {
+			e := target.Index(i)
+			if e.Kind() == reflect.Ptr {
+				if e.IsNil() {
+					continue
+				}
+				e = e.Elem()
+			}
+			if e.Field(kl.offset).Interface() == kv.Interface() {
+				elem = e
+				break
+			}
+		}
+		if !elem.IsValid() {
+			target.Set(reflect.Append(target,
+				reflect.New(t.Elem()).Elem()))
+			elem = target.Index(target.Len() - 1)
+			if elem.Kind() == reflect.Ptr {
+				elem.Set(reflect.New(et))
+				elem = elem.Elem()
+			}
+			elem.Field(kl.offset).Set(kv)
+		}
+
+		parseStruct(p, key, rest, values, elem)
+	}
+}
+
+// Finds the key field of the struct type et that the keyfield option names,
+// returning its name as a parameter, and its cache line.
+func keyFieldOf(et reflect.Type, keyField string) (string, cacheLine, bool) {
+	cache := cacheStruct(et)
+	for name, l := range cache {
+		if et.Field(l.offset).Name == keyField {
+			return name, l, true
+		}
+	}
+	l, ok := cache[keyField]
+	return keyField, l, ok
+}
+
+// Returns the struct type t is or points to, or t if it is neither.
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,104 @@
+package param
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type Item struct {
+	ID   string `param:"id"`
+	Qty  int    `param:"qty"`
+	Note string `param:"note"`
+}
+
+type NumberedItem struct {
+	ID  int `param:"id"`
+	Qty int `param:"qty"`
+}
+
+type Cart struct {
+	Items    []Item          `param:"items,keyfield=id"`
+	Numbered []*NumberedItem `param:"numbered,keyfield=id"`
+}
+
+func TestKeyField(t *testing.T) {
+	t.Parallel()
+
+	c := Cart{Items: []Item{{ID: "def456", Qty: 5, Note: "old"}}}
+	err := Parse(url.Values{
+		"items[abc123][qty]":  {"2"},
+		"items[abc123][note]": {"hi"},
+		"items[def456][qty]":  {"1"},
+		"numbered[7][qty]":    {"3"},
+	}, &c)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	assertEqual(t, "c.Items", []Item{
+		{ID: "def456", Qty: 1, Note: "old"},
+		{ID: "abc123", Qty: 2, Note: "hi"},
+	}, c.Items)
+	assertEqual(t, "c.Numbered", []*NumberedItem{{ID: 7, Qty: 3}},
+		c.Numbered)
+}
+
+func TestKeyFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{
+		"items", "items[abc]", "items[abc][llama]", "numbered[x][qty]",
+	} {
+		c := Cart{}
+		err := Parse(url.Values{key: {"1"}}, &c)
+		if err == nil {
+			t.Errorf("Expected error parsing %q", key)
+		}
+	}
+}
+
+type SKU struct {
+	ID  string `param:"sku"`
+	Qty int    `param:"qty"`
+}
+
+// Unmarshals a comma-separated list of IDs.
+type SKUs []SKU
+
+func (s *SKUs) UnmarshalJSON(b []byte) error {
+	var ids string
+	if err := json.Unmarshal(b, &ids); err != nil {
+		return err
+	}
+	for _, id := range strings.Split(ids, ",") {
+		*s = append(*s, SKU{ID: id, Qty: 1})
+	}
+	return nil
+}
+
+type Stock struct {
+	SKUs SKUs `param:"skus,keyfield=ID"`
+}
+
+func TestKeyFieldGoName(t *testing.T) {
+	t.Parallel()
+
+	var s Stock
+	err := Parse(url.Values{"skus[a1][qty]": {"2"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.SKUs", SKUs{{ID: "a1", Qty: 2}}, s.SKUs)
+
+	// The field's own handler still parses it when it has no key.
+	s = Stock{}
+	d := NewDecoder(WithJSONUnmarshalers())
+	err = d.Parse(url.Values{"skus": {"a1,b2"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.SKUs", SKUs{{ID: "a1", Qty: 1}, {ID: "b2", Qty: 1}},
+		s.SKUs)
+}
@@ -0,0 +1,92 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// A KeySplitter splits keys written in some syntax other than the usual
+// brackets into the segments of their paths, allowing Decoders to accept other
+// syntaxes (see WithKeySplitter). For instance, a KeySplitter for keys with
+// double underscores would split "user__address__city" into "user", "address",
+// and "city", which is treated exactly like "user[address][city]". An empty
+// segment other than the first is treated like "[]", so that {"tags", ""} is
+// treated like "tags[]".
+//
+// The first segment may not contain brackets, but the others may contain
+// anything. SplitKey may return an error to reject a key, which Parse returns
+// as a SyntaxError of subtype InvalidKey.
+type KeySplitter interface {
+	SplitKey(key string) ([]string, error)
+}
+
+// KeySplitterFunc is an adapter that allows an ordinary function to be used as
+// a KeySplitter.
+type KeySplitterFunc func(key string) ([]string, error)
+
+// SplitKey calls f(key).
+func (f KeySplitterFunc) SplitKey(key string) ([]string, error) {
+	return f(key)
+}
+
+// WithKeySplitter returns an Option that makes the Decoder split keys into
+// segments with the given KeySplitter, rather than expecting brackets.
+func WithKeySplitter(s KeySplitter) Option {
+	return func(d *Decoder) {
+		d.keySplitter = s
+	}
+}
+
+var errEmptyKey = errors.New("key has no name")
+var errBracketInSegment = errors.New("key segment contains a bracket")
+
+// Rewrite keys split by the Decoder's KeySplitter into the bracketed keys the
+// rest of the parser expects.
+func (d *Decoder) splitKeys(params url.Values) url.Values {
+	split := make(url.Values, len(params))
+	for _, key := range sortedKeys(params) {
+		values := params[key]
+		key = d.splitKey(key)
+		split[key] = append(split[key], values...)
+	}
+	return split
+}
+
+// Translates the given keys, which are in order and distinct, as splitKeys
+// does, preserving their order and distinctness.
+func (d *Decoder) splitOrder(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	split := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = d.splitKey(key); !seen[key] {
+			seen[key] = true
+			split = append(split, key)
+		}
+	}
+	return split
+}
+
+func (d *Decoder) splitKey(key string) string {
+	segs, err := d.keySplitter.SplitKey(key)
+	if err == nil && (len(segs) == 0 || segs[0] == "") {
+		err = errEmptyKey
+	}
+	if err == nil && strings.ContainsAny(segs[0], "[]") {
+		err = errBracketInSegment
+	}
+	if err != nil {
+		panic(SyntaxError{
+			Key:     key,
+			Subtype: InvalidKey,
+			Err:     err,
+		})
+	}
+
+	var b strings.Builder
+	b.WriteString(segs[0])
+	for _, seg := range segs[1:] {
+		b.WriteString("[" + escapeKey(seg) + "]")
+	}
+	return b.String()
+}
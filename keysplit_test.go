@@ -0,0 +1,76 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestKeySplitter(t *testing.T) {
+	t.Parallel()
+
+	underscores := KeySplitterFunc(func(key string) ([]string, error) {
+		if strings.HasPrefix(key, "_") {
+			return nil, errors.New("leading underscore")
+		}
+		return strings.Split(key, "__"), nil
+	})
+	d := NewDecoder(WithKeySplitter(underscores))
+
+	e := Everything{}
+	err := d.Parse(url.Values{
+		"Int":        {"1"},
+		"Struct__A":  {"2"},
+		"Map__a":     {"3"},
+		"Slice__":    {"4", "5"},
+		"PStruct__B": {"6"},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 1, e.Int)
+	assertEqual(t, "e.Struct.A", 2, e.Struct.A)
+	assertEqual(t, "e.Map", map[string]int{"a": 3}, e.Map)
+	assertEqual(t, "e.Slice", []int{4, 5}, e.Slice)
+	assertEqual(t, "e.PStruct.B", 6, e.PStruct.B)
+
+	// Segments after the first may contain brackets.
+	e = Everything{}
+	err = d.Parse(url.Values{"Map__a]b[": {"1"}}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Map", map[string]int{"a]b[": 1}, e.Map)
+
+	// Keys are processed in order by ParseQuery, as usual.
+	err = d.ParseQuery("Uint=x&Struct__A=y", &e)
+	if terr, ok := err.(TypeError); !ok || terr.Key != "Uint" {
+		t.Errorf("Expected TypeError for Uint, got %v", err)
+	}
+
+	for key, cause := range map[string]error{
+		"_Int":      errors.New("leading underscore"),
+		"__Int":     errors.New("leading underscore"),
+		"Struct[A]": errBracketInSegment,
+		"Map[a]__b": errBracketInSegment,
+	} {
+		err := d.Parse(url.Values{key: {"1"}}, &e)
+		assertEqual(t, "err for "+key, SyntaxError{
+			Key:     key,
+			Subtype: InvalidKey,
+			Err:     cause,
+		}, err)
+		if !errors.Is(err, ErrSyntax) {
+			t.Errorf("Expected %v to be %v", err, ErrSyntax)
+		}
+	}
+
+	empty := KeySplitterFunc(func(key string) ([]string, error) {
+		return nil, nil
+	})
+	err = NewDecoder(WithKeySplitter(empty)).Parse(
+		url.Values{"Int": {"1"}}, &e)
+	assertEqual(t, "err", `param: syntax error while parsing key "Int": `+
+		`key has no name`, err.Error())
+}
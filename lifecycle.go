@@ -0,0 +1,166 @@
+package param
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BeforeParamer is implemented by types that want to prepare themselves before
+// they are parsed into, for instance by setting defaults that can't be given in
+// struct tags. If the target of a parse implements BeforeParamer, its
+// BeforeParam method is called before any parameters are parsed. Structs nested
+// within the target have theirs called just before the first parameter for them
+// is parsed, and not at all if there are none.
+type BeforeParamer interface {
+	BeforeParam()
+}
+
+// AfterParamer is implemented by types that want to normalize themselves once
+// they have been parsed into. If the target of a parse, or any struct nested
+// within it that BeforeParam would be called on, implements AfterParamer, its
+// AfterParam method is called once parsing is otherwise complete, but before any
+// Validators are run. Nested structs are handled before the structs they are
+// in. AfterParam is passed the set of fields that were given values, with keys
+// relative to the struct (see ParseWithFields). The first error it returns is
+// returned by Parse as a ValidationError.
+type AfterParamer interface {
+	AfterParam(fields FieldSet) error
+}
+
+var beforeParamerType = reflect.TypeOf((*BeforeParamer)(nil)).Elem()
+var afterParamerType = reflect.TypeOf((*AfterParamer)(nil)).Elem()
+
+// Whether it's worth looking for BeforeParamers and AfterParamers within values
+// of a given type, computed once per type as for isValidatable.
+var lifecycleLock sync.RWMutex
+var lifecycle = make(map[reflect.Type]bool)
+
+func hasLifecycle(t reflect.Type) bool {
+	lifecycleLock.RLock()
+	v, ok := lifecycle[t]
+	lifecycleLock.RUnlock()
+	if ok {
+		return v
+	}
+
+	v = findImplementer(t, make(map[reflect.Type]bool), beforeParamerType,
+		afterParamerType)
+
+	lifecycleLock.Lock()
+	lifecycle[t] = v
+	lifecycleLock.Unlock()
+	return v
+}
+
+// Call the BeforeParam method of the given struct, whose key is key, unless it
+// has already been called during this parse.
+func (p *parser) beforeParam(key string, v reflect.Value) {
+	if p.begun == nil || p.begun[key] {
+		return
+	}
+	p.begun[key] = true
+	if !v.CanAddr() {
+		return
+	}
+	if bp, ok := v.Addr().Interface().(BeforeParamer); ok {
+		bp.BeforeParam()
+	}
+}
+
+// Call the AfterParam method of every struct reachable from v, whose key is
+// key, that BeforeParam would have been called on.
+func runAfterParams(p *parser, key string, v reflect.Value) {
+	t := v.Type()
+	if !hasLifecycle(t) || isLeaf(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			runAfterParams(p, key, v.Elem())
+		}
+		return
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			runAfterParams(p, key+"["+strconv.Itoa(i)+"]", v.Index(i))
+		}
+		return
+	case reflect.Map:
+		for _, mk := range v.MapKeys() {
+			// Map values aren't addressable, so we work on a copy
+			// and put it back afterwards.
+			mv := reflect.New(t.Elem()).Elem()
+			mv.Set(v.MapIndex(mk))
+			// Keys of top-level maps are never bracketed, and so
+			// never escaped.
+			name := mk.String()
+			if key != "" {
+				name = escapeKey(name)
+			}
+			runAfterParams(p, subkey(prefixOf(key), name), mv)
+			v.SetMapIndex(mk, mv)
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+
+	if !p.begun[key] {
+		return
+	}
+	cache := p.cached(t)
+	names := make([]string, 0, len(cache))
+	for name := range cache {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		runAfterParams(p, subkey(prefixOf(key), name),
+			v.Field(cache[name].offset))
+	}
+
+	if !v.CanAddr() {
+		return
+	}
+	ap, ok := v.Addr().Interface().(AfterParamer)
+	if !ok {
+		return
+	}
+	if err := ap.AfterParam(p.fields.under(key)); err != nil {
+		panic(ValidationError{Key: key, Type: t, Err: err})
+	}
+}
+
+// Returns the prefix of the fields of a struct with the given key, as for
+// subkey.
+func prefixOf(key string) string {
+	if key == "" {
+		return ""
+	}
+	return key + "["
+}
+
+// Returns the fields of the set nested within the field with the given key,
+// with keys relative to it.
+func (s FieldSet) under(key string) FieldSet {
+	if key == "" {
+		return s
+	}
+	sub := make(FieldSet)
+	for k := range s {
+		if !strings.HasPrefix(k, key+"[") {
+			continue
+		}
+		// Names are unescaped, as they are at the top level.
+		keytail := k[len(key):]
+		if idx := closingBracket(keytail); idx != -1 {
+			sub[unescapeKey(keytail[1:idx])+keytail[idx+1:]] = struct{}{}
+		}
+	}
+	return sub
+}
@@ -0,0 +1,119 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type SearchRange struct {
+	From int `param:"from"`
+	To   int `param:"to"`
+
+	before int
+	given  []string
+}
+
+func (r *SearchRange) BeforeParam() {
+	r.before++
+	r.To = 100
+}
+
+func (r *SearchRange) AfterParam(fields FieldSet) error {
+	r.given = fields.Keys()
+	if r.From > r.To {
+		return errors.New("empty range")
+	}
+	return nil
+}
+
+type SearchForm struct {
+	Query  string                 `param:"q"`
+	Range  SearchRange            `param:"range"`
+	Ranges map[string]SearchRange `param:"ranges"`
+	Unused *SearchRange           `param:"unused"`
+
+	calls []string
+}
+
+func (s *SearchForm) BeforeParam() {
+	s.calls = append(s.calls, "before")
+	s.Query = "*"
+}
+
+func (s *SearchForm) AfterParam(fields FieldSet) error {
+	s.calls = append(s.calls, "after "+strings.Join(fields.Keys(), ","))
+	s.Query = strings.ToLower(s.Query)
+	return nil
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Parallel()
+
+	s := SearchForm{}
+	err := Parse(url.Values{
+		"q":               {"LLAMAS"},
+		"range[from]":     {"5"},
+		"ranges[a][from]": {"1"},
+		"ranges[a][to]":   {"2"},
+		"ranges[b][from]": {"3"},
+	}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.calls", []string{
+		"before",
+		"after q,range,range[from],ranges,ranges[a][from]," +
+			"ranges[a][to],ranges[b][from]",
+	}, s.calls)
+	assertEqual(t, "s.Query", "llamas", s.Query)
+	assertEqual(t, "s.Range.To", 100, s.Range.To)
+	assertEqual(t, "s.Range.before", 1, s.Range.before)
+	assertEqual(t, "s.Range.given", []string{"from"}, s.Range.given)
+	assertEqual(t, "s.Ranges[a].given", []string{"from", "to"},
+		s.Ranges["a"].given)
+	assertEqual(t, "s.Ranges[a].To", 2, s.Ranges["a"].To)
+	assertEqual(t, "s.Ranges[b].To", 100, s.Ranges["b"].To)
+	assertEqual(t, "s.Ranges[b].before", 1, s.Ranges["b"].before)
+	assertEqual(t, "s.Unused", (*SearchRange)(nil), s.Unused)
+
+	// Without any parameters, the target's own hooks still run.
+	s = SearchForm{}
+	if err := Parse(url.Values{}, &s); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.calls", []string{"before", "after "}, s.calls)
+	assertEqual(t, "s.Range.To", 0, s.Range.To)
+
+	err = Parse(url.Values{"range[from]": {"500"}}, &SearchForm{})
+	verr, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+	assertEqual(t, "verr.Key", "range", verr.Key)
+}
+
+type Bracketed struct {
+	AB    int `param:"a]b"`
+	given []string
+}
+
+func (b *Bracketed) AfterParam(fields FieldSet) error {
+	b.given = fields.Keys()
+	return nil
+}
+
+func TestAfterParamEscapedKeys(t *testing.T) {
+	t.Parallel()
+
+	var s struct {
+		Inner Bracketed `param:"inner"`
+	}
+	err := Parse(url.Values{"inner[a]]b]": {"1"}}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.Inner.AB", 1, s.Inner.AB)
+	assertEqual(t, "s.Inner.given", []string{"a]b"}, s.Inner.given)
+}
@@ -0,0 +1,86 @@
+package param
+
+import (
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// The "maxlen" tag option limits the length, in characters, of the values of
+// string fields (or slices of them, and so on), as in `param:"bio,maxlen=500"`.
+// Longer values are rejected with a LimitError before they are parsed.
+func wrapMaxLen(s reflect.Type, sf reflect.StructField, maxlen string, h parseFunc) parseFunc {
+	if bt := baseType(sf.Type); isLeaf(bt) || bt.Kind() != reflect.String {
+		pebkac("struct %v field %q has the maxlen option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+	max := parseMax(s, sf, "maxlen", maxlen)
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		for _, v := range values {
+			// Counting runes is slow, but there can't be more of
+			// them than bytes.
+			if len(v) > max && utf8.RuneCountInString(v) > max {
+				panic(LimitError{
+					Key:   kpath(key, keytail),
+					Limit: "length",
+					Max:   max,
+				})
+			}
+		}
+		h(p, key, keytail, values, target)
+	}
+}
+
+// The "maxitems" tag option limits the number of elements of slice and map
+// fields, as in `param:"tags,maxitems=10"`. Fields that end up with more are
+// rejected with a LimitError.
+func wrapMaxItems(s reflect.Type, sf reflect.StructField, maxitems string, h parseFunc) parseFunc {
+	t := sf.Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isLeaf(t) || isBytes(t) || t.Kind() != reflect.Slice && t.Kind() != reflect.Map {
+		pebkac("struct %v field %q has the maxitems option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+	max := parseMax(s, sf, "maxitems", maxitems)
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		h(p, key, keytail, values, target)
+		v := target
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Ptr && v.Len() > max {
+			panic(LimitError{
+				Key:   kpath(key, keytail),
+				Limit: "items",
+				Max:   max,
+			})
+		}
+	}
+}
+
+func parseMax(s reflect.Type, sf reflect.StructField, opt, max string) int {
+	n, err := strconv.Atoi(max)
+	if err != nil || n < 0 {
+		pebkac("struct %v field %q has invalid %s %q.", s, sf.Name, opt,
+			max)
+	}
+	return n
+}
+
+// Returns the value of a maxlen or maxitems option, which cacheStruct has
+// already checked, for the benefit of schemas.
+func optionInt(opt string) *int {
+	n, _ := strconv.Atoi(opt)
+	return &n
+}
+
+// Returns the value of a min or max option, which cacheStruct has already
+// checked, for the benefit of schemas.
+func optionFloat(opt string) *float64 {
+	f, _ := strconv.ParseFloat(opt, 64)
+	return &f
+}
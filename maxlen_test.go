@@ -0,0 +1,75 @@
+package param
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type Bio struct {
+	Bio   string            `param:"bio,maxlen=10"`
+	Tags  []string          `param:"tags,maxitems=3,maxlen=4"`
+	Links map[string]string `param:"links,maxitems=2"`
+	Refs  *[]int            `param:"refs,maxitems=1"`
+}
+
+func TestMaxLen(t *testing.T) {
+	t.Parallel()
+
+	p := Bio{}
+	err := Parse(url.Values{
+		"bio":       {"héllo wörl"},
+		"tags[]":    {"a", "b", "cdef"},
+		"links[gh]": {"x"},
+		"links[me]": {"y"},
+		"refs[]":    {"1"},
+	}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Bio", "héllo wörl", p.Bio)
+	assertEqual(t, "p.Tags", []string{"a", "b", "cdef"}, p.Tags)
+
+	tests := []struct {
+		params url.Values
+		err    LimitError
+	}{
+		{url.Values{"bio": {strings.Repeat("x", 11)}},
+			LimitError{Key: "bio", Limit: "length", Max: 10}},
+		{url.Values{"tags[]": {"a", "b", "c", "d"}},
+			LimitError{Key: "tags", Limit: "items", Max: 3}},
+		{url.Values{"tags[5]": {"a"}},
+			LimitError{Key: "tags", Limit: "items", Max: 3}},
+		{url.Values{"tags[]": {"abcde"}},
+			LimitError{Key: "tags", Limit: "length", Max: 4}},
+		{url.Values{"links[a]": {"1"}, "links[b]": {"2"}, "links[c]": {"3"}},
+			LimitError{Key: "links", Limit: "items", Max: 2}},
+		{url.Values{"refs[]": {"1", "2"}},
+			LimitError{Key: "refs", Limit: "items", Max: 1}},
+	}
+	for _, test := range tests {
+		err := Parse(test.params, &Bio{})
+		assertEqual(t, "err", test.err, err)
+	}
+}
+
+func TestMaxLenSchema(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&Bio{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+	var s struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal("Unmarshal error: ", err)
+	}
+	assertEqual(t, "bio", `{"type":"string","maxLength":10}`,
+		string(s.Properties["bio"]))
+	assertEqual(t, "tags",
+		`{"type":"array","maxItems":3,"items":{"type":"string","maxLength":4}}`,
+		string(s.Properties["tags"]))
+}
@@ -0,0 +1,52 @@
+package param
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+)
+
+// Merge combines several sets of parameters, such as those of a route, a
+// request body, and a query string, into one. Sources are given in increasing
+// order of precedence: each key takes its values from the last source that has
+// any, and the values of different sources are never combined. Keys are
+// compared exactly, so "filter" and "filter[name]" are different keys, and may
+// each come from a different source. The sources themselves are left
+// untouched.
+func Merge(overrides ...url.Values) url.Values {
+	params := make(url.Values)
+	for _, source := range overrides {
+		for key, values := range source {
+			params[key] = values
+		}
+	}
+	return params
+}
+
+// ParseSources parses the given sources of parameters into target, as Parse
+// would parse them once combined by Merge, except that keys given values by
+// several sources are subject to the Decoder's DuplicatePolicy: by default,
+// unless every source gives the key the same values, ParseSources returns a
+// ConflictError. With DuplicatesFirst, the first source to give a key values
+// takes precedence instead, and with DuplicatesLast, the last does, as with
+// Merge.
+func (d *Decoder) ParseSources(target interface{}, sources ...url.Values) error {
+	params := make(url.Values)
+	for _, source := range sources {
+		for _, key := range sortedKeys(source) {
+			values := source[key]
+			old, ok := params[key]
+			switch {
+			case !ok:
+			case d.duplicates == DuplicatesFirst:
+				continue
+			case d.duplicates == DuplicatesError &&
+				!reflect.DeepEqual(old, values):
+				return ConflictError{Key: key, Keys: []string{key}}
+			}
+			params[key] = values
+		}
+	}
+	return d.parseContext(context.Background(), "param.Decoder.ParseSources",
+		params, target)
+}
@@ -0,0 +1,53 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	route := url.Values{"id": {"1"}}
+	body := url.Values{"name": {"a"}, "tags[]": {"x", "y"}}
+	query := url.Values{"name": {"b"}, "Struct[A]": {"1"}}
+	assertEqual(t, "Merge", url.Values{
+		"id":        {"1"},
+		"name":      {"b"},
+		"tags[]":    {"x", "y"},
+		"Struct[A]": {"1"},
+	}, Merge(route, body, query))
+	assertEqual(t, "Merge", url.Values{}, Merge())
+	assertEqual(t, "body", url.Values{"name": {"a"}, "tags[]": {"x", "y"}},
+		body)
+}
+
+func TestParseSources(t *testing.T) {
+	t.Parallel()
+
+	body := url.Values{"String": {"a"}, "Int": {"1"}}
+	query := url.Values{"String": {"b"}, "Int": {"1"}, "Uint": {"2"}}
+
+	var e Everything
+	err := NewDecoder().ParseSources(&e, body, query)
+	assertEqual(t, "err", ConflictError{
+		Key:  "String",
+		Keys: []string{"String"},
+	}, err)
+
+	e = Everything{}
+	err = NewDecoder(WithDuplicates(DuplicatesFirst)).ParseSources(&e,
+		body, query)
+	if err != nil {
+		t.Fatal("ParseSources error: ", err)
+	}
+	assertEqual(t, "e", Everything{String: "a", Int: 1, Uint: 2}, e)
+
+	e = Everything{}
+	err = NewDecoder(WithDuplicates(DuplicatesLast)).ParseSources(&e,
+		body, query)
+	if err != nil {
+		t.Fatal("ParseSources error: ", err)
+	}
+	assertEqual(t, "e", Everything{String: "b", Int: 1, Uint: 2}, e)
+}
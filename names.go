@@ -0,0 +1,170 @@
+package param
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// How a Decoder with WithTagNames or SetNameMapper names struct fields.
+type naming struct {
+	// The tags that names are taken from, in order of preference.
+	tags []string
+	// Names fields not named by their tags, if non-nil.
+	mapper func(string) string
+	// Whether fields may also be given under the JSON names in their
+	// "protobuf" tags (see ProfileGRPCGateway).
+	protoJSON bool
+}
+
+// Returns the name of the given struct field, and whether it is to be ignored.
+func (n *naming) name(sf reflect.StructField) (string, bool) {
+	tag := namingTag(sf, n.tags)
+	name, _ := splitTag(tag)
+	if name == "" {
+		name = sf.Name
+		if n.mapper != nil {
+			name = n.mapper(name)
+		}
+	}
+	return name, tag == "-"
+}
+
+// The struct caches of the Decoders with a given way of naming struct fields,
+// which are shared by all of them, so that Decoders that name fields the same
+// way needn't build caches of their own, and Decoders that name fields
+// differently never see each other's names. A nameCache's naming never changes.
+type nameCache struct {
+	naming
+	lock  sync.RWMutex
+	cache map[reflect.Type]structCache
+	// Which of the cached struct types are flat (see isFlat).
+	flat map[reflect.Type]bool
+}
+
+// The nameCaches of every way of naming fields that any Decoder has used, by
+// tag names, mapper, and whether protoJSON is set. Mappers are told apart by
+// their code, since functions aren't comparable.
+var nameCachesLock sync.Mutex
+var nameCaches = make(map[namingKey]*nameCache)
+
+type namingKey struct {
+	tags      string
+	mapper    uintptr
+	protoJSON bool
+}
+
+// Changes the way the Decoder names fields, switching it to the nameCache of
+// the new naming.
+func (d *Decoder) rename(fn func(*naming)) {
+	n := naming{tags: defaultTagNames}
+	if d.names != nil {
+		n = d.names.naming
+	}
+	fn(&n)
+
+	var mapper uintptr
+	if n.mapper != nil {
+		mapper = reflect.ValueOf(n.mapper).Pointer()
+	}
+	if mapper == 0 && !n.protoJSON && slices.Equal(n.tags, defaultTagNames) {
+		d.names = nil
+		return
+	}
+	// Tag names can't contain commas, or they couldn't be listed in tags.
+	key := namingKey{strings.Join(n.tags, ","), mapper, n.protoJSON}
+
+	nameCachesLock.Lock()
+	defer nameCachesLock.Unlock()
+	if nameCaches[key] == nil {
+		n.tags = slices.Clone(n.tags)
+		nameCaches[key] = &nameCache{
+			naming: n,
+			cache:  make(map[reflect.Type]structCache),
+			flat:   make(map[reflect.Type]bool),
+		}
+	}
+	d.names = nameCaches[key]
+}
+
+// WithTagNames returns an Option that sets the struct tags that field names are
+// taken from, in order of preference, in place of "param", "mapstructure", and
+// "json". For instance, WithTagNames("param", "form", "json") allows structs
+// tagged for binding libraries that use "form" tags to be used as they are.
+// Tag options, such as "required", are still only read from "param" tags.
+//
+// Like SetNameMapper, this applies only to parsing with the Decoder.
+func WithTagNames(names ...string) Option {
+	return func(d *Decoder) {
+		d.rename(func(n *naming) {
+			n.tags = names
+		})
+	}
+}
+
+// SetNameMapper sets a function that derives the names of struct fields that
+// are not named by their tags from the names of the fields themselves, in place
+// of using the field names as they are. For instance, d.SetNameMapper(SnakeCase)
+// allows a field named UserID to be given as "user_id" without tagging it.
+//
+// The mapper applies only to parsing with this Decoder: Encode and the other
+// package-level functions go on using unmapped names, as do FieldSet.Columns
+// (see Decoder.Columns) and the names of fields referred to by tag options such
+// as keyfield.
+//
+// Decoders with the same tag names and mapper share their struct caches.
+// Mappers are told apart by their code, so mapper must derive names from field
+// names alone: a closure that maps names differently depending on the variables
+// it captures must not be given to more than one Decoder. SetNameMapper must
+// not be called once the Decoder is in use.
+func (d *Decoder) SetNameMapper(mapper func(fieldName string) string) {
+	d.rename(func(n *naming) {
+		n.mapper = mapper
+	})
+}
+
+// Returns the struct cache for the given type, with field names as this
+// Decoder sees them.
+func (d *Decoder) cached(t reflect.Type) structCache {
+	if d.names == nil {
+		return cacheStruct(t)
+	}
+
+	n := d.names
+	n.lock.RLock()
+	sc, ok := n.cache[t]
+	n.lock.RUnlock()
+	if ok {
+		return sc
+	}
+
+	sc = buildStruct(t, &n.naming)
+
+	n.lock.Lock()
+	n.cache[t] = sc
+	n.lock.Unlock()
+
+	return sc
+}
+
+// SnakeCase converts a Go field name to snake case, for use with
+// SetNameMapper. Runs of capital letters are treated as initialisms, so that
+// "UserID" becomes "user_id" and "HTTPServer" becomes "http_server".
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	out := make([]rune, 0, len(runes)+4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				unicode.IsUpper(prev) && nextLower {
+				out = append(out, '_')
+			}
+		}
+		out = append(out, unicode.ToLower(r))
+	}
+	return string(out)
+}
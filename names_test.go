@@ -0,0 +1,154 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Account struct {
+	UserID     int
+	HTTPServer string
+	Nickname   string `param:"nick"`
+	Home       Place
+	Limit      int `param:",default=10"`
+}
+
+type Place struct {
+	StreetName string
+}
+
+func TestSnakeCase(t *testing.T) {
+	t.Parallel()
+
+	for in, out := range map[string]string{
+		"A":          "a",
+		"Name":       "name",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Address2":   "address2",
+		"V2Name":     "v2_name",
+		"already_ok": "already_ok",
+	} {
+		assertEqual(t, "SnakeCase("+in+")", out, SnakeCase(in))
+	}
+}
+
+func TestNameMapper(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	d.SetNameMapper(SnakeCase)
+
+	a := Account{}
+	err := d.Parse(url.Values{
+		"user_id":           {"1"},
+		"http_server":       {"example.com"},
+		"nick":              {"al"},
+		"home[street_name]": {"Main"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a", Account{
+		UserID:     1,
+		HTTPServer: "example.com",
+		Nickname:   "al",
+		Home:       Place{StreetName: "Main"},
+		Limit:      10,
+	}, a)
+
+	err = d.Parse(url.Values{"UserID": {"1"}}, &a)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+
+	// Other Decoders are unaffected.
+	err = Parse(url.Values{"UserID": {"2"}}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.UserID", 2, a.UserID)
+}
+
+type Binding struct {
+	Name   string `form:"name" json:"full_name"`
+	Email  string `json:"email"`
+	Secret string `form:"-"`
+	Page   int    `param:"p" form:"page"`
+	Sort   string `query:"sort"`
+}
+
+func TestTagNames(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithTagNames("param", "form", "query", "json"))
+	b := Binding{}
+	err := d.Parse(url.Values{
+		"name":  {"Alice"},
+		"email": {"a@example.com"},
+		"p":     {"2"},
+		"sort":  {"asc"},
+	}, &b)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "b", Binding{
+		Name:  "Alice",
+		Email: "a@example.com",
+		Page:  2,
+		Sort:  "asc",
+	}, b)
+
+	for _, key := range []string{"Secret", "full_name", "page"} {
+		err = d.Parse(url.Values{key: {"x"}}, &b)
+		if _, ok := err.(KeyError); !ok {
+			t.Errorf("Expected KeyError for %q, got %v", key, err)
+		}
+	}
+
+	d = NewDecoder(WithTagNames("form"))
+	d.SetNameMapper(SnakeCase)
+	err = d.Parse(url.Values{"email": {"b@example.com"}, "p": {"3"}}, &b)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+	err = d.Parse(url.Values{"email": {"b@example.com"}, "page": {"3"}}, &b)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "b.Email", "b@example.com", b.Email)
+	assertEqual(t, "b.Page", 3, b.Page)
+}
+
+func TestNameCacheIsolation(t *testing.T) {
+	t.Parallel()
+
+	snake := NewDecoder()
+	snake.SetNameMapper(SnakeCase)
+	var a, b Account
+	if err := snake.Parse(url.Values{"user_id": {"1"}}, &a); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	if err := Parse(url.Values{"UserID": {"2"}}, &b); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.UserID", 1, a.UserID)
+	assertEqual(t, "b.UserID", 2, b.UserID)
+
+	// Decoders that name fields the same way share their caches.
+	d1 := NewDecoder(WithTagNames("param", "form"))
+	d2 := NewDecoder(WithTagNames("param", "form"))
+	if d1.names != d2.names {
+		t.Error("Expected Decoders with the same tag names to share caches")
+	}
+	if d1.names == snake.names {
+		t.Error("Expected Decoders naming fields differently not to")
+	}
+	d2.SetNameMapper(SnakeCase)
+	if d1.names == d2.names {
+		t.Error("Expected Decoders with different mappers not to")
+	}
+	if NewDecoder(WithTagNames(defaultTagNames...)).names != nil {
+		t.Error("Expected the usual tag names to use the global cache")
+	}
+}
@@ -0,0 +1,115 @@
+package param
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var tcpAddrType = reflect.TypeOf(net.TCPAddr{})
+var udpAddrType = reflect.TypeOf(net.UDPAddr{})
+
+var errHostNotIP = errors.New("host is not an IP address")
+
+// Split a "host:port" address into its IP address, port, and IPv6 zone. Since
+// we have no business making DNS queries while parsing parameters, the host
+// must be an IP address, or empty.
+func splitAddr(addr string) (net.IP, int, string, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var zone string
+	if i := strings.LastIndexByte(host, '%'); i >= 0 {
+		host, zone = host[:i], host[i+1:]
+	}
+	var ip net.IP
+	if host != "" {
+		if ip = net.ParseIP(host); ip == nil {
+			return nil, 0, "", errHostNotIP
+		}
+	}
+
+	return ip, int(port), zone, nil
+}
+
+func parseTCPAddr(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+
+	ip, port, zone, err := splitAddr(value)
+	if err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  err,
+		})
+	}
+	target.Set(reflect.ValueOf(net.TCPAddr{IP: ip, Port: port, Zone: zone}))
+}
+
+func parseUDPAddr(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+
+	ip, port, zone, err := splitAddr(value)
+	if err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  err,
+		})
+	}
+	target.Set(reflect.ValueOf(net.UDPAddr{IP: ip, Port: port, Zone: zone}))
+}
+
+// The "hostport" tag option requires string fields to be of the form
+// "host:port", where the port is numeric. It may be given a default port, as
+// in `param:"addr,hostport=80"`, which is used when a value has none. The
+// default port also applies to net.TCPAddr and net.UDPAddr fields, which are
+// always parsed as "host:port".
+func wrapHostPort(s reflect.Type, sf reflect.StructField, port string, h parseFunc) parseFunc {
+	switch bt := baseType(sf.Type); {
+	case bt.Kind() == reflect.String:
+	case bt == tcpAddrType || bt == udpAddrType:
+		if port == "" {
+			return h
+		}
+	default:
+		pebkac("struct %v field %q has the hostport option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+	if port != "" {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			pebkac("struct %v field %q has invalid default port %q.",
+				s, sf.Name, port)
+		}
+	}
+
+	return transformValues(h, func(v string) (string, error) {
+		if port != "" && !hasPort(v) {
+			v = net.JoinHostPort(strings.Trim(v, "[]"), port)
+		}
+		_, p, err := net.SplitHostPort(v)
+		if err == nil {
+			_, err = strconv.ParseUint(p, 10, 16)
+		}
+		return v, err
+	})
+}
+
+// Reports whether the given address ends in a port. IPv6 addresses have to be
+// bracketed to be given a port, so otherwise a port is present only if there is
+// exactly one colon.
+func hasPort(addr string) bool {
+	if strings.HasPrefix(addr, "[") {
+		return strings.LastIndexByte(addr, ':') >
+			strings.LastIndexByte(addr, ']')
+	}
+	return strings.Count(addr, ":") == 1
+}
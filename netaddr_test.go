@@ -0,0 +1,66 @@
+package param
+
+import (
+	"net"
+	"net/url"
+	"testing"
+)
+
+type Addresses struct {
+	Upstream string        `param:"upstream,hostport"`
+	Backend  string        `param:"backend,hostport=8080"`
+	Backends []string      `param:"backends,hostport=8080"`
+	TCP      net.TCPAddr   `param:"tcp"`
+	UDP      *net.UDPAddr  `param:"udp,hostport=53"`
+	TCPs     []net.TCPAddr `param:"tcps"`
+}
+
+func TestHostPort(t *testing.T) {
+	t.Parallel()
+
+	a := Addresses{}
+	err := Parse(url.Values{
+		"upstream":   {"example.com:443"},
+		"backend":    {"10.0.0.1"},
+		"backends[]": {"[::1]", "::1", "db:5432"},
+		"tcp":        {"127.0.0.1:80"},
+		"udp":        {"[fe80::1%eth0]"},
+		"tcps[]":     {":9000"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	assertEqual(t, "a.Upstream", "example.com:443", a.Upstream)
+	assertEqual(t, "a.Backend", "10.0.0.1:8080", a.Backend)
+	assertEqual(t, "a.Backends",
+		[]string{"[::1]:8080", "[::1]:8080", "db:5432"}, a.Backends)
+	assertEqual(t, "a.TCP", net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 80,
+	}, a.TCP)
+	assertEqual(t, "a.UDP", &net.UDPAddr{
+		IP:   net.ParseIP("fe80::1"),
+		Port: 53,
+		Zone: "eth0",
+	}, a.UDP)
+	assertEqual(t, "a.TCPs", []net.TCPAddr{{Port: 9000}}, a.TCPs)
+}
+
+func TestHostPortErrors(t *testing.T) {
+	t.Parallel()
+
+	for key, value := range map[string]string{
+		"upstream": "example.com",
+		"backend":  "db:http",
+		"tcp":      "example.com:80",
+		"udp":      "127.0.0.1:99999",
+	} {
+		a := Addresses{}
+		err := Parse(url.Values{key: {value}}, &a)
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError parsing %s=%q, got %v", key,
+				value, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// The "lower", "upper", and "title" tag options convert the values of string
+// fields (or slices of them, and so on) to lower case, upper case, or title case
+// (in which the first letter of each word is upper case and the rest are lower
+// case), as in `param:"email,lower"`. Values are converted before they are
+// parsed, so they are in their final form by the time options such as "oneof"
+// and "pattern" see them. Types implementing encoding.TextUnmarshaler may have
+// these options too.
+func wrapCase(s reflect.Type, sf reflect.StructField, opts tagOptions, h parseFunc) parseFunc {
+	var opt string
+	var fn func(string) (string, error)
+	for _, c := range []struct {
+		opt string
+		fn  func(string) (string, error)
+	}{{"lower", toLower}, {"upper", toUpper}, {"title", toTitle}} {
+		if !opts.has(c.opt) {
+			continue
+		}
+		if fn != nil {
+			pebkac("struct %v field %q has both the %s and %s options.",
+				s, sf.Name, opt, c.opt)
+		}
+		opt, fn = c.opt, c.fn
+	}
+	if bt := baseType(sf.Type); !isLeaf(bt) && bt.Kind() != reflect.String {
+		pebkac("struct %v field %q has the %s option, but is of type %v.",
+			s, sf.Name, opt, sf.Type)
+	}
+	return transformValues(h, fn)
+}
+
+func toLower(v string) (string, error) {
+	return strings.ToLower(v), nil
+}
+
+func toUpper(v string) (string, error) {
+	return strings.ToUpper(v), nil
+}
+
+func toTitle(v string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(v))
+	start := true
+	for _, r := range v {
+		if start {
+			b.WriteRune(unicode.ToTitle(r))
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		start = unicode.IsSpace(r)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,32 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Contact struct {
+	Email   string   `param:"email,lower,trim"`
+	Country string   `param:"country,upper,oneof=US CA FR"`
+	Name    string   `param:"name,title"`
+	Codes   []string `param:"codes,upper,pattern=^[A-Z]+$"`
+}
+
+func TestCase(t *testing.T) {
+	t.Parallel()
+
+	c := Contact{}
+	err := Parse(url.Values{
+		"email":   {"  Alice@Example.COM "},
+		"country": {"fr"},
+		"name":    {"éMILE de LA rue"},
+		"codes[]": {"ab", "Cd"},
+	}, &c)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "c.Email", "alice@example.com", c.Email)
+	assertEqual(t, "c.Country", "FR", c.Country)
+	assertEqual(t, "c.Name", "Émile De La Rue", c.Name)
+	assertEqual(t, "c.Codes", []string{"AB", "CD"}, c.Codes)
+}
@@ -0,0 +1,18 @@
+package param
+
+// WithNullSentinel returns an Option that treats the given value, such as
+// "null", as null for fields that can be null: pointers are set to nil, and
+// Optionals and the nullable types of database/sql are set to their absent or
+// invalid states. For other fields, the sentinel is parsed like any other
+// value.
+func WithNullSentinel(sentinel string) Option {
+	return func(d *Decoder) {
+		d.nullSentinel = &sentinel
+	}
+}
+
+// Reports whether the given values are the Decoder's null sentinel.
+func (p *parser) null(keytail string, values []string) bool {
+	return p.nullSentinel != nil && keytail == "" && len(values) == 1 &&
+		values[0] == *p.nullSentinel
+}
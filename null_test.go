@@ -0,0 +1,50 @@
+package param
+
+import (
+	"database/sql"
+	"net/url"
+	"testing"
+)
+
+type NullableFields struct {
+	Ptr      *int           `param:"ptr"`
+	Optional Optional[int]  `param:"optional"`
+	SQL      sql.NullString `param:"sql"`
+	String   string         `param:"string"`
+}
+
+func TestNullSentinel(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithNullSentinel("null"))
+	one := 1
+	n := NullableFields{
+		Ptr:      &one,
+		Optional: Some(1),
+		SQL:      sql.NullString{String: "x", Valid: true},
+	}
+	err := d.Parse(url.Values{
+		"ptr":      {"null"},
+		"optional": {"null"},
+		"sql":      {"null"},
+		"string":   {"null"},
+	}, &n)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "n", NullableFields{String: "null"}, n)
+
+	err = NewDecoder(WithNullSentinel("~")).Parse(url.Values{
+		"sql": {"null"},
+	}, &n)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "n.SQL", sql.NullString{String: "null", Valid: true},
+		n.SQL)
+
+	err = Parse(url.Values{"ptr": {"null"}}, &n)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
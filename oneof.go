@@ -0,0 +1,132 @@
+package param
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The "oneof" tag option restricts string and integer fields (or slices of
+// them, and so on) to the given space-separated choices, as in
+// `param:"sort,oneof=asc desc"`. Other values are rejected with a TypeError
+// whose underlying error is a ChoiceError listing the choices. Named types,
+// such as enumerations declared as `type Order string`, may have the option
+// too. Integers are compared by value, so that "07" is the choice "7".
+func wrapOneOf(s reflect.Type, sf reflect.StructField, oneof string, h parseFunc) parseFunc {
+	bt := baseType(sf.Type)
+	if isLeaf(bt) || !isChoiceKind(bt.Kind()) {
+		pebkac("struct %v field %q has the oneof option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+	choices := strings.Fields(oneof)
+	if len(choices) == 0 {
+		pebkac("struct %v field %q has the oneof option, but no choices.",
+			s, sf.Name)
+	}
+	allowed := make(map[string]bool, len(choices))
+	for _, c := range choices {
+		v := reflect.New(bt).Elem()
+		if err := setChoice(v, c); err != nil {
+			pebkac("struct %v field %q has invalid choice %q: %v", s,
+				sf.Name, c, err)
+		}
+		allowed[choiceString(v)] = true
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		h(p, key, keytail, values, target)
+		checkChoices(kpath(key, keytail), target, allowed, choices)
+	}
+}
+
+func isChoiceKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// Set v, whose kind is a choice kind, to the choice c.
+func setChoice(v reflect.Value, c string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(c)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(c, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	default:
+		n, err := strconv.ParseUint(c, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	}
+	return nil
+}
+
+// Returns the value of v, whose kind is a choice kind, as a string.
+func choiceString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	}
+	return strconv.FormatUint(v.Uint(), 10)
+}
+
+// Check that the value or values in v, which has the given key, are among the
+// allowed choices.
+func checkChoices(key string, v reflect.Value, allowed map[string]bool, choices []string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			checkChoices(key, v.Elem(), allowed, choices)
+		}
+		return
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			checkChoices(key, v.Index(i), allowed, choices)
+		}
+		return
+	}
+	if !isChoiceKind(v.Kind()) {
+		return
+	}
+	if s := choiceString(v); !allowed[s] {
+		panic(TypeError{
+			Key:  key,
+			Type: v.Type(),
+			Err:  ChoiceError{Value: s, Choices: choices},
+		})
+	}
+}
+
+// Returns the choices given by the field's oneof option, if any, as values of
+// the field's base type t, for the benefit of schemas.
+func enumValues(t reflect.Type, opts tagOptions) []interface{} {
+	oneof, ok := opts["oneof"]
+	if !ok {
+		return nil
+	}
+	var enum []interface{}
+	for _, c := range strings.Fields(oneof) {
+		v := reflect.New(t).Elem()
+		setChoice(v, c)
+		switch v.Kind() {
+		case reflect.String:
+			enum = append(enum, v.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			enum = append(enum, v.Int())
+		default:
+			enum = append(enum, v.Uint())
+		}
+	}
+	return enum
+}
@@ -0,0 +1,94 @@
+package param
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type SortOrder string
+
+type Listing struct {
+	Sort   SortOrder `param:"sort,oneof=asc desc,default=asc"`
+	Fields []string  `param:"fields,oneof=id name price"`
+	Per    *uint8    `param:"per,oneof=10 25 50"`
+	Level  int       `param:"level,oneof=-1 0 1"`
+}
+
+func TestOneOf(t *testing.T) {
+	t.Parallel()
+
+	l := Listing{}
+	err := Parse(url.Values{
+		"fields[]": {"id", "price"},
+		"per":      {"025"},
+		"level":    {"-1"},
+	}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l.Sort", SortOrder("asc"), l.Sort)
+	assertEqual(t, "l.Fields", []string{"id", "price"}, l.Fields)
+	assertEqual(t, "*l.Per", uint8(25), *l.Per)
+	assertEqual(t, "l.Level", -1, l.Level)
+
+	tests := []struct {
+		key, value string
+		err        ChoiceError
+		typ        reflect.Type
+	}{
+		{"sort", "up", ChoiceError{"up", []string{"asc", "desc"}},
+			reflect.TypeOf(SortOrder(""))},
+		{"fields[]", "cost", ChoiceError{"cost", []string{"id", "name", "price"}},
+			reflect.TypeOf("")},
+		{"per", "100", ChoiceError{"100", []string{"10", "25", "50"}},
+			reflect.TypeOf(uint8(0))},
+	}
+	for _, test := range tests {
+		err := Parse(url.Values{test.key: {test.value}}, &Listing{})
+		var te TypeError
+		if !errors.As(err, &te) {
+			t.Errorf("Expected TypeError for %s=%s, got %v", test.key,
+				test.value, err)
+			continue
+		}
+		assertEqual(t, "te.Type", test.typ, te.Type)
+		assertEqual(t, "te.Err", test.err, te.Err)
+		assertEqual(t, "ErrorCode(te.Err)", "choice", ErrorCode(te.Err))
+	}
+}
+
+func TestOneOfSchemas(t *testing.T) {
+	t.Parallel()
+
+	keys, err := Describe(&Listing{})
+	if err != nil {
+		t.Fatal("Describe error: ", err)
+	}
+	assertEqual(t, "keys", []string{
+		"fields[]=<id|name|price>",
+		"level=<-1|0|1>",
+		"per=<10|25|50>",
+		"sort=<asc|desc>",
+	}, keys)
+
+	params, err := OpenAPIParams(&Listing{})
+	if err != nil {
+		t.Fatal("OpenAPIParams error: ", err)
+	}
+	out, err := json.Marshal(params[1].Schema)
+	if err != nil {
+		t.Fatal("Marshal error: ", err)
+	}
+	assertEqual(t, "fields schema",
+		`{"type":"array","items":{"type":"string","enum":["id","name","price"]}}`,
+		string(out))
+	out, err = json.Marshal(params[2].Schema)
+	if err != nil {
+		t.Fatal("Marshal error: ", err)
+	}
+	assertEqual(t, "per schema",
+		`{"type":"integer","minimum":0,"enum":[10,25,50]}`, string(out))
+}
@@ -0,0 +1,250 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// OpenAPIParameter is an OpenAPI 3 parameter object, as produced by
+// OpenAPIParams. It marshals to JSON in the format the OpenAPI specification
+// expects.
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Style    string         `json:"style,omitempty"`
+	Explode  bool           `json:"explode,omitempty"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the (rather small) subset of an OpenAPI 3 schema object
+// needed to describe the values param accepts.
+type OpenAPISchema struct {
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	Default              interface{}               `json:"default,omitempty"`
+	Enum                 []interface{}             `json:"enum,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// OpenAPIParams describes the parameters accepted by the struct pointed to by
+// target as a list of OpenAPI 3 parameter objects, one for each top-level field
+// in the order the fields are declared, followed by those promoted from
+// embedded structs, which are described in place of the embedded structs
+// themselves. Fields with aliases are described once under each of their
+// names, though only their canonical names are ever required. The description
+// is derived from the same struct metadata Parse uses, so the two cannot
+// disagree.
+//
+// Each parameter's location is taken from the field's "in" tag (see Binder),
+// defaulting to "query". Fields tagged with several locations are described
+// once for each, and fields bound from "form" are skipped, since the request
+// body is not a parameter as far as OpenAPI is concerned. Slices are described
+// as exploded "form" style parameters named with a trailing "[]", and maps and
+// structs as "deepObject" style parameters, matching the bracket syntax Parse
+// expects.
+func OpenAPIParams(target interface{}) (params []OpenAPIParameter, err error) {
+	defer recoverError(&err)
+
+	t := targetStruct("param.OpenAPIParams", target).Type()
+	params = []OpenAPIParameter{}
+	for _, f := range reachableFields(t, false, []reflect.Type{t}) {
+		if f.l.promoted {
+			continue
+		}
+		sf := f.s.Field(f.l.offset)
+		sources := sf.Tag.Get("in")
+		if sources == "" {
+			sources = "query"
+		}
+		for _, in := range strings.Split(sources, "|") {
+			if in == "form" {
+				continue
+			}
+			for i, name := range f.names {
+				p := openAPIParam(sf, name, in, f.l)
+				p.Required = p.Required && (i == 0 || in == "path")
+				params = append(params, p)
+			}
+		}
+	}
+
+	return params, nil
+}
+
+func openAPIParam(sf reflect.StructField, name, in string, l cacheLine) OpenAPIParameter {
+	p := OpenAPIParameter{
+		Name: name,
+		In:   in,
+		// OpenAPI insists that path parameters be required, which is
+		// fair enough, since a route can't match without them.
+		Required: requiredIn(l.opts, "") || in == "path",
+		Schema:   openAPIFieldSchema(sf.Type, l, nil),
+	}
+
+	if def, ok := l.opts["default"]; ok {
+		p.Schema.Default = typedDefault(sf.Type, name, def, l)
+	}
+
+	switch p.Schema.Type {
+	case "array":
+		p.Name += "[]"
+		p.Style, p.Explode = "form", true
+	case "object":
+		p.Style, p.Explode = "deepObject", true
+	}
+
+	return p
+}
+
+// Describes a struct field, taking into account tag options that change the
+// shape of the values it accepts.
+func openAPIFieldSchema(t reflect.Type, l cacheLine, seen []reflect.Type) *OpenAPISchema {
+	if l.opts.has("base64") {
+		return &OpenAPISchema{Type: "string", Format: "byte"}
+	}
+	s := openAPISchema(t, seen)
+	if max, ok := l.opts["maxitems"]; ok && s.Type == "array" {
+		s.MaxItems = optionInt(max)
+	}
+	// Choices, patterns, and lengths constrain the elements of slices.
+	es := s
+	for es.Items != nil {
+		es = es.Items
+	}
+	es.Enum = enumValues(baseType(t), l.opts)
+	es.Pattern = l.opts["pattern"]
+	if max, ok := l.opts["maxlen"]; ok {
+		es.MaxLength = optionInt(max)
+	}
+	if min, ok := l.opts["min"]; ok {
+		es.Minimum = optionFloat(min)
+	}
+	if max, ok := l.opts["max"]; ok {
+		es.Maximum = optionFloat(max)
+	}
+	if l.opts.has("keyfield") {
+		// These are given as objects keyed by the key field, not as
+		// arrays.
+		s = &OpenAPISchema{
+			Type:                 "object",
+			AdditionalProperties: s.Items,
+		}
+	}
+	return s
+}
+
+// seen holds the struct types we are in the middle of describing, so that
+// recursive types are described as plain objects instead of recursing forever.
+func openAPISchema(t reflect.Type, seen []reflect.Type) *OpenAPISchema {
+	if isSQLNull(t) || isOptional(t) {
+		return openAPISchema(t.Field(0).Type, seen)
+	}
+	if t == timeType {
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+	if t == urlType {
+		return &OpenAPISchema{Type: "string", Format: "uri"}
+	}
+	if isLeaf(t) {
+		return &OpenAPISchema{Type: "string"}
+	}
+	if isBytes(t) {
+		return &OpenAPISchema{Type: "string", Format: "byte"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Int32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := 0.0
+		return &OpenAPISchema{Type: "integer", Minimum: &min}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Ptr:
+		return openAPISchema(t.Elem(), seen)
+	case reflect.Slice:
+		return &OpenAPISchema{
+			Type:  "array",
+			Items: openAPISchema(t.Elem(), seen),
+		}
+	case reflect.Map:
+		return &OpenAPISchema{
+			Type:                 "object",
+			AdditionalProperties: openAPISchema(t.Elem(), seen),
+		}
+	case reflect.Struct:
+		s := &OpenAPISchema{Type: "object"}
+		for _, st := range seen {
+			if st == t {
+				return s
+			}
+		}
+		seen = append(seen, t)
+
+		s.Properties = make(map[string]*OpenAPISchema)
+		for _, f := range reachableFields(t, false, seen) {
+			if f.l.promoted {
+				continue
+			}
+			fs := openAPIFieldSchema(f.s.Field(f.l.offset).Type,
+				f.l, seen)
+			for _, name := range f.names {
+				s.Properties[name] = fs
+			}
+		}
+		return s
+	}
+
+	// cacheStruct has already complained about anything else.
+	return &OpenAPISchema{}
+}
+
+// Default values are given in the struct tag as strings, but schemas want them
+// to be of the parameter's type.
+func typedDefault(t reflect.Type, name, def string, l cacheLine) interface{} {
+	v := reflect.New(t).Elem()
+	l.parse(defaultParser(), name, "", []string{def}, v)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if isOptional(v.Type()) {
+		v = optionalOf(v).elem()
+	}
+	if pt := reflect.PtrTo(v.Type()); pt.Implements(textUnmarshalerType) ||
+		pt.Implements(flagValueType) {
+		return def
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return def
+}
@@ -0,0 +1,122 @@
+package param
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type Documented struct {
+	ID      int               `param:"id" in:"path"`
+	Limit   int32             `param:"limit,default=25"`
+	Query   string            `param:"q,required"`
+	Tags    []string          `param:"tags"`
+	Filter  map[string]string `param:"filter"`
+	Since   *time.Time        `param:"since" in:"header|query"`
+	Body    string            `param:"body" in:"form"`
+	Ignored bool              `param:"-"`
+}
+
+const documentedJSON = `[` +
+	`{"name":"id","in":"path","required":true,"schema":{"type":"integer"}},` +
+	`{"name":"limit","in":"query","schema":{"type":"integer","format":"int32","default":25}},` +
+	`{"name":"q","in":"query","required":true,"schema":{"type":"string"}},` +
+	`{"name":"tags[]","in":"query","style":"form","explode":true,"schema":{"type":"array","items":{"type":"string"}}},` +
+	`{"name":"filter","in":"query","style":"deepObject","explode":true,"schema":{"type":"object","additionalProperties":{"type":"string"}}},` +
+	`{"name":"since","in":"header","schema":{"type":"string","format":"date-time"}},` +
+	`{"name":"since","in":"query","schema":{"type":"string","format":"date-time"}}` +
+	`]`
+
+func TestOpenAPIParams(t *testing.T) {
+	t.Parallel()
+
+	params, err := OpenAPIParams(&Documented{})
+	if err != nil {
+		t.Fatal("OpenAPIParams error: ", err)
+	}
+	out, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal("Marshal error: ", err)
+	}
+	assertEqual(t, "OpenAPI JSON", documentedJSON, string(out))
+}
+
+func TestOpenAPIParamsRecursive(t *testing.T) {
+	t.Parallel()
+
+	params, err := OpenAPIParams(&Crazy{})
+	if err != nil {
+		t.Fatal("OpenAPIParams error: ", err)
+	}
+	assertEqual(t, "len(params)", 5, len(params))
+	a := params[0].Schema
+	assertEqual(t, "A type", "object", a.Type)
+	assertEqual(t, "A[A] type", "object", a.Properties["A"].Type)
+	if a.Properties["A"].Properties != nil {
+		t.Error("Expected recursive type not to be expanded")
+	}
+}
+
+func TestOpenAPIParamsPromoted(t *testing.T) {
+	t.Parallel()
+
+	params, err := OpenAPIParams(&ListParams{})
+	if err != nil {
+		t.Fatal("OpenAPIParams error: ", err)
+	}
+	var names []string
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	assertEqual(t, "names", []string{
+		"q", "cursor", "place", "limit", "page", "A", "B",
+	}, names)
+}
+
+func TestOpenAPIParamsAliases(t *testing.T) {
+	t.Parallel()
+
+	params, err := OpenAPIParams(&Paging{})
+	if err != nil {
+		t.Fatal("OpenAPIParams error: ", err)
+	}
+	var names, required []string
+	for _, p := range params {
+		names = append(names, p.Name)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	assertEqual(t, "names", []string{
+		"limit", "per_page", "page_size", "filter", "f", "sort[]",
+		"order[]",
+	}, names)
+	assertEqual(t, "required", []string{"sort[]"}, required)
+	assertEqual(t, "filter[title]", "string",
+		params[3].Schema.Properties["title"].Type)
+}
+
+func TestOpenAPIParamsBounds(t *testing.T) {
+	t.Parallel()
+
+	params, err := OpenAPIParams(&Page{})
+	if err != nil {
+		t.Fatal("OpenAPIParams error: ", err)
+	}
+	schemas := make(map[string]string)
+	for _, p := range params {
+		out, err := json.Marshal(p.Schema)
+		if err != nil {
+			t.Fatal("Marshal error: ", err)
+		}
+		schemas[p.Name] = string(out)
+	}
+	assertEqual(t, "limit",
+		`{"type":"integer","minimum":1,"maximum":100,"default":25}`,
+		schemas["limit"])
+	assertEqual(t, "offset", `{"type":"integer","minimum":0,"maximum":1000}`,
+		schemas["offset"])
+	assertEqual(t, "scores[]",
+		`{"type":"array","items":{"type":"integer","minimum":-5,"maximum":5}}`,
+		schemas["scores[]"])
+}
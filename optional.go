@@ -0,0 +1,88 @@
+package param
+
+import "reflect"
+
+// Optional holds a value of type T that may or may not have been provided. An
+// Optional field is parsed exactly as a field of type T would be, but also
+// records whether its key was present at all, distinguishing a missing value
+// from one that was given as the zero value. This makes it a more convenient
+// alternative to pointer fields:
+//
+//	type Filter struct {
+//		Limit param.Optional[int] `param:"limit"`
+//	}
+//
+//	limit := filter.Limit.Or(25)
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Some returns an Optional holding the given value.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+// Value returns the value of the Optional, which is the zero value of T if it
+// is not present.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Present reports whether the Optional holds a value.
+func (o Optional[T]) Present() bool {
+	return o.present
+}
+
+// Or returns the value of the Optional if it is present, and def otherwise.
+func (o Optional[T]) Or(def T) T {
+	if o.present {
+		return o.value
+	}
+	return def
+}
+
+// Since an Optional's fields are unexported, we can't set them through
+// reflection, and instead go through this interface, which every *Optional[T]
+// implements.
+type optional interface {
+	// Returns the settable value the Optional holds.
+	elem() reflect.Value
+	setPresent()
+	Present() bool
+}
+
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+func (o *Optional[T]) elem() reflect.Value {
+	return reflect.ValueOf(&o.value).Elem()
+}
+
+func (o *Optional[T]) setPresent() {
+	o.present = true
+}
+
+func isOptional(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(optionalType)
+}
+
+// Returns an addressable copy of the given Optional, so that we can get at its
+// value.
+func optionalOf(v reflect.Value) optional {
+	if !v.CanAddr() {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+	return v.Addr().Interface().(optional)
+}
+
+func parseOptional(p *parser, key, keytail string, values []string, target reflect.Value) {
+	if p.null(keytail, values) {
+		target.Set(reflect.Zero(target.Type()))
+		return
+	}
+	o := target.Addr().Interface().(optional)
+	parseValue(p, key, keytail, values, o.elem())
+	o.setPresent()
+}
@@ -0,0 +1,81 @@
+package param
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type Filter struct {
+	Limit  Optional[int]              `param:"limit"`
+	Offset Optional[int]              `param:"offset,default=0"`
+	Query  Optional[string]           `param:"q"`
+	Since  Optional[time.Time]        `param:"since"`
+	Tags   Optional[[]string]         `param:"tags"`
+	Sub    Optional[Sub]              `param:"sub"`
+	Extra  map[string]Optional[uint8] `param:"extra"`
+}
+
+func TestOptional(t *testing.T) {
+	t.Parallel()
+
+	var f Filter
+	err := Parse(url.Values{
+		"q":          {""},
+		"since":      {testTimeString},
+		"tags[]":     {"a"},
+		"sub[A]":     {"1"},
+		"extra[one]": {"1"},
+	}, &f)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	assertEqual(t, "f.Limit.Present()", false, f.Limit.Present())
+	assertEqual(t, "f.Limit.Or(25)", 25, f.Limit.Or(25))
+	assertEqual(t, "f.Offset", Some(0), f.Offset)
+	assertEqual(t, "f.Query", Some(""), f.Query)
+	assertEqual(t, "f.Query.Or(\"x\")", "", f.Query.Or("x"))
+	assertEqual(t, "f.Since.Value()", testTime, f.Since.Value())
+	assertEqual(t, "f.Tags", Some([]string{"a"}), f.Tags)
+	assertEqual(t, "f.Sub.Value().A", 1, f.Sub.Value().A)
+	assertEqual(t, "f.Extra", map[string]Optional[uint8]{"one": Some[uint8](1)},
+		f.Extra)
+
+	err = Parse(url.Values{"limit": {"lots"}}, &f)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
+
+func TestOptionalEncode(t *testing.T) {
+	t.Parallel()
+
+	params, err := Encode(Filter{Limit: Some(10)})
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{"limit": {"10"}}, params)
+}
+
+func TestOptionalSchema(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&Filter{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+	var s struct {
+		Properties map[string]struct {
+			Type    string      `json:"type"`
+			Default interface{} `json:"default"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "limit type", "integer", s.Properties["limit"].Type)
+	assertEqual(t, "tags type", "array", s.Properties["tags"].Type)
+	assertEqual(t, "offset default", 0.0, s.Properties["offset"].Default)
+}
@@ -0,0 +1,84 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Buggy struct{}
+
+func (*Buggy) UnmarshalText(text []byte) error {
+	if string(text) == "nil" {
+		var m map[string]int
+		m["boom"]++
+	}
+	panic(string(text))
+}
+
+type Bugged struct {
+	Bug  Buggy `param:"bug"`
+	Deep struct {
+		Bug *Buggy `param:"bug"`
+	} `param:"deep"`
+}
+
+func TestInvalidParse(t *testing.T) {
+	t.Parallel()
+
+	b := Bugged{}
+	err := Parse(url.Values{"bug": {"oops"}}, &b)
+	assertEqual(t, "err", InvalidParseError{
+		Key:   "bug",
+		Type:  reflect.TypeOf(Buggy{}),
+		Value: "oops",
+	}, err)
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected %v to be %v", err, ErrInvalid)
+	}
+
+	// The innermost key is reported.
+	err = Parse(url.Values{"deep[bug]": {"nil"}}, &b)
+	ierr, ok := err.(InvalidParseError)
+	if !ok {
+		t.Fatalf("Expected InvalidParseError, got %v", err)
+	}
+	assertEqual(t, "ierr.Key", "deep[bug]", ierr.Key)
+	assertEqual(t, "ierr.Type", reflect.TypeOf(&Buggy{}), ierr.Type)
+	if ierr.Unwrap() == nil {
+		t.Error("Expected runtime error to be unwrapped")
+	}
+
+	// Errors the parser signals itself are untouched.
+	err = Parse(url.Values{"deep[llama]": {"1"}}, &b)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
+
+// Whatever the query, parsing must fail cleanly, never panic.
+func FuzzParseQuery(f *testing.F) {
+	for _, seed := range []string{
+		"Int=4&Slice[]=1&String=a+b&Struct[A]=5",
+		"A[B][B][A][Value]=1&B[Map][hello][Slice][]=3",
+		"Map[]=1&Slice[99999999999]=1&PPInt=2&Time=x",
+		"[=&]]=[[&%zz&A[&A]=",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, query string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Parsing %q panicked: %v", query, r)
+			}
+		}()
+		ParseQuery(query, &Everything{})
+		ParseQuery(query, &Crazy{})
+		err := ParseQuery(query, &Bugged{})
+		if _, ok := err.(InvalidParseError); ok &&
+			!errors.Is(err, ErrInvalid) {
+			t.Fatalf("Expected %v to be %v", err, ErrInvalid)
+		}
+	})
+}
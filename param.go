@@ -6,55 +6,302 @@ written with the intent of parsing the output of jQuery.param.
 
 This package uses struct tags to guess what names things ought to have. If a
 struct value has a "param" tag defined, it will use that. If there is no "param"
-tag defined, the name part of the "json" tag will be used. If that is not
-defined, the name of the field itself will be used (no case transformation is
-performed).
+tag defined, the name part of the "mapstructure" tag, and then of the "json" tag,
+will be used. If neither is defined, the name of the field itself will be used
+(no case transformation is performed). Decoders may be configured to consult
+other tags (see WithTagNames) and to transform field names (see
+Decoder.SetNameMapper).
 
-If the name derived in this way is the string "-", param will refuse to set that
-value.
+If the tag the name is derived from is exactly "-", param will refuse to set that
+value. As with encoding/json, a field may be given the name "-" with the tag
+"-,".
+
+The name in a "param" tag may be followed by a comma-separated list of options.
+Top-level fields tagged "required" (as in `param:"name,required"`) must be
+present in the parameters, and fields tagged with a default (as in
+`param:"limit,default=25"`) are parsed from the given default value when they
+are not.
+
+Fields may also be required only when other fields of the same struct have
+certain values, as in `param:"state,required_if=country:US|CA"`, or whenever
+any of them is present, as in `param:"cvv,required_with=card|iban"`. Values are
+compared as Encode would give them. These conditions are checked once parsing
+is otherwise complete, and apply to nested structs as well. A field counts as
+present if it was given, even as "0" or "", or if the target already held a
+value other than its zero value.
+
+Closing brackets within map keys are escaped by doubling them, so that any
+string may be used as a map key: "attrs[a]]b]" sets the entry "a]b" of the map
+attrs. Encode escapes map keys in the same way.
 
 The parser is extremely strict, and will return an error if it has any
 difficulty whatsoever in parsing any parameter, or if there is any kind of type
-mismatch.
+mismatch. Keys are processed in sorted order (or, by ParseQuery, in the order
+in which they appear), so the same parameters always produce the same error.
 */
 package param
 
 import (
+	"context"
 	"net/url"
 	"reflect"
+	"runtime"
+	"slices"
 	"strings"
 )
 
 // Parse the given arguments into the the given pointer to a struct object.
-func Parse(params url.Values, target interface{}) (err error) {
-	v := reflect.ValueOf(target)
+//
+// The target may instead be a pointer to a map with string keys, such as a
+// *map[string]string, a *map[string][]int, or a *map[string]SomeStruct. Each
+// key is then parsed as though the map were a field of a struct: the part of
+// the key before the first bracket is the map key, and the rest is parsed into
+// the map's element type, so that "tags[]=a&tags[]=b" sets the "tags" entry of
+// a map[string][]string. Nil maps are allocated.
+func Parse(params url.Values, target interface{}) error {
+	p := defaultParser()
+	defer p.release()
+	return defaultDecoder.decode(p, "param.Parse", params, target)
+}
+
+// ParseContext is like Parse, but passes the given context on to the
+// ContextTextUnmarshalers and ContextValidators it encounters.
+func ParseContext(ctx context.Context, params url.Values, target interface{}) error {
+	p := defaultDecoder.newParser(ctx)
+	defer p.release()
+	return defaultDecoder.decode(p, "param.ParseContext", params, target)
+}
+
+// The guts of Parse, using the given parser. fn names the public entry point,
+// for the sake of error messages.
+func (d *Decoder) decode(p *parser, fn string, params url.Values, target interface{}) (err error) {
+	defer recoverError(&err)
+
+	el, ok := targetMap(target)
+	if !ok {
+		el = targetStruct(fn, target)
+	}
+	t := el.Type()
+	if t.Kind() == reflect.Struct {
+		p.root = t
+		if d.only != nil || d.except != nil {
+			p.checkFieldNames(t)
+		}
+	}
+
+	if d.dottedKeys {
+		params = undotKeys(params)
+		if p.order != nil {
+			p.order = undotOrder(p.order)
+		}
+	}
+	if d.keySplitter != nil {
+		params = d.splitKeys(params)
+		if p.order != nil {
+			p.order = d.splitOrder(p.order)
+		}
+	}
+	d.checkLimits(params)
+	if d.stringFilter != nil {
+		params = d.filterStrings(params)
+	}
+	p.params = params
 
-	defer func() {
-		if r := recover(); r != nil {
-			var ok bool
-			err, ok = r.(error)
-			if !ok {
-				panic(err)
+	keys := p.order
+	if keys == nil {
+		keys = p.sortedKeys()
+	}
+	if d.zeroMissing {
+		p.zeroFields(el)
+	}
+	if p.fields == nil && (isConditional(t) || isValidatable(t)) {
+		// checkRequired and runValidators need to know which fields
+		// were given.
+		p.fields = make(FieldSet)
+	}
+	if hasLifecycle(t) {
+		p.begun = make(map[string]bool)
+		if p.fields == nil {
+			p.fields = make(FieldSet)
+		}
+		p.beforeParam("", el)
+	}
+	if t.Kind() == reflect.Map {
+		for _, key := range keys {
+			sk, keytail := key, ""
+			if i := strings.IndexRune(key, '['); i != -1 {
+				sk, keytail = sk[:i], sk[i:]
 			}
+			parseMapEntry(p, key, sk, keytail, params[key], el)
+		}
+	} else {
+		d.decodeFields(p, keys, el)
+	}
+
+	if p.begun != nil {
+		runAfterParams(p, "", el)
+	}
+	checkRequired(p, "", el)
+	runValidators(p, "", el)
+	if d.validator != nil {
+		if err := d.validator(p.ctx, target); err != nil {
+			panic(d.validationError(t, err))
 		}
-	}()
+	}
+
+	return nil
+}
 
+// Parse the given keys of p.params into the fields of the given struct,
+// then fill in defaults and complain about missing required fields.
+func (d *Decoder) decodeFields(p *parser, keys []string, el reflect.Value) {
+	t := el.Type()
+	cache := d.cached(t)
+
+	// Which fields, by offset, have been given values. Most structs are
+	// small enough for this not to need allocating.
+	var small [64]bool
+	seen := small[:]
+	if n := t.NumField(); n > len(small) {
+		seen = make([]bool, n)
+	}
+	flat := p.flatPath() && d.isFlat(t, cache)
+	if p.concurrent(len(keys)) {
+		p.decodeConcurrently(cache, keys, flat, el, seen)
+	} else {
+		for _, key := range keys {
+			if offset := p.decodeKey(cache, key, flat, el); offset >= 0 {
+				seen[offset] = true
+			}
+		}
+	}
+
+	for name, l := range cache {
+		if seen[l.offset] {
+			continue
+		}
+		if d.filtered() && !p.fieldEnabled(name, t, name, l) {
+			continue
+		}
+		if d.fillZero && filled(el.Field(l.offset)) {
+			continue
+		}
+		if requiredIn(l.opts, d.group) {
+			panic(RequiredError{
+				Key:  name,
+				Type: t.Field(l.offset).Type,
+			})
+		} else if def, ok := l.opts["default"]; ok {
+			l.parse(p, name, "", []string{def}, el.Field(l.offset))
+		}
+	}
+}
+
+// Parse the given key of p.params into the given struct, whose cache is given,
+// and which is flat if flat is set. Returns the offset of the field the key
+// belongs to, or -1 if it doesn't belong to any.
+func (p *parser) decodeKey(cache structCache, key string, flat bool, el reflect.Value) int {
+	values := p.params[key]
+	if l, ok := cache[key]; ok && flat {
+		p.setFlat(key, l, values, el)
+		return l.offset
+	}
+	sk, keytail := key, ""
+	if i := strings.IndexByte(key, '['); i != -1 {
+		sk, keytail = sk[:i], sk[i:]
+	}
+	parseStructField(p, cache, key, sk, keytail, values, el)
+	if _, l, ok := cache.lookup(sk); ok {
+		return l.offset
+	}
+	return -1
+}
+
+// Returns the keys of the given parameters in sorted order, which is the order
+// in which we process them, rather than Go's randomized map order, so that
+// parameters with several errors reliably produce the same one.
+func sortedKeys(params url.Values) []string {
+	return appendSortedKeys(make([]string, 0, len(params)), params)
+}
+
+// Like sortedKeys, but appends the keys to buf, so that callers with a buffer
+// of their own needn't allocate.
+func appendSortedKeys(buf []string, params url.Values) []string {
+	for key := range params {
+		buf = append(buf, key)
+	}
+	slices.Sort(buf)
+	return buf
+}
+
+// Returns the struct pointed to by target, complaining loudly if target is not
+// in fact a pointer to a struct. fn names the public entry point for the sake
+// of the error message.
+func targetStruct(fn string, target interface{}) reflect.Value {
+	v := reflect.ValueOf(target)
 	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
-		pebkac("Target of param.Parse must be a pointer to a struct. "+
-			"We instead were passed a %v", v.Type())
+		pebkac("Target of %s must be a pointer to a struct. "+
+			"We instead were passed a %v", fn, v.Type())
 	}
+	compileStruct(v.Elem().Type())
+	return v.Elem()
+}
 
+// If target is a pointer to a map with string keys, returns the map, allocating
+// it if necessary. Such maps are parsed into as though they were map fields of
+// a struct, with the first part of each key as the map key.
+func targetMap(target interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Map {
+		return reflect.Value{}, false
+	}
 	el := v.Elem()
-	t := el.Type()
-	cache := cacheStruct(t)
+	if el.Type().Key().Kind() != reflect.String {
+		pebkac("key for map %v isn't a string (it's a %v).", el.Type(),
+			el.Type().Key())
+	}
+	if el.IsNil() {
+		el.Set(reflect.MakeMap(el.Type()))
+	}
+	return el, true
+}
 
-	for key, values := range params {
-		sk, keytail := key, ""
-		if i := strings.IndexRune(key, '['); i != -1 {
-			sk, keytail = sk[:i], sk[i:]
+// The parser signals errors by panicking with them. This function, which must
+// be deferred, turns such a panic back into an error stored in *err. Any other
+// panic is turned into an InvalidParseError.
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		if pe, ok := r.(pebkacError); ok {
+			*err = pe.halt()
+		} else if internalPanic(r) {
+			*err = InvalidParseError{Value: r}
+		} else {
+			*err = r.(error)
 		}
-		parseStructField(cache, key, sk, keytail, values, el)
 	}
+}
 
-	return nil
+// Like recoverError, but rather than recovering from panics, it passes them on,
+// turning any that aren't errors signaled by the parser into an
+// InvalidParseError for the given key and type.
+func recoverInternal(key string, t reflect.Type) {
+	if r := recover(); r != nil {
+		if internalPanic(r) {
+			panic(InvalidParseError{Key: key, Type: t, Value: r})
+		}
+		panic(r)
+	}
+}
+
+// Reports whether a recovered panic was caused by something other than the
+// parser signaling an error: by the runtime, by reflect, or by anything that
+// panics with a value that isn't an error.
+func internalPanic(r interface{}) bool {
+	switch r.(type) {
+	case runtime.Error, *reflect.ValueError:
+		return true
+	case error:
+		return false
+	}
+	return true
 }
@@ -1,8 +1,10 @@
 package param
 
 import (
+	"errors"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -285,6 +287,50 @@ func TestMapTyped(t *testing.T) {
 	assertEqual(t, "e.AMap[one]", MyInt(1), e.AMap[MyString("one")])
 }
 
+func TestMapOfSlices(t *testing.T) {
+	t.Parallel()
+
+	var f struct {
+		Filters map[string][]string `param:"filters"`
+	}
+	err := Parse(url.Values{
+		"filters[color][]": {"red", "blue"},
+		"filters[size][0]": {"s"},
+		"filters[size][1]": {"m"},
+	}, &f)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "f.Filters", map[string][]string{
+		"color": {"red", "blue"},
+		"size":  {"s", "m"},
+	}, f.Filters)
+}
+
+func TestMapOfStructs(t *testing.T) {
+	t.Parallel()
+
+	var f struct {
+		Subs  map[string]Sub  `param:"subs"`
+		PSubs map[string]*Sub `param:"psubs"`
+	}
+	err := Parse(url.Values{
+		"subs[a][A]":  {"1"},
+		"subs[a][B]":  {"2"},
+		"subs[b][B]":  {"3"},
+		"psubs[a][A]": {"4"},
+		"psubs[a][B]": {"5"},
+	}, &f)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "f.Subs", map[string]Sub{
+		"a": {A: 1, B: 2},
+		"b": {B: 3},
+	}, f.Subs)
+	assertEqual(t, "f.PSubs[a]", Sub{A: 4, B: 5}, *f.PSubs["a"])
+}
+
 func TestMapErrors(t *testing.T) {
 	t.Parallel()
 	e := Everything{}
@@ -409,6 +455,84 @@ func TestSliceErrors(t *testing.T) {
 	}
 }
 
+type Nested struct {
+	Matrix [][]int             `param:"matrix"`
+	Subs   []Sub               `param:"subs"`
+	Maps   []map[string]string `param:"maps"`
+}
+
+func TestSliceIndexed(t *testing.T) {
+	t.Parallel()
+
+	n := Nested{Subs: []Sub{{A: 1, B: 2}}}
+	err := Parse(url.Values{
+		"matrix[0][]": {"1", "2"},
+		"matrix[2][]": {"3"},
+		"subs[0][A]":  {"3"},
+		"subs[1][B]":  {"4"},
+		"maps[0][a]":  {"b"},
+	}, &n)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "n.Matrix", [][]int{{1, 2}, nil, {3}}, n.Matrix)
+	assertEqual(t, "n.Subs", []Sub{{A: 3, B: 2}, {B: 4}}, n.Subs)
+	assertEqual(t, "n.Maps", []map[string]string{{"a": "b"}}, n.Maps)
+
+	e := Everything{}
+	err = Parse(url.Values{"Slice[1]": {"5"}}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Slice", []int{0, 5}, e.Slice)
+}
+
+func TestSliceGrowthLimit(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{}
+	for i := 0; i < 20; i++ {
+		params.Set("matrix["+strconv.Itoa(i)+"][10000]", "1")
+	}
+	err := Parse(params, &Nested{})
+	var lerr LimitError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("Expected LimitError, got %v", err)
+	}
+	assertEqual(t, "lerr.Limit", "slice growth", lerr.Limit)
+	assertEqual(t, "lerr.Max", maxSliceGrowth, lerr.Max)
+}
+
+func TestSliceIndexedErrors(t *testing.T) {
+	t.Parallel()
+
+	for key, expected := range map[string]error{
+		"matrix[10001][]": LimitError{
+			Key:   "matrix[10001]",
+			Limit: "slice index",
+			Max:   maxSliceIndex,
+		},
+		"matrix[99999999999999999999][]": LimitError{
+			Key:   "matrix[99999999999999999999]",
+			Limit: "slice index",
+			Max:   maxSliceIndex,
+		},
+		"matrix[0][x]": NestingError{
+			Key:     "matrix[0]",
+			Type:    reflect.TypeOf([]int{}),
+			Nesting: "[x]",
+		},
+		"matrix[-1][]": NestingError{
+			Key:     "matrix",
+			Type:    reflect.TypeOf([][]int{}),
+			Nesting: "[-1][]",
+		},
+	} {
+		err := Parse(url.Values{key: {"1"}}, &Nested{})
+		assertEqual(t, key, expected, err)
+	}
+}
+
 var stringAnswer = "This is the world's best string"
 
 func TestString(t *testing.T) {
@@ -507,3 +631,147 @@ func TestTextUnmarshalerError(t *testing.T) {
 		t.Error("expected error parsing llama as time")
 	}
 }
+
+type Defaulted struct {
+	Limit int    `param:"limit,default=25"`
+	Sort  string `param:"sort,default=asc"`
+	Query string `param:"q,required"`
+}
+
+func TestDefault(t *testing.T) {
+	t.Parallel()
+
+	d := Defaulted{}
+	err := Parse(url.Values{"q": {"llama"}, "sort": {"desc"}}, &d)
+	if err != nil {
+		t.Error("Parse error with defaults: ", err)
+	}
+	assertEqual(t, "d.Limit", 25, d.Limit)
+	assertEqual(t, "d.Sort", "desc", d.Sort)
+	assertEqual(t, "d.Query", "llama", d.Query)
+}
+
+func TestRequired(t *testing.T) {
+	t.Parallel()
+
+	d := Defaulted{}
+	err := Parse(url.Values{"limit": {"4"}}, &d)
+	if rerr, ok := err.(RequiredError); !ok {
+		t.Errorf("Expected RequiredError, got %v", err)
+	} else {
+		assertEqual(t, "rerr.Key", "q", rerr.Key)
+	}
+}
+
+func TestKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	// Every key is in error, but the first in sorted order is the one
+	// that's reported, every time.
+	for i := 0; i < 20; i++ {
+		e := Everything{}
+		err := Parse(url.Values{
+			"Uint":  {"x"},
+			"Int":   {"y"},
+			"Float": {"z"},
+		}, &e)
+		terr, ok := err.(TypeError)
+		if !ok {
+			t.Fatalf("Expected TypeError, got %v", err)
+		}
+		assertEqual(t, "terr.Key", "Float", terr.Key)
+	}
+}
+
+func TestMapTarget(t *testing.T) {
+	t.Parallel()
+
+	var m map[string]string
+	err := Parse(url.Values{"a": {"1"}, "b": {"2"}}, &m)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "m", map[string]string{"a": "1", "b": "2"}, m)
+
+	err = Parse(url.Values{"a[b]": {"1"}}, &m)
+	assertEqual(t, "err", NestingError{
+		Key:     "a",
+		Type:    reflect.TypeOf(""),
+		Nesting: "[b]",
+	}, err)
+
+	ms := map[string][]int{"old": {1}}
+	err = Parse(url.Values{"a[]": {"1", "2"}, "b[1]": {"3"}}, &ms)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "ms", map[string][]int{
+		"old": {1},
+		"a":   {1, 2},
+		"b":   {0, 3},
+	}, ms)
+
+	var subs map[string]Sub
+	err = Parse(url.Values{"x[A]": {"1"}, "x[B]": {"2"}, "y[A]": {"3"}}, &subs)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "subs", map[string]Sub{"x": {1, 2}, "y": {3, 0}}, subs)
+
+	err = Parse(url.Values{"x": {"1"}}, &subs)
+	if _, ok := err.(SyntaxError); !ok {
+		t.Errorf("Expected SyntaxError, got %v", err)
+	}
+
+	// Validate parses into a fresh map.
+	err = Validate(url.Values{"z[A]": {"x"}}, &subs)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+	assertEqual(t, "len(subs)", 2, len(subs))
+}
+
+func TestEscapedBrackets(t *testing.T) {
+	t.Parallel()
+
+	s := struct {
+		Attrs map[string]string
+		Subs  map[string]Sub
+	}{}
+	err := Parse(url.Values{
+		"Attrs[data[id]]]": {"1"},
+		"Attrs[]]]]]":      {"2"},
+		"Attrs[a]]b]":      {"3"},
+		"Subs[x]]][A]":     {"4"},
+		"Subs[[y][B]":      {"5"},
+	}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.Attrs", map[string]string{
+		"data[id]": "1",
+		"]]":       "2",
+		"a]b":      "3",
+	}, s.Attrs)
+	assertEqual(t, "s.Subs", map[string]Sub{
+		"x]": {A: 4},
+		"[y": {B: 5},
+	}, s.Subs)
+
+	params, err := Encode(s)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	u := s
+	u.Attrs, u.Subs = nil, nil
+	if err := Parse(params, &u); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "u", s, u)
+
+	err = Parse(url.Values{"Attrs[a]]": {"1"}}, &s)
+	if serr, ok := err.(SyntaxError); !ok ||
+		serr.Subtype != MissingClosingBracket {
+		t.Errorf("Expected MissingClosingBracket, got %v", err)
+	}
+}
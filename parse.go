@@ -1,47 +1,119 @@
 package param
 
 import (
+	"context"
 	"encoding"
-	"fmt"
+	"errors"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
+	"unsafe"
 )
 
 var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var contextTextUnmarshalerType = reflect.TypeOf((*ContextTextUnmarshaler)(nil)).Elem()
+
+// ContextTextUnmarshaler is like encoding.TextUnmarshaler, but its
+// UnmarshalTextContext method is also passed the context given to ParseContext
+// (or context.Background(), for Parse), allowing its parsing to depend on
+// request-scoped data. Types implementing both interfaces are parsed with
+// UnmarshalTextContext.
+type ContextTextUnmarshaler interface {
+	UnmarshalTextContext(ctx context.Context, text []byte) error
+}
 
 // Generic parse dispatcher. This function's signature is the interface of all
-// parse functions. `key` is the entire key that is currently being parsed, such
-// as "foo[bar][]". `keytail` is the portion of the string that the current
-// parser is responsible for, for instance "[bar][]". `values` is the list of
-// values assigned to this key, and `target` is where the resulting typed value
-// should be Set() to.
-func parse(key, keytail string, values []string, target reflect.Value) {
+// parse functions. `p` holds the state of the parse as a whole. `key` is the
+// entire key that is currently being parsed, such as "foo[bar][]". `keytail` is
+// the portion of the string that the current parser is responsible for, for
+// instance "[bar][]". `values` is the list of values assigned to this key, and
+// `target` is where the resulting typed value should be Set() to.
+func parse(p *parser, key, keytail string, values []string, target reflect.Value) {
+	if p.hooks != nil && target.Kind() != reflect.Ptr {
+		var done bool
+		if values, done = p.runHooks(key, keytail, values, target); done {
+			return
+		}
+	}
+	parseValue(p, key, keytail, values, target)
+}
+
+// Like parse, but without running the Decoder's hooks, for values whose hooks
+// have been run on whatever holds them already, such as the value of an
+// Optional.
+func parseValue(p *parser, key, keytail string, values []string, target reflect.Value) {
 	t := target.Type()
+	if isBig(t) {
+		parseBig(p, key, keytail, values, target)
+		return
+	}
+	if reflect.PtrTo(t).Implements(contextTextUnmarshalerType) {
+		parseContextTextUnmarshaler(p, key, keytail, values, target)
+		return
+	}
 	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
-		parseTextUnmarshaler(key, keytail, values, target)
+		parseTextUnmarshaler(p, key, keytail, values, target)
+		return
+	}
+	if reflect.PtrTo(t).Implements(flagValueType) {
+		parseFlagValue(p, key, keytail, values, target)
+		return
+	}
+
+	switch t {
+	case tcpAddrType:
+		parseTCPAddr(p, key, keytail, values, target)
+		return
+	case udpAddrType:
+		parseUDPAddr(p, key, keytail, values, target)
+		return
+	case rawMessageType:
+		parseRawMessage(p, key, keytail, values, target)
+		return
+	case urlType:
+		parseURL(p, key, keytail, values, target)
+		return
+	case mailAddressType:
+		parseMailAddress(p, key, keytail, values, target)
+		return
+	}
+	if isSQLNull(t) {
+		parseSQLNull(p, key, keytail, values, target)
+		return
+	}
+	if isOptional(t) {
+		parseOptional(p, key, keytail, values, target)
+		return
+	}
+	if isBytes(t) {
+		parseBytes(p, key, keytail, values, target)
+		return
+	}
+	if p.jsonUnmarshalers && keytail == "" && isJSONUnmarshaler(t) {
+		parseJSONUnmarshaler(p, key, keytail, values, target)
 		return
 	}
 
 	switch k := target.Kind(); k {
 	case reflect.Bool:
-		parseBool(key, keytail, values, target)
+		parseBool(p, key, keytail, values, target)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		parseInt(key, keytail, values, target)
+		parseInt(p, key, keytail, values, target)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		parseUint(key, keytail, values, target)
+		parseUint(p, key, keytail, values, target)
 	case reflect.Float32, reflect.Float64:
-		parseFloat(key, keytail, values, target)
+		parseFloat(p, key, keytail, values, target)
 	case reflect.Map:
-		parseMap(key, keytail, values, target)
+		parseMap(p, key, keytail, values, target)
 	case reflect.Ptr:
-		parsePtr(key, keytail, values, target)
+		parsePtr(p, key, keytail, values, target)
 	case reflect.Slice:
-		parseSlice(key, keytail, values, target)
+		parseSlice(p, key, keytail, values, target)
 	case reflect.String:
-		parseString(key, keytail, values, target)
+		parseString(p, key, keytail, values, target)
 	case reflect.Struct:
-		parseStruct(key, keytail, values, target)
+		parseStruct(p, key, keytail, values, target)
 
 	default:
 		pebkac("unsupported object of type %v and kind %v.",
@@ -49,6 +121,18 @@ func parse(key, keytail string, values []string, target reflect.Value) {
 	}
 }
 
+// Reports whether the given type is parsed from a single value, regardless of
+// its kind.
+func isLeaf(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return pt.Implements(contextTextUnmarshalerType) ||
+		pt.Implements(textUnmarshalerType) ||
+		pt.Implements(flagValueType) ||
+		t == tcpAddrType || t == udpAddrType || t == rawMessageType ||
+		t == urlType || t == mailAddressType ||
+		isSQLNull(t) || isOptional(t)
+}
+
 // We pass down both the full key ("foo[bar][]") and the part the current layer
 // is responsible for making sense of ("[bar][]"). This computes the other thing
 // you probably want to know, which is the path you took to get here ("foo").
@@ -58,8 +142,27 @@ func kpath(key, keytail string) string {
 }
 
 // Helper for validating that a value has been passed exactly once, and that the
-// user is not attempting to nest on the key.
-func primitive(key, keytail string, tipe reflect.Type, values []string) {
+// user is not attempting to nest on the key. Returns the value, or if the
+// Decoder's duplicate policy allows several values, the one that wins, trimmed
+// if the Decoder calls for it.
+func (p *parser) primitive(key, keytail string, tipe reflect.Type, values []string) string {
+	v := p.primitiveValue(key, keytail, tipe, values)
+	if p.trimSpace {
+		if t := strings.TrimSpace(v); t != v {
+			p.warn(WarnTrimmed, kpath(key, keytail), v)
+			v = t
+		}
+	}
+	return v
+}
+
+// Reports whether v is empty, once it has been trimmed if the Decoder calls for
+// it.
+func (p *parser) blank(v string) bool {
+	return v == "" || p.trimSpace && strings.TrimSpace(v) == ""
+}
+
+func (p *parser) primitiveValue(key, keytail string, tipe reflect.Type, values []string) string {
 	if keytail != "" {
 		panic(NestingError{
 			Key:     kpath(key, keytail),
@@ -67,6 +170,17 @@ func primitive(key, keytail string, tipe reflect.Type, values []string) {
 			Nesting: keytail,
 		})
 	}
+	if len(values) > 1 {
+		switch p.duplicates {
+		case DuplicatesFirst:
+			p.warn(WarnDuplicates, kpath(key, keytail), values[1:]...)
+			return values[0]
+		case DuplicatesLast:
+			p.warn(WarnDuplicates, kpath(key, keytail),
+				values[:len(values)-1]...)
+			return values[len(values)-1]
+		}
+	}
 	if len(values) != 1 {
 		panic(SingletonError{
 			Key:    kpath(key, keytail),
@@ -74,6 +188,7 @@ func primitive(key, keytail string, tipe reflect.Type, values []string) {
 			Values: values,
 		})
 	}
+	return values[0]
 }
 
 func keyed(tipe reflect.Type, key, keytail string) (string, string) {
@@ -82,26 +197,59 @@ func keyed(tipe reflect.Type, key, keytail string) (string, string) {
 			Key:       kpath(key, keytail),
 			Subtype:   MissingOpeningBracket,
 			ErrorPart: keytail,
+			FullKey:   key,
+			Offset:    len(key) - len(keytail),
+			Remainder: keytail,
 		})
 	}
 
-	idx := strings.IndexRune(keytail, ']')
+	idx := closingBracket(keytail)
 	if idx == -1 {
 		panic(SyntaxError{
 			Key:       kpath(key, keytail),
 			Subtype:   MissingClosingBracket,
 			ErrorPart: keytail[1:],
+			FullKey:   key,
+			Offset:    len(key) - len(keytail),
+			Remainder: keytail,
 		})
 	}
 
-	return keytail[1:idx], keytail[idx+1:]
+	return unescapeKey(keytail[1:idx]), keytail[idx+1:]
+}
+
+// Closing brackets within a key are escaped by doubling them, so that any
+// string may be used as a map key: "m[a]]b]" sets the entry "a]b" of m. Returns
+// the index of the bracket that closes the one keytail begins with, or -1 if
+// there is none.
+func closingBracket(keytail string) int {
+	for i := 1; i < len(keytail); i++ {
+		if keytail[i] != ']' {
+			continue
+		}
+		if i+1 < len(keytail) && keytail[i+1] == ']' {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
 }
 
-func parseTextUnmarshaler(key, keytail string, values []string, target reflect.Value) {
-	primitive(key, keytail, target.Type(), values)
+// Escapes any closing brackets in the given key (see closingBracket).
+func escapeKey(key string) string {
+	return strings.ReplaceAll(key, "]", "]]")
+}
+
+func unescapeKey(key string) string {
+	return strings.ReplaceAll(key, "]]", "]")
+}
+
+func parseTextUnmarshaler(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
 
 	tu := target.Addr().Interface().(encoding.TextUnmarshaler)
-	err := tu.UnmarshalText([]byte(values[0]))
+	err := tu.UnmarshalText([]byte(value))
 	if err != nil {
 		panic(TypeError{
 			Key:  kpath(key, keytail),
@@ -111,80 +259,180 @@ func parseTextUnmarshaler(key, keytail string, values []string, target reflect.V
 	}
 }
 
-func parseBool(key, keytail string, values []string, target reflect.Value) {
-	primitive(key, keytail, target.Type(), values)
+func parseContextTextUnmarshaler(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
 
-	switch values[0] {
-	case "true", "1", "on":
-		target.SetBool(true)
-	case "false", "0", "":
-		target.SetBool(false)
-	default:
+	tu := target.Addr().Interface().(ContextTextUnmarshaler)
+	err := tu.UnmarshalTextContext(p.ctx, []byte(value))
+	if err != nil {
 		panic(TypeError{
 			Key:  kpath(key, keytail),
 			Type: target.Type(),
+			Err:  err,
 		})
 	}
 }
 
-func parseInt(key, keytail string, values []string, target reflect.Value) {
-	t := target.Type()
-	primitive(key, keytail, t, values)
+func parseBool(p *parser, key, keytail string, values []string, target reflect.Value) {
+	target.SetBool(p.boolOf(key, keytail, target.Type(), values))
+}
+
+// The parse functions for primitive types are split in two: one half parses
+// the value for a field of type t, and the other sets the field, so that the
+// unsafe fast path (see WithUnsafeFields) can share the first half.
+func (p *parser) boolOf(key, keytail string, t reflect.Type, values []string) bool {
+	value := p.primitive(key, keytail, t, values)
+	if p.foldBools {
+		value = strings.ToLower(value)
+	}
+
+	switch value {
+	case "true", "1", "on":
+		return true
+	case "false", "0", "":
+		return false
+	}
+	if b, ok := p.boolValue(value); ok {
+		return b
+	}
+	panic(TypeError{
+		Key:  kpath(key, keytail),
+		Type: t,
+	})
+}
+
+func parseInt(p *parser, key, keytail string, values []string, target reflect.Value) {
+	target.SetInt(p.intOf(key, keytail, target.Type(), values))
+}
+
+func (p *parser) intOf(key, keytail string, t reflect.Type, values []string) int64 {
+	value := p.primitive(key, keytail, t, values)
+	if value == "" && p.emptyZero {
+		p.warn(WarnEmptyZero, kpath(key, keytail))
+		return 0
+	}
 
-	i, err := strconv.ParseInt(values[0], 10, t.Bits())
+	i, err := strconv.ParseInt(p.ungroup(value), p.intBase(), t.Bits())
 	if err != nil {
 		panic(TypeError{
 			Key:  kpath(key, keytail),
 			Type: t,
-			Err:  err,
+			Err:  rangeError(t, err),
 		})
 	}
-	target.SetInt(i)
+	return i
 }
 
-func parseUint(key, keytail string, values []string, target reflect.Value) {
-	t := target.Type()
-	primitive(key, keytail, t, values)
+func parseUint(p *parser, key, keytail string, values []string, target reflect.Value) {
+	target.SetUint(p.uintOf(key, keytail, target.Type(), values))
+}
+
+func (p *parser) uintOf(key, keytail string, t reflect.Type, values []string) uint64 {
+	value := p.primitive(key, keytail, t, values)
+	if value == "" && p.emptyZero {
+		p.warn(WarnEmptyZero, kpath(key, keytail))
+		return 0
+	}
 
-	i, err := strconv.ParseUint(values[0], 10, t.Bits())
+	i, err := strconv.ParseUint(p.ungroup(value), p.intBase(), t.Bits())
 	if err != nil {
 		panic(TypeError{
 			Key:  kpath(key, keytail),
 			Type: t,
-			Err:  err,
+			Err:  rangeError(t, err),
 		})
 	}
-	target.SetUint(i)
+	return i
 }
 
-func parseFloat(key, keytail string, values []string, target reflect.Value) {
-	t := target.Type()
-	primitive(key, keytail, t, values)
+func parseFloat(p *parser, key, keytail string, values []string, target reflect.Value) {
+	target.SetFloat(p.floatOf(key, keytail, target.Type(), values))
+}
 
-	f, err := strconv.ParseFloat(values[0], t.Bits())
+func (p *parser) floatOf(key, keytail string, t reflect.Type, values []string) float64 {
+	value := p.ungroup(p.primitive(key, keytail, t, values))
+	if value == "" && p.emptyZero {
+		p.warn(WarnEmptyZero, kpath(key, keytail))
+		return 0
+	}
+
+	var err error
+	if p.decimalComma {
+		value, err = decimalComma(value)
+	}
+	var f float64
+	if err == nil {
+		f, err = strconv.ParseFloat(value, t.Bits())
+	}
 	if err != nil {
 		panic(TypeError{
 			Key:  kpath(key, keytail),
 			Type: t,
-			Err:  err,
+			Err:  rangeError(t, err),
 		})
 	}
+	return f
+}
+
+// If err reports that a number was out of range for the numeric type t, wrap it
+// in a RangeError giving the bounds of t.
+func rangeError(t reflect.Type, err error) error {
+	if !errors.Is(err, strconv.ErrRange) {
+		return err
+	}
+	min, max := typeBounds(t)
+	return RangeError{Min: min, Max: max, Err: err}
+}
 
-	target.SetFloat(f)
+// Returns the smallest and largest values of the numeric type t, as a
+// RangeError gives them.
+func typeBounds(t reflect.Type) (min, max interface{}) {
+	bits := uint(t.Bits())
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int64(-1) << (bits - 1), int64(1)<<(bits-1) - 1
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint64(0), uint64(math.MaxUint64) >> (64 - bits)
+	}
+	fmax := math.MaxFloat64
+	if bits == 32 {
+		fmax = math.MaxFloat32
+	}
+	return -fmax, fmax
 }
 
-func parseString(key, keytail string, values []string, target reflect.Value) {
-	primitive(key, keytail, target.Type(), values)
+func parseString(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
 
-	target.SetString(values[0])
+	p.alloc(kpath(key, keytail), len(value))
+	target.SetString(value)
 }
 
-func parseSlice(key, keytail string, values []string, target reflect.Value) {
+// Slices are usually given all at once, as in "foo[]=1&foo[]=2", which replaces
+// the entire slice. Elements may also be addressed individually by index, as in
+// "foo[0][]=1&foo[1][bar]=2", which is how slices of slices, maps, and structs
+// are given. The slice is grown as necessary to accommodate the index, but the
+// index may not exceed maxSliceIndex, nor may the slices grown this way add up
+// to more than maxSliceGrowth elements over the course of a parse.
+func parseSlice(p *parser, key, keytail string, values []string, target reflect.Value) {
 	t := target.Type()
 
-	// BUG(carl): We currently do not handle slices of nested types. If
-	// support is needed, the implementation probably could be fleshed out.
-	if keytail != "[]" {
+	if i, rest, ok := sliceIndex(keytail); ok {
+		if i > maxSliceIndex {
+			panic(LimitError{
+				Key:   key[:len(key)-len(rest)],
+				Limit: "slice index",
+				Max:   maxSliceIndex,
+			})
+		}
+		if i >= target.Len() {
+			p.growSlice(key[:len(key)-len(rest)], i+1, target)
+		}
+		parse(p, key, rest, values, target.Index(i))
+		return
+	}
+
+	if keytail != "[]" && !(p.repeatedKeys && keytail == "") {
 		panic(NestingError{
 			Key:     kpath(key, keytail),
 			Type:    t,
@@ -192,58 +440,180 @@ func parseSlice(key, keytail string, values []string, target reflect.Value) {
 		})
 	}
 
-	slice := reflect.MakeSlice(t, len(values), len(values))
+	// When appending, the new values go after the ones already there.
+	start := 0
+	if p.appendSlices {
+		start = target.Len()
+	}
 	kp := kpath(key, keytail)
+	p.allocValues(kp, t.Elem(), start+len(values))
+	slice := reflect.MakeSlice(t, start+len(values), start+len(values))
+	if start > 0 {
+		reflect.Copy(slice, target)
+	}
+	// We actually cheat a little bit and modify the key so we can generate
+	// better debugging messages later. The keys of all the elements share a
+	// single buffer, which is allocated at its full size up front so that
+	// the strings we make of it are never moved out from under us.
+	size := 0
 	for i := range values {
-		// We actually cheat a little bit and modify the key so we can
-		// generate better debugging messages later
-		key := fmt.Sprintf("%s[%d]", kp, i)
-		parse(key, "", values[i:i+1], slice.Index(i))
+		size += len(kp) + len("[]") + decimalLen(i)
+	}
+	buf := make([]byte, 0, size)
+	for i := range values {
+		n := len(buf)
+		buf = append(buf, kp...)
+		buf = append(buf, '[')
+		buf = strconv.AppendInt(buf, int64(i), 10)
+		buf = append(buf, ']')
+		key := unsafe.String(&buf[n], len(buf)-n)
+		parse(p, key, "", values[i:i+1], slice.Index(start+i))
 	}
 	target.Set(slice)
 }
 
-func parseMap(key, keytail string, values []string, target reflect.Value) {
+// Grow the given slice to length n. Indices often arrive in lexical order
+// ("[1]", "[10]", "[100]", "[11]"), so we grow slices geometrically, as append
+// would, lest this be quadratic. The spare capacity of slices we didn't
+// allocate ourselves may belong to someone else, so we only ever make use of
+// our own.
+func (p *parser) growSlice(key string, n int, target reflect.Value) {
+	if n <= target.Cap() && p.grown[target.Pointer()] {
+		target.SetLen(n)
+		return
+	}
+
+	c := n
+	if d := 2 * target.Len(); d > c && d <= maxSliceIndex+1 {
+		c = d
+	}
+	p.indexed += c
+	if p.indexed > maxSliceGrowth {
+		panic(LimitError{Key: key, Limit: "slice growth", Max: maxSliceGrowth})
+	}
+	p.allocValues(key, target.Type().Elem(), c)
+	slice := reflect.MakeSlice(target.Type(), n, c)
+	reflect.Copy(slice, target)
+	target.Set(slice)
+
+	if p.grown == nil {
+		p.grown = make(map[uintptr]bool)
+	}
+	p.grown[slice.Pointer()] = true
+}
+
+// Returns the number of decimal digits in i, which must not be negative.
+func decimalLen(i int) int {
+	n := 1
+	for ; i >= 10; i /= 10 {
+		n++
+	}
+	return n
+}
+
+func parseMap(p *parser, key, keytail string, values []string, target reflect.Value) {
 	t := target.Type()
 	mapkey, maptail := keyed(t, key, keytail)
 
 	// BUG(carl): We don't support any map keys except strings, although
 	// there's no reason we shouldn't be able to throw the value through our
 	// unparsing stack.
-	var mk reflect.Value
-	if t.Key().Kind() == reflect.String {
-		mk = reflect.ValueOf(mapkey).Convert(t.Key())
-	} else {
+	if t.Key().Kind() != reflect.String {
 		pebkac("key for map %v isn't a string (it's a %v).", t, t.Key())
 	}
 
 	if target.IsNil() {
+		p.nest(kpath(key, keytail))
 		target.Set(reflect.MakeMap(t))
 	}
+	parseMapEntry(p, key, mapkey, maptail, values, target)
+}
 
-	val := target.MapIndex(mk)
-	if !val.IsValid() || !val.CanSet() {
-		// It's a teensy bit annoying that the value returned by
-		// MapIndex isn't Set()table if the key exists.
-		val = reflect.New(t.Elem()).Elem()
+// Parse the values of the given key into the entry with the given key of the
+// map target, which must not be nil. maptail is what remains of the key.
+func parseMapEntry(p *parser, key, mapkey, maptail string, values []string, target reflect.Value) {
+	t := target.Type()
+	mk := reflect.ValueOf(mapkey).Convert(t.Key())
+
+	// It's a teensy bit annoying that the value returned by MapIndex isn't
+	// Set()table if the key exists, so we always parse into a new value.
+	// Entries may be given a piece at a time, as in "foo[bar][x]=1&
+	// foo[bar][y]=2", so we start from a copy of the existing entry.
+	if (p.maxAlloc > 0 || p.maxNested > 0) && !target.MapIndex(mk).IsValid() {
+		kp := kpath(key, maptail)
+		p.nest(kp)
+		p.allocValues(kp, t.Key(), 1)
+		p.allocValues(kp, t.Elem(), 1)
+		p.alloc(kp, len(mapkey))
+	}
+	val := reflect.New(t.Elem()).Elem()
+	if old := target.MapIndex(mk); old.IsValid() {
+		val.Set(old)
+	}
+	if maptail == "" {
+		values = p.mapEntryValues(key, t, values)
 	}
-	parse(key, maptail, values, val)
+	parse(p, key, maptail, values, val)
 	target.SetMapIndex(mk, val)
 }
 
-func parseStruct(key, keytail string, values []string, target reflect.Value) {
+func parseStruct(p *parser, key, keytail string, values []string, target reflect.Value) {
 	t := target.Type()
 	sk, skt := keyed(t, key, keytail)
-	cache := cacheStruct(t)
+	cache := p.cached(t)
+	if p.begun != nil {
+		p.beforeParam(kpath(key, keytail), target)
+	}
 
-	parseStructField(cache, key, sk, skt, values, target)
+	parseStructField(p, cache, key, sk, skt, values, target)
 }
 
-func parsePtr(key, keytail string, values []string, target reflect.Value) {
+func parsePtr(p *parser, key, keytail string, values []string, target reflect.Value) {
 	t := target.Type()
+	if p.emptyNil && keytail == "" && len(values) == 1 && p.blank(values[0]) {
+		return
+	}
+	if p.null(keytail, values) {
+		target.Set(reflect.Zero(t))
+		return
+	}
 
 	if target.IsNil() {
+		p.nest(kpath(key, keytail))
+		p.allocValues(kpath(key, keytail), t.Elem(), 1)
 		target.Set(reflect.New(t.Elem()))
 	}
-	parse(key, keytail, values, target.Elem())
+	parse(p, key, keytail, values, target.Elem())
+}
+
+// The largest index that may be given for a slice element. This prevents a
+// single key from allocating an enormous slice.
+const maxSliceIndex = 10000
+
+// The most slice elements that may be allocated to accommodate indices in a
+// single parse. This prevents many keys from each allocating a large slice.
+const maxSliceGrowth = 100000
+
+// If keytail begins with an index, as in "[12][foo]", return the index and the
+// rest of keytail.
+func sliceIndex(keytail string) (int, string, bool) {
+	if len(keytail) < 3 || keytail[0] != '[' {
+		return 0, "", false
+	}
+	end := strings.IndexByte(keytail, ']')
+	if end < 2 {
+		return 0, "", false
+	}
+	for _, c := range keytail[1:end] {
+		if c < '0' || c > '9' {
+			return 0, "", false
+		}
+	}
+	i, err := strconv.Atoi(keytail[1:end])
+	if err != nil {
+		// The only possible error is that the index is too large to
+		// represent, which it also is for our purposes.
+		i = maxSliceIndex + 1
+	}
+	return i, keytail[end+1:], true
 }
@@ -0,0 +1,36 @@
+package param
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// The "pattern" tag option requires the values of string fields (or slices of
+// them, and so on) to match the given regular expression, in the syntax of the
+// regexp package, as in `param:"slug,pattern=^[a-z0-9-]+$"`. Values that don't
+// are rejected with a TypeError. As with regexp.MatchString, the pattern may
+// match any part of the value unless it is anchored. Since tag options are
+// separated by commas, patterns can't contain them.
+//
+// Patterns are compiled once, when the struct is first seen, so invalid ones
+// are reported by Precompile.
+func wrapPattern(s reflect.Type, sf reflect.StructField, pattern string, h parseFunc) parseFunc {
+	if bt := baseType(sf.Type); isLeaf(bt) || bt.Kind() != reflect.String {
+		pebkac("struct %v field %q has the pattern option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		pebkac("struct %v field %q has invalid pattern %q: %v", s,
+			sf.Name, pattern, err)
+	}
+
+	return transformValues(h, func(v string) (string, error) {
+		if !re.MatchString(v) {
+			return v, fmt.Errorf("value does not match pattern %q",
+				pattern)
+		}
+		return v, nil
+	})
+}
@@ -0,0 +1,60 @@
+package param
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+type Article struct {
+	Slug string   `param:"slug,pattern=^[a-z0-9-]+$,trim"`
+	Tags []string `param:"tags,pattern=^#"`
+}
+
+func TestPattern(t *testing.T) {
+	t.Parallel()
+
+	a := Article{}
+	err := Parse(url.Values{
+		"slug":   {" hello-world "},
+		"tags[]": {"#go", "#web"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.Slug", "hello-world", a.Slug)
+	assertEqual(t, "a.Tags", []string{"#go", "#web"}, a.Tags)
+
+	for _, params := range []url.Values{
+		{"slug": {"Hello World"}},
+		{"slug": {""}},
+		{"tags[]": {"#go", "web"}},
+	} {
+		err := Parse(params, &Article{})
+		var te TypeError
+		if !errors.As(err, &te) {
+			t.Errorf("Expected TypeError for %v, got %v", params, err)
+		}
+	}
+}
+
+func TestPatternSchema(t *testing.T) {
+	t.Parallel()
+
+	doc, err := JSONSchema(&Article{})
+	if err != nil {
+		t.Fatal("JSONSchema error: ", err)
+	}
+	var s struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(doc, &s); err != nil {
+		t.Fatal("Unmarshal error: ", err)
+	}
+	assertEqual(t, "slug", `{"type":"string","pattern":"^[a-z0-9-]+$"}`,
+		string(s.Properties["slug"]))
+	assertEqual(t, "tags",
+		`{"type":"array","items":{"type":"string","pattern":"^#"}}`,
+		string(s.Properties["tags"]))
+}
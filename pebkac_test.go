@@ -2,6 +2,7 @@ package param
 
 import (
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -56,3 +57,546 @@ func TestBadTypes(t *testing.T) {
 
 	pebkacTesting = false
 }
+
+type BadDefault struct {
+	Int int `param:"int,default=llama"`
+}
+
+type BadDefault2 struct {
+	Int int `param:"int,required,default=4"`
+}
+
+func TestBadDefaults(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadDefault{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadDefault2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadHostPort struct {
+	Port int `param:"port,hostport"`
+}
+
+type BadHostPort2 struct {
+	Addr string `param:"addr,hostport=http"`
+}
+
+func TestBadHostPort(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadHostPort{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadHostPort2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadKeyField struct {
+	Items []int `param:"items,keyfield=id"`
+}
+
+type BadKeyField2 struct {
+	Items []Item `param:"items,keyfield=llama"`
+}
+
+func TestBadKeyField(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadKeyField{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadKeyField2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadEncode(t *testing.T) {
+	pebkacTesting = true
+
+	_, err := Encode(4)
+	assertPebkac(t, err)
+
+	_, err = Encode(struct{ Subs []Sub }{[]Sub{{}}})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadDiscriminator struct {
+	Kind  string `param:"kind"`
+	Shape Sub    `param:"shape,discriminator=kind"`
+}
+
+type BadDiscriminator2 struct {
+	Shape Shape `param:"shape,discriminator=llama"`
+}
+
+func TestBadDiscriminator(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadDiscriminator{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadDiscriminator2{})
+	assertPebkac(t, err)
+
+	err = func() (err error) {
+		defer recoverError(&err)
+		NewDecoder().RegisterInterface(reflect.TypeOf(Circle{}),
+			"circle", reflect.TypeOf(Circle{}))
+		return nil
+	}()
+	assertPebkac(t, err)
+
+	err = func() (err error) {
+		defer recoverError(&err)
+		NewDecoder().RegisterInterface(shapeType, "sub",
+			reflect.TypeOf(Sub{}))
+		return nil
+	}()
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type Order struct {
+	Items []*OrderItem
+}
+
+type OrderItem struct {
+	Meta map[string]OrderMeta
+}
+
+type OrderMeta struct {
+	Chan chan int
+}
+
+func TestBadNestedType(t *testing.T) {
+	pebkacTesting = true
+
+	// The error is reported even though no parameter reaches the field
+	err := Parse(url.Values{}, &Order{})
+	assertPebkac(t, err)
+	if !strings.Contains(err.Error(), "Order.Items[].Meta[].Chan ") {
+		t.Errorf("Expected error to contain path to field, got %v", err)
+	}
+
+	pebkacTesting = false
+}
+
+type BadRaw struct {
+	Int int `param:"int,raw"`
+}
+
+func TestBadRaw(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadRaw{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadBase64 struct {
+	Data string `param:"data,base64"`
+}
+
+type BadBase642 struct {
+	Data []byte `param:"data,base64=llama"`
+}
+
+func TestBadBase64(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadBase64{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadBase642{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadDecodeHook(t *testing.T) {
+	pebkacTesting = true
+
+	err := func() (err error) {
+		defer recoverError(&err)
+		WithDecodeHook(func(string) string { return "" })
+		return nil
+	}()
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadBase struct {
+	Float float64 `param:"float,base=16"`
+}
+
+type BadBase2 struct {
+	Int int `param:"int,base=1"`
+}
+
+func TestBadBase(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadBase{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadBase2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadDecimal struct {
+	Int int `param:"int,decimal=comma"`
+}
+
+type BadDecimal2 struct {
+	Float float64 `param:"float,decimal=semicolon"`
+}
+
+func TestBadDecimal(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadDecimal{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadDecimal2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadByteSize struct {
+	Size float64 `param:"size,bytesize"`
+}
+
+func TestBadByteSize(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadByteSize{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadPercent struct {
+	Ratio int `param:"ratio,percent"`
+}
+
+func TestBadPercent(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadPercent{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadColumns(t *testing.T) {
+	pebkacTesting = true
+
+	_, err := FieldSet{}.Columns(UserRow{}, "db")
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadValidate(t *testing.T) {
+	pebkacTesting = true
+
+	err := Validate(url.Values{}, 4)
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadAppend struct {
+	Tag string `param:"tag,append"`
+}
+
+func TestBadAppend(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadAppend{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadAlias struct {
+	Limit   int `param:"limit,alias=per_page"`
+	PerPage int `param:"per_page"`
+}
+
+type BadAlias2 struct {
+	Limit int `param:"limit,alias=per_page|"`
+}
+
+func TestBadAlias(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadAlias{})
+	assertPebkac(t, err)
+
+	err = Parse(url.Values{}, &BadAlias2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadFlatten struct {
+	City string `param:"city_,flatten"`
+}
+
+func TestBadFlatten(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadFlatten{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadMapTarget(t *testing.T) {
+	pebkacTesting = true
+
+	m := map[int]string{}
+	err := Parse(url.Values{"1": {"a"}}, &m)
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadDiff(t *testing.T) {
+	pebkacTesting = true
+
+	_, err := Diff(Everything{}, Sub{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadPrecompile(t *testing.T) {
+	pebkacTesting = true
+
+	err := Precompile(&Warm{}, Warm{})
+	assertPebkac(t, err)
+	err = Precompile(&BadDefault{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadGroups struct {
+	Name string `param:"name,groups="`
+}
+
+type BadGroups2 struct {
+	Name string `param:"name,groups=create||update"`
+}
+
+func TestBadGroups(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadGroups{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadGroups2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadRequiredIf struct {
+	State   string `param:"state,required_if=country"`
+	Country string `param:"country"`
+}
+
+type BadRequiredWith struct {
+	CVV string `param:"cvv,required_with=card"`
+}
+
+func TestBadConditions(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadRequiredIf{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadRequiredWith{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadBounds struct {
+	Name string `param:"name,min=1"`
+}
+
+type BadBounds2 struct {
+	Limit int `param:"limit,min=10,max=1"`
+}
+
+type BadBounds3 struct {
+	Limit uint `param:"limit,min=-1"`
+}
+
+type BadBounds4 struct {
+	Limit int `param:"limit,clamp"`
+}
+
+type BadBounds5 struct {
+	Limit uint8 `param:"limit,max=1000"`
+}
+
+type BadBounds6 struct {
+	Offset int8 `param:"offset,min=-129"`
+}
+
+func TestBadBounds(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadBounds{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadBounds2{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadBounds3{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadBounds4{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadBounds5{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadBounds6{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadOneOf struct {
+	Ratio float64 `param:"ratio,oneof=0.5 1"`
+}
+
+type BadOneOf2 struct {
+	Level int8 `param:"level,oneof=1 1000"`
+}
+
+type BadOneOf3 struct {
+	Sort string `param:"sort,oneof="`
+}
+
+func TestBadOneOf(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadOneOf{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadOneOf2{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadOneOf3{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadPattern struct {
+	Slug string `param:"slug,pattern=[a-z"`
+}
+
+type BadPattern2 struct {
+	Count int `param:"count,pattern=^[0-9]+$"`
+}
+
+func TestBadPattern(t *testing.T) {
+	pebkacTesting = true
+
+	err := Precompile(&BadPattern{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadPattern2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadMaxLen struct {
+	Count int `param:"count,maxlen=3"`
+}
+
+type BadMaxItems struct {
+	Name string `param:"name,maxitems=3"`
+}
+
+type BadMaxItems2 struct {
+	Tags []string `param:"tags,maxitems=-1"`
+}
+
+func TestBadMaxLen(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadMaxLen{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadMaxItems{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadMaxItems2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+type BadCase struct {
+	Count int `param:"count,lower"`
+}
+
+type BadCase2 struct {
+	Name string `param:"name,lower,upper"`
+}
+
+func TestBadCase(t *testing.T) {
+	pebkacTesting = true
+
+	err := Parse(url.Values{}, &BadCase{})
+	assertPebkac(t, err)
+	err = Parse(url.Values{}, &BadCase2{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBadOnlyExcept(t *testing.T) {
+	pebkacTesting = true
+
+	err := NewDecoder(Except("Rol")).Parse(url.Values{}, &Membership{})
+	assertPebkac(t, err)
+	err = NewDecoder(Only("Name", "Nope")).Parse(url.Values{}, &Membership{})
+	assertPebkac(t, err)
+
+	pebkacTesting = false
+}
+
+func TestBoolValuesFoldConflict(t *testing.T) {
+	pebkacTesting = true
+
+	for _, opts := range [][]Option{
+		{WithBoolValues([]string{"Yes"}, []string{"YES"}),
+			WithCaseInsensitiveBools()},
+		{WithCaseInsensitiveBools(),
+			WithBoolValues([]string{"Y"}, []string{"y"})},
+		{ProfilePHP, WithBoolValues([]string{"OFF"}, nil)},
+	} {
+		err := func() (err error) {
+			defer recoverError(&err)
+			NewDecoder(opts...)
+			return nil
+		}()
+		assertPebkac(t, err)
+	}
+
+	pebkacTesting = false
+}
@@ -0,0 +1,55 @@
+package param
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var errPercent = errors.New("invalid percentage")
+
+// The "percent" tag option parses floating point fields (or slices of them,
+// and so on) as percentages, with or without a trailing "%": both "75%" and
+// "75" are parsed as 0.75.
+func wrapPercent(s reflect.Type, sf reflect.StructField, h parseFunc) parseFunc {
+	if k := baseType(sf.Type).Kind(); k != reflect.Float32 && k != reflect.Float64 {
+		pebkac("struct %v field %q has the percent option, but is of "+
+			"type %v.", s, sf.Name, sf.Type)
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		transformValues(h, p.percent)(p, key, keytail, values, target)
+	}
+}
+
+// Translate a percentage into the fraction it represents, written in a way that
+// parseFloat will accept given the Decoder's options.
+func (p *parser) percent(v string) (string, error) {
+	if v == "" {
+		// Let the usual handler decide what to make of it.
+		return v, nil
+	}
+	num := p.ungroup(strings.TrimSpace(strings.TrimSuffix(v, "%")))
+	if p.decimalComma {
+		var err error
+		if num, err = decimalComma(num); err != nil {
+			return v, err
+		}
+	}
+
+	// We divide exactly, so that "7%" is the closest float to 0.07, rather
+	// than 7.0/100.
+	r, ok := new(big.Rat).SetString(num)
+	if !ok || strings.ContainsRune(num, '/') {
+		return v, errPercent
+	}
+	f, _ := r.Quo(r, big.NewRat(100, 1)).Float64()
+
+	out := strconv.FormatFloat(f, 'g', -1, 64)
+	if p.decimalComma {
+		out = strings.Replace(out, ".", ",", 1)
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Dashboard struct {
+	Ratio      float64   `param:"ratio,percent"`
+	Thresholds []float32 `param:"thresholds,percent"`
+}
+
+func TestPercent(t *testing.T) {
+	t.Parallel()
+
+	for value, expected := range map[string]float64{
+		"75%":    0.75,
+		"75":     0.75,
+		"7%":     0.07,
+		"12.5 %": 0.125,
+		"-100%":  -1,
+		"150%":   1.5,
+	} {
+		var d Dashboard
+		if err := Parse(url.Values{"ratio": {value}}, &d); err != nil {
+			t.Fatalf("Parse error for %q: %v", value, err)
+		}
+		assertEqual(t, value, expected, d.Ratio)
+	}
+
+	var d Dashboard
+	err := Parse(url.Values{"thresholds[]": {"50%", "90%"}}, &d)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "d.Thresholds", []float32{0.5, 0.9}, d.Thresholds)
+
+	err = NewDecoder(WithDecimalComma(), WithGroupSeparators(".")).Parse(
+		url.Values{"ratio": {"1.012,5%"}}, &d)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "d.Ratio", 10.125, d.Ratio)
+
+	for _, value := range []string{"", "%", "lots%", "1/2%", "75%%"} {
+		err := Parse(url.Values{"ratio": {value}}, &Dashboard{})
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %q, got %v", value, err)
+		}
+	}
+}
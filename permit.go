@@ -0,0 +1,256 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Permit restricts the Decoder to parsing into the fields with the given keys,
+// in the manner of the strong parameters of Rails, as a defense against mass
+// assignment: a handler that binds a User from a signup form can permit "name"
+// and "address[city]" without fear of a client also setting "admin". Elements
+// of slices are given with empty brackets, as in "tags[]" or "items[][name]",
+// and permitting a field also permits everything nested within it, such as the
+// entries of a map.
+//
+// Fields that are not permitted are treated as though they did not exist, as
+// with WithFieldFilter (which Permit may be combined with): parameters that
+// address them are unknown keys, which Parse rejects with a KeyError, and which
+// ParseWithReport and ParseWithWarnings ignore and report, and their required
+// and default options are ignored. Calling Permit again permits more fields.
+//
+// Permit must not be called once the Decoder is in use.
+func (d *Decoder) Permit(keys ...string) {
+	// Decoders made by With share their parent's map, so we copy it.
+	permitted := make(map[string]bool, len(d.permitted)+len(keys))
+	for key, ok := range d.permitted {
+		permitted[key] = ok
+	}
+	for _, key := range keys {
+		key = strings.TrimSuffix(key, "[]")
+		permitted[key] = true
+		// The fields the permitted one is nested within have to be
+		// permitted for there to be any way of reaching it.
+		for i := strings.LastIndexByte(key, '['); i > 0; i = strings.LastIndexByte(key, '[') {
+			key = strings.TrimSuffix(key[:i], "[]")
+			if _, ok := permitted[key]; !ok {
+				permitted[key] = false
+			}
+		}
+	}
+	d.permitted = permitted
+}
+
+// Reports whether the field with the given key is permitted: whether it or a
+// field it is nested within was permitted, or it leads to a field that was.
+// Values in d.permitted are true for fields that were permitted, and false for
+// the ones that only lead to them.
+func (d *Decoder) permits(key string) bool {
+	key = permitKey(key)
+	if _, ok := d.permitted[key]; ok {
+		return true
+	}
+	for i := strings.LastIndexByte(key, '['); i > 0; i = strings.LastIndexByte(key, '[') {
+		key = strings.TrimSuffix(key[:i], "[]")
+		if d.permitted[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// Replaces the indices in the given key with empty brackets, as in Permit:
+// "items[0][name]" becomes "items[][name]".
+func permitKey(key string) string {
+	var b strings.Builder
+	for {
+		i, rest, ok := sliceIndexIn(key)
+		if !ok {
+			b.WriteString(key)
+			return b.String()
+		}
+		b.WriteString(key[:i])
+		b.WriteString("[]")
+		key = rest
+	}
+}
+
+// Finds the first index in key, returning the offset of its opening bracket
+// and what follows its closing one.
+func sliceIndexIn(key string) (int, string, bool) {
+	for i := strings.IndexByte(key, '['); i >= 0; {
+		if _, rest, ok := sliceIndex(key[i:]); ok {
+			return i, rest, true
+		}
+		j := strings.IndexByte(key[i+1:], '[')
+		if j < 0 {
+			break
+		}
+		i += j + 1
+	}
+	return 0, "", false
+}
+
+// Only returns an Option that restricts the Decoder to parsing into the
+// top-level fields of the target with the given Go names, and whatever is
+// nested within them, as Permit does. It is intended for use with With, so that
+// the same struct may be bound differently by different handlers: for
+// instance, an endpoint that lets users edit their own profile might use
+// d.With(param.Only("Name", "Email")).Parse(params, &user). Giving Only several
+// times permits the fields given to any of them.
+func Only(fields ...string) Option {
+	return func(d *Decoder) {
+		d.only = addNames(d.only, fields)
+	}
+}
+
+// Except returns an Option that prevents the Decoder from parsing into the
+// top-level fields of the target with the given Go names, as Permit does for
+// fields that aren't permitted. Like Only, it is intended for use with With.
+//
+// Fields promoted from embedded structs may be given to Only and Except by
+// their own Go names, or by the name of the embedded struct, and are included
+// or excluded whichever key they are given by. Names that aren't those of
+// fields of the target can only be mistakes, and Parse complains about them
+// loudly.
+func Except(fields ...string) Option {
+	return func(d *Decoder) {
+		d.except = addNames(d.except, fields)
+	}
+}
+
+// Returns a copy of the given set of names with the given ones added.
+func addNames(set map[string]bool, names []string) map[string]bool {
+	added := make(map[string]bool, len(set)+len(names))
+	for name := range set {
+		added[name] = true
+	}
+	for _, name := range names {
+		added[name] = true
+	}
+	return added
+}
+
+// Reports whether the field with the given key is excluded by Only or Except,
+// which name the fields of the target by the Go names of the fields the key
+// leads through (see goNames). fallback names the field if none of the key
+// resolves to a field, as for setters.
+func (p *parser) excludes(key, fallback string) bool {
+	if p.only == nil && p.except == nil || p.root == nil {
+		return false
+	}
+	names := p.goNames(key)
+	if len(names) == 0 {
+		names = []string{fallback}
+	}
+	included := p.only == nil
+	for _, name := range names {
+		if p.except[name] {
+			return true
+		}
+		included = included || p.only[name]
+	}
+	return !included
+}
+
+// Returns the Go names of the fields of the target that the given key leads
+// through, up to and including the first that isn't an embedded struct, so that
+// a promoted field is named the same way whichever key reaches it: both "role"
+// and "Common[role]" give ["Common", "Role"] if Role is promoted from the
+// embedded struct Common. The names end early at anything that isn't a field.
+func (p *parser) goNames(key string) []string {
+	t := p.root
+	sk, rest := key, ""
+	if i := strings.IndexByte(key, '['); i >= 0 {
+		sk, rest = key[:i], key[i:]
+	}
+
+	var names []string
+	for {
+		chain, st, l, ok := p.goChain(t, sk, []reflect.Type{t})
+		names = append(names, chain...)
+		// Only embedded structs lead any further.
+		if !ok || !l.promoted || rest == "" || rest[0] != '[' {
+			return names
+		}
+		i := closingBracket(rest)
+		if i < 0 {
+			return names
+		}
+		t = structType(st.Field(l.offset).Type)
+		sk, rest = unescapeKey(rest[1:i]), rest[i+1:]
+	}
+}
+
+// Finds the field of struct t named sk, whether directly, promoted from an
+// embedded struct, or flattened, as parseStructField would. Returns the Go names
+// of the fields that lead to it, and the struct it belongs to and its cache
+// line. seen holds the types we have already looked in.
+func (p *parser) goChain(t reflect.Type, sk string, seen []reflect.Type) ([]string, reflect.Type, cacheLine, bool) {
+	cache := p.cached(t)
+	if _, l, ok := cache.lookup(sk); ok {
+		return []string{t.Field(l.offset).Name}, t, l, true
+	}
+	for _, l := range embeds(cache) {
+		sf := t.Field(l.offset)
+		et := structType(sf.Type)
+		found := false
+		for _, st := range seen {
+			found = found || st == et
+		}
+		if found {
+			continue
+		}
+		if chain, st, el, ok := p.goChain(et, sk, append(seen, et)); ok {
+			return append([]string{sf.Name}, chain...), st, el, true
+		}
+	}
+	// Flattened fields belong to the flattened struct, which is what Only
+	// and Except name.
+	names, lines := flattened(cache)
+	for i, l := range lines {
+		rest, ok := strings.CutPrefix(sk, names[i])
+		sf := t.Field(l.offset)
+		if ok && rest != "" && p.hasFlattened(structType(sf.Type), rest) {
+			return []string{sf.Name}, t, l, true
+		}
+	}
+	return nil, nil, cacheLine{}, false
+}
+
+// Complain about any name given to Only or Except that isn't the Go name of a
+// field of the struct type t, or of a field promoted from its embedded structs,
+// or the name of one of their setters.
+func (p *parser) checkFieldNames(t reflect.Type) {
+	for _, set := range []map[string]bool{p.only, p.except} {
+		for name := range set {
+			if !p.hasGoName(t, name, []reflect.Type{t}) {
+				pebkac("Only or Except was given %q, which is not a "+
+					"field of %v.", name, t)
+			}
+		}
+	}
+}
+
+func (p *parser) hasGoName(t reflect.Type, name string, seen []reflect.Type) bool {
+	if _, ok := setterMethods(t)[name]; ok {
+		return true
+	}
+	cache := p.cached(t)
+	for _, l := range cache {
+		if t.Field(l.offset).Name == name {
+			return true
+		}
+	}
+	for _, l := range embeds(cache) {
+		et := structType(t.Field(l.offset).Type)
+		found := false
+		for _, st := range seen {
+			found = found || st == et
+		}
+		if !found && p.hasGoName(et, name, append(seen, et)) {
+			return true
+		}
+	}
+	return false
+}
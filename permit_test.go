@@ -0,0 +1,233 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Registration struct {
+	Name    string            `param:"name,required"`
+	Admin   bool              `param:"admin"`
+	Role    string            `param:"role,default=user"`
+	Tags    []string          `param:"tags"`
+	Meta    map[string]string `param:"meta"`
+	Address struct {
+		City string `param:"city"`
+		Zip  string `param:"zip"`
+	} `param:"address"`
+	Items []struct {
+		Name  string `param:"name"`
+		Price int    `param:"price"`
+	} `param:"items"`
+}
+
+func TestPermit(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	d.Permit("name", "address[city]", "tags[]")
+	d.Permit("meta", "items[][name]")
+
+	s := Registration{}
+	err := d.Parse(url.Values{
+		"name":           {"Alice"},
+		"address[city]":  {"Paris"},
+		"tags[]":         {"a", "b"},
+		"meta[x]":        {"y"},
+		"items[0][name]": {"hat"},
+	}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.Name", "Alice", s.Name)
+	assertEqual(t, "s.Address.City", "Paris", s.Address.City)
+	assertEqual(t, "s.Tags", []string{"a", "b"}, s.Tags)
+	assertEqual(t, "s.Meta", map[string]string{"x": "y"}, s.Meta)
+	assertEqual(t, "s.Items[0].Name", "hat", s.Items[0].Name)
+	// Defaults of fields that aren't permitted are ignored.
+	assertEqual(t, "s.Role", "", s.Role)
+
+	for _, key := range []string{"admin", "address[zip]", "items[0][price]"} {
+		err := d.Parse(url.Values{"name": {"Alice"}, key: {"1"}}, &s)
+		if _, ok := err.(KeyError); !ok {
+			t.Errorf("Expected KeyError for %q, got %v", key, err)
+		}
+	}
+
+	unknown, err := d.ParseWithReport(url.Values{
+		"name":  {"Mallory"},
+		"admin": {"true"},
+	}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "unknown", []string{"admin"}, unknown)
+	assertEqual(t, "s.Admin", false, s.Admin)
+}
+
+func TestPermitKey(t *testing.T) {
+	t.Parallel()
+
+	for in, out := range map[string]string{
+		"name":           "name",
+		"items[0][name]": "items[][name]",
+		"a[1][b][22][c]": "a[][b][][c]",
+		"m[x][0]":        "m[x][]",
+		"m[1x]":          "m[1x]",
+	} {
+		assertEqual(t, "permitKey("+in+")", out, permitKey(in))
+	}
+}
+
+type Profile struct {
+	Name    string `param:"name"`
+	Email   string `param:"email"`
+	Role    string `param:"role,required"`
+	Address struct {
+		City string `param:"city"`
+	} `param:"address"`
+}
+
+func TestOnlyExcept(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	params := url.Values{"name": {"Bob"}, "email": {"b@example.com"}}
+
+	p := Profile{}
+	err := d.With(Only("Name", "Email")).Parse(params, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Name", "Bob", p.Name)
+
+	params["role"] = []string{"admin"}
+	err = d.With(Except("Role")).Parse(params, &p)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+	err = d.With(Only("Name", "Email"), Only("Role")).Parse(params, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Role", "admin", p.Role)
+
+	err = d.With(Only("Address")).Parse(url.Values{
+		"address[city]": {"Oslo"},
+	}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Address.City", "Oslo", p.Address.City)
+
+	// The original Decoder is untouched.
+	err = d.Parse(url.Values{"name": {"Eve"}}, &p)
+	assertEqual(t, "err", RequiredError{Key: "role", Type: stringType}, err)
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithBoolValues([]string{"yes"}, nil))
+	d.SetNameMapper(SnakeCase)
+	c := d.With(WithBoolValues([]string{"si"}, nil), WithTagNames("form"))
+
+	var e struct {
+		OK bool `json:"ok" form:"okay"`
+	}
+	if err := c.Parse(url.Values{"okay": {"si"}}, &e); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.OK", true, e.OK)
+	if err := d.Parse(url.Values{"ok": {"yes"}}, &e); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	err := d.Parse(url.Values{"ok": {"si"}}, &e)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+	err = d.Parse(url.Values{"okay": {"yes"}}, &e)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
+
+type Promotable struct {
+	Name string `param:"name"`
+
+	admin bool
+}
+
+func (p *Promotable) SetAdmin(v string) error {
+	p.admin = true
+	return nil
+}
+
+func TestPermitSetters(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	d.Permit("name")
+
+	p := Promotable{}
+	err := d.Parse(url.Values{"name": {"Eve"}, "Admin": {"true"}}, &p)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+	assertEqual(t, "p.admin", false, p.admin)
+
+	err = NewDecoder(Except("Admin")).Parse(url.Values{"Admin": {"true"}}, &p)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+	assertEqual(t, "p.admin", false, p.admin)
+
+	d.Permit("Admin")
+	if err := d.Parse(url.Values{"Admin": {"true"}}, &p); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.admin", true, p.admin)
+}
+
+type Audited struct {
+	Role  string `param:"role"`
+	Owner string `param:"owner"`
+}
+
+type Membership struct {
+	Audited
+	Name string `param:"name"`
+}
+
+func TestOnlyExceptPromoted(t *testing.T) {
+	t.Parallel()
+
+	except := NewDecoder(Except("Role"))
+	for _, key := range []string{"role", "Audited[role]"} {
+		m := Membership{}
+		err := except.Parse(url.Values{key: {"admin"}}, &m)
+		if _, ok := err.(KeyError); !ok {
+			t.Errorf("Expected KeyError for %q, got %v", key, err)
+		}
+		assertEqual(t, "m.Role", "", m.Role)
+	}
+
+	m := Membership{}
+	err := except.Parse(url.Values{"Audited[owner]": {"bob"}, "name": {"x"}}, &m)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "m.Owner", "bob", m.Owner)
+
+	// The embedded struct's name covers its promoted fields.
+	only := NewDecoder(Only("Audited"))
+	err = only.Parse(url.Values{"role": {"a"}, "Audited[owner]": {"b"}}, &m)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "m.Role", "a", m.Role)
+	err = only.Parse(url.Values{"name": {"x"}}, &m)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package param
+
+import (
+	"context"
+	"sync"
+)
+
+// Parsers, and the scratch buffers they hold on to, are reused from one call to
+// Parse to the next, so that their allocations amortize across requests.
+var parserPool = sync.Pool{
+	New: func() interface{} {
+		return new(parser)
+	},
+}
+
+func (d *Decoder) newParser(ctx context.Context) *parser {
+	p := parserPool.Get().(*parser)
+	p.Decoder, p.ctx = d, ctx
+	return p
+}
+
+// Returns p to the pool. Nothing may refer to p, or to its scratch buffers,
+// once it's released. Its results, such as its warnings, are left alone, and so
+// may still be handed out.
+func (p *parser) release() {
+	// Don't hang on to the keys of the last request.
+	keys := p.keys
+	clear(keys)
+	*p = parser{keys: keys[:0]}
+	parserPool.Put(p)
+}
+
+// Returns the keys of p.params in sorted order, in a buffer that is reused
+// across calls to Parse.
+func (p *parser) sortedKeys() []string {
+	p.keys = appendSortedKeys(p.keys[:0], p.params)
+	return p.keys
+}
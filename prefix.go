@@ -0,0 +1,45 @@
+package param
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// ParsePrefix parses the parameters nested under the given prefix into the
+// given pointer to a struct object, ignoring all others. Parameters of the form
+// "prefix[name]=a" and "prefix[name][sub]=b" are parsed exactly as Parse would
+// parse "name=a" and "name[sub]=b", so that, for instance, filters and
+// pagination given in the same query string can be parsed into separate
+// structs. Keys in the errors ParsePrefix returns are likewise given without
+// the prefix.
+func ParsePrefix(params url.Values, prefix string, target interface{}) error {
+	return defaultDecoder.parsePrefix("param.ParsePrefix", params, prefix,
+		target)
+}
+
+// ParsePrefix is like the package-level ParsePrefix, but parses as the Decoder
+// would.
+func (d *Decoder) ParsePrefix(params url.Values, prefix string, target interface{}) error {
+	return d.parsePrefix("param.Decoder.ParsePrefix", params, prefix, target)
+}
+
+func (d *Decoder) parsePrefix(fn string, params url.Values, prefix string, target interface{}) (err error) {
+	defer recoverError(&err)
+
+	if d.dottedKeys {
+		params = undotKeys(params)
+	}
+	t := reflect.TypeOf(target)
+	sub := make(url.Values)
+	for _, key := range sortedKeys(params) {
+		if !strings.HasPrefix(key, prefix+"[") {
+			continue
+		}
+		name, tail := keyed(t, key, key[len(prefix):])
+		sub[name+tail] = params[key]
+	}
+
+	return d.parseContext(context.Background(), fn, sub, target)
+}
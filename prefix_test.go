@@ -0,0 +1,62 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParsePrefix(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{
+		"filter[Int]":       {"4"},
+		"filter[Struct][A]": {"5"},
+		"filter":            {"x"},
+		"filters[Int]":      {"6"},
+		"page[Int]":         {"2"},
+		"page[Uint]":        {"3"},
+		"Int":               {"7"},
+	}
+
+	f := Everything{}
+	if err := ParsePrefix(params, "filter", &f); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "f.Int", 4, f.Int)
+	assertEqual(t, "f.Struct.A", 5, f.Struct.A)
+
+	p := Everything{}
+	if err := ParsePrefix(params, "page", &p); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Int", 2, p.Int)
+	assertEqual(t, "p.Uint", uint(3), p.Uint)
+
+	err := ParsePrefix(url.Values{"filter[Llama]": {"1"}}, "filter", &f)
+	if kerr, ok := err.(KeyError); !ok || kerr.Key != "Llama" {
+		t.Errorf("Expected KeyError for Llama, got %v", err)
+	}
+	err = ParsePrefix(url.Values{"filter[Int": {"1"}}, "filter", &f)
+	if _, ok := err.(SyntaxError); !ok {
+		t.Errorf("Expected SyntaxError, got %v", err)
+	}
+
+	// Of several malformed keys, the first in sorted order is reported.
+	for i := 0; i < 10; i++ {
+		err = ParsePrefix(url.Values{
+			"filter[C": {"1"}, "filter[A": {"1"}, "filter[B": {"1"},
+		}, "filter", &f)
+		serr, ok := err.(SyntaxError)
+		if !ok || serr.FullKey != "filter[A" {
+			t.Errorf("Expected SyntaxError for filter[A, got %v", err)
+		}
+	}
+
+	d := NewDecoder(WithDottedKeys())
+	p = Everything{}
+	err = d.ParsePrefix(url.Values{"page.Int": {"8"}}, "page", &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p.Int", 8, p.Int)
+}
@@ -0,0 +1,100 @@
+package param
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ParseQuery parses the given raw query string, such as "a=1&b[]=2&b[]=3",
+// into the given pointer to a struct object. Unlike parsing the result of
+// url.ParseQuery, keys are processed in the order in which they first appear
+// in the query string, so that which of several errors is reported, for
+// instance, depends only on the query string.
+func ParseQuery(query string, target interface{}) error {
+	return defaultDecoder.parseQuery("param.ParseQuery", query, target)
+}
+
+// ParseQuery is like the package-level ParseQuery, but parses as the Decoder
+// would.
+func (d *Decoder) ParseQuery(query string, target interface{}) error {
+	return d.parseQuery("param.Decoder.ParseQuery", query, target)
+}
+
+func (d *Decoder) parseQuery(fn, query string, target interface{}) error {
+	params, order, err := parseQueryOrdered(query, d.semicolons)
+	if err != nil {
+		return err
+	}
+	p := d.newParser(context.Background())
+	defer p.release()
+	p.order = order
+	return d.run(p, fn, params, target)
+}
+
+// WithSemicolons returns an Option that makes ParseQuery and ParseBody accept
+// semicolons as separators between pairs, as in "a=1;b=2", as well as
+// ampersands. The standard library stopped doing so in Go 1.17, since proxies
+// that disagree about separators can be made to see different parameters, but
+// some legacy clients still send them. By default, semicolons are an error.
+func WithSemicolons() Option {
+	return func(d *Decoder) {
+		d.semicolons = true
+	}
+}
+
+// Like url.ParseQuery, but also returns the distinct keys of the query in the
+// order in which they first appear. Unlike url.ParseQuery, it stops at the
+// first error. If semicolons is set, pairs may also be separated by
+// semicolons.
+func parseQueryOrdered(query string, semicolons bool) (url.Values, []string, error) {
+	q := newQueryBuilder()
+	for query != "" {
+		var pair string
+		if i := strings.IndexAny(query, "&;"); semicolons && i >= 0 {
+			pair, query = query[:i], query[i+1:]
+		} else {
+			pair, query, _ = strings.Cut(query, "&")
+		}
+		if err := q.add(pair); err != nil {
+			return nil, nil, err
+		}
+	}
+	return q.params, q.order, nil
+}
+
+// A queryBuilder accumulates the "key=value" pairs of a query, remembering the
+// order in which keys first appear.
+type queryBuilder struct {
+	params url.Values
+	order  []string
+}
+
+func newQueryBuilder() *queryBuilder {
+	return &queryBuilder{params: make(url.Values)}
+}
+
+// Add a single pair, which must not contain any separators but semicolons,
+// which are an error.
+func (q *queryBuilder) add(pair string) error {
+	if strings.Contains(pair, ";") {
+		return errSemicolon
+	}
+	if pair == "" {
+		return nil
+	}
+	key, value, _ := strings.Cut(pair, "=")
+	key, err := url.QueryUnescape(key)
+	if err != nil {
+		return err
+	}
+	value, err = url.QueryUnescape(value)
+	if err != nil {
+		return err
+	}
+	if _, ok := q.params[key]; !ok {
+		q.order = append(q.order, key)
+	}
+	q.params[key] = append(q.params[key], value)
+	return nil
+}
@@ -0,0 +1,96 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	e := Everything{}
+	err := ParseQuery("Int=4&Slice[]=1&String=a+b%21&Slice[]=2&&Struct[A]=5",
+		&e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 4, e.Int)
+	assertEqual(t, "e.Slice", []int{1, 2}, e.Slice)
+	assertEqual(t, "e.String", "a b!", e.String)
+	assertEqual(t, "e.Struct.A", 5, e.Struct.A)
+}
+
+func TestParseQueryOrder(t *testing.T) {
+	t.Parallel()
+
+	// Both keys are in error, but the first one to appear is the one
+	// that's reported, every time.
+	for i := 0; i < 20; i++ {
+		e := Everything{}
+		err := ParseQuery("Uint=x&Int=y", &e)
+		terr, ok := err.(TypeError)
+		if !ok {
+			t.Fatalf("Expected TypeError, got %v", err)
+		}
+		assertEqual(t, "terr.Key", "Uint", terr.Key)
+
+		err = NewDecoder(WithDottedKeys()).ParseQuery(
+			"Struct.B=x&Struct[A]=y&Struct.B=z", &e)
+		if serr, ok := err.(SingletonError); !ok {
+			t.Fatalf("Expected SingletonError, got %v", err)
+		} else {
+			assertEqual(t, "serr.Key", "Struct[B]", serr.Key)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	t.Parallel()
+
+	e := Everything{}
+	err := ParseQuery("Int=%zz", &e)
+	if _, ok := err.(url.EscapeError); !ok {
+		t.Errorf("Expected url.EscapeError, got %v", err)
+	}
+
+	err = ParseQuery("Int=1;String=a", &e)
+	if err != errSemicolon {
+		t.Errorf("Expected errSemicolon, got %v", err)
+	}
+}
+
+func TestSemicolons(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithSemicolons())
+	e := Everything{}
+	err := d.ParseQuery("Int=4;String=a&Slice[]=1;Slice[]=2", &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 4, e.Int)
+	assertEqual(t, "e.String", "a", e.String)
+	assertEqual(t, "e.Slice", []int{1, 2}, e.Slice)
+
+	e = Everything{}
+	err = d.ParseBody([]byte("Int=5;Uint=6"), &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 5, e.Int)
+	assertEqual(t, "e.Uint", uint(6), e.Uint)
+
+	e = Everything{}
+	err = NewDecoder(WithSemicolons(), WithZeroCopy()).ParseBody(
+		[]byte("Int=7;String=b"), &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 7, e.Int)
+	assertEqual(t, "e.String", "b", e.String)
+
+	err = NewDecoder().ParseBody([]byte("Int=5;Uint=6"), &e)
+	if err != errSemicolon {
+		t.Errorf("Expected errSemicolon, got %v", err)
+	}
+}
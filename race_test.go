@@ -0,0 +1,7 @@
+//go:build race
+
+package param
+
+// The race detector makes sync.Pool drop items at random, so allocation counts
+// can't be relied on.
+const raceEnabled = true
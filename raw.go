@@ -0,0 +1,88 @@
+package param
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+var valuesType = reflect.TypeOf(url.Values(nil))
+
+var errInvalidJSON = errors.New("invalid JSON")
+
+// json.RawMessage values are copied verbatim from their parameter, which must
+// be valid JSON, so that they can be forwarded or unmarshaled later.
+func parseRawMessage(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+	if !json.Valid([]byte(value)) {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  errInvalidJSON,
+		})
+	}
+	target.SetBytes([]byte(value))
+}
+
+// The "raw" tag option, as in `param:"meta,raw"`, captures a field's value
+// verbatim, without interpreting it. string and []byte fields (including
+// json.RawMessage, which is then not required to be valid JSON) are set to the
+// field's value. url.Values fields capture the entire subtree of keys beneath
+// the field, with keys relative to the field, so that it may be parsed later:
+// "meta[a]=1&meta[b][c]=2" sets the field to {"a": {"1"}, "b[c]": {"2"}}.
+func rawHandler(s reflect.Type, sf reflect.StructField) parseFunc {
+	switch t := sf.Type; {
+	case t.ConvertibleTo(valuesType) && t.Kind() == reflect.Map:
+		return parseRawValues
+	case t.Kind() == reflect.String:
+		return parseString
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return parseRawBytes
+	default:
+		pebkac("struct %v field %q has the raw option, but is of type "+
+			"%v.", s, sf.Name, t)
+		return nil
+	}
+}
+
+func parseRawBytes(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+	p.alloc(kpath(key, keytail), len(value))
+	target.SetBytes([]byte(value))
+}
+
+func parseRawValues(p *parser, key, keytail string, values []string, target reflect.Value) {
+	head, rest := keyed(target.Type(), key, keytail)
+	if target.IsNil() {
+		target.Set(reflect.MakeMap(target.Type()))
+	}
+	raw := target.Convert(valuesType).Interface().(url.Values)
+	raw[head+rest] = append(raw[head+rest], values...)
+}
+
+// Encode a field tagged with the "raw" option.
+func encodeRaw(params url.Values, key string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		params.Add(key, v.String())
+		return
+	case reflect.Slice:
+		if !v.IsNil() {
+			params.Add(key, string(v.Bytes()))
+		}
+		return
+	}
+
+	raw := v.Convert(valuesType).Interface().(url.Values)
+	for k, values := range raw {
+		head, rest := k, ""
+		if i := strings.IndexByte(k, '['); i >= 0 {
+			head, rest = k[:i], k[i:]
+		}
+		params[key+"["+escapeKey(head)+"]"+rest] =
+			append([]string(nil), values...)
+	}
+}
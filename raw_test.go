@@ -0,0 +1,98 @@
+package param
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+type Webhook struct {
+	Event   string          `param:"event"`
+	Payload json.RawMessage `param:"payload"`
+	Blob    json.RawMessage `param:"blob,raw"`
+	Bytes   []byte          `param:"bytes,raw"`
+	Meta    url.Values      `param:"meta,raw"`
+}
+
+func TestRaw(t *testing.T) {
+	t.Parallel()
+
+	var w Webhook
+	err := Parse(url.Values{
+		"event":      {"push"},
+		"payload":    {`{"ref": "main"}`},
+		"blob":       {"not json"},
+		"bytes":      {"hello"},
+		"meta[a]":    {"1"},
+		"meta[b][c]": {"2", "3"},
+		"meta[d][]":  {"4"},
+	}, &w)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "w", Webhook{
+		Event:   "push",
+		Payload: json.RawMessage(`{"ref": "main"}`),
+		Blob:    json.RawMessage("not json"),
+		Bytes:   []byte("hello"),
+		Meta: url.Values{
+			"a":    {"1"},
+			"b[c]": {"2", "3"},
+			"d[]":  {"4"},
+		},
+	}, w)
+
+	// The captured subtree can be parsed later
+	params := url.Values{
+		"meta[Int]":       {"1"},
+		"meta[Struct][A]": {"2"},
+	}
+	var w2 Webhook
+	if err := Parse(params, &w2); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	var e Everything
+	if err := Parse(w2.Meta, &e); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 1, e.Int)
+	assertEqual(t, "e.Struct.A", 2, e.Struct.A)
+
+	err = Parse(url.Values{"payload": {"{"}}, &Webhook{})
+	assertEqual(t, "err", TypeError{
+		Key:  "payload",
+		Type: rawMessageType,
+		Err:  errInvalidJSON,
+	}, err)
+
+	err = Parse(url.Values{"meta": {"1"}}, &Webhook{})
+	if _, ok := err.(SyntaxError); !ok {
+		t.Errorf("Expected SyntaxError, got %v", err)
+	}
+}
+
+func TestRawEncode(t *testing.T) {
+	t.Parallel()
+
+	w := Webhook{
+		Event:   "push",
+		Payload: json.RawMessage(`[1,2]`),
+		Meta:    url.Values{"a": {"1"}, "b[c]": {"2"}},
+	}
+	params, err := Encode(w)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{
+		"event":      {"push"},
+		"payload":    {"[1,2]"},
+		"meta[a]":    {"1"},
+		"meta[b][c]": {"2"},
+	}, params)
+
+	var w2 Webhook
+	if err := Parse(params, &w2); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "w2", w, w2)
+}
@@ -0,0 +1,123 @@
+package param
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+)
+
+// The size limit ParseReader imposes on Decoders without one of their own. It
+// matches the limit net/http imposes on url-encoded request bodies.
+const defaultMaxBodySize = 10 << 20
+
+// WithMaxBodySize returns an Option that makes ParseReader reject bodies longer
+// than n bytes with a LimitError. By default, bodies may be up to 10MiB long.
+func WithMaxBodySize(n int64) Option {
+	return func(d *Decoder) {
+		d.maxBodySize = n
+	}
+}
+
+var errBodyTooLarge = errors.New("body too large")
+
+// ParseReader parses the application/x-www-form-urlencoded body read from r
+// into the given pointer to a struct object. Rather than reading the whole body
+// into memory before splitting it into parameters, it unescapes each pair as it
+// is read, so that large bodies are not held in memory twice. As with
+// ParseQuery, keys are processed in the order in which they first appear.
+//
+// Bodies longer than 10MiB are rejected with a LimitError, without reading any
+// further. Use a Decoder created with WithMaxBodySize to change the limit.
+func ParseReader(r io.Reader, target interface{}) error {
+	return defaultDecoder.parseReader("param.ParseReader", r, target)
+}
+
+// ParseReader is like the package-level ParseReader, but parses as the Decoder
+// would.
+func (d *Decoder) ParseReader(r io.Reader, target interface{}) error {
+	return d.parseReader("param.Decoder.ParseReader", r, target)
+}
+
+func (d *Decoder) parseReader(fn string, r io.Reader, target interface{}) error {
+	max := d.maxBodySize
+	if max <= 0 {
+		max = defaultMaxBodySize
+	}
+	br := &cappedReader{r: r, left: max}
+
+	s := bufio.NewScanner(br)
+	// No pair can be any longer than the body itself.
+	tokenMax := max
+	if tokenMax >= math.MaxInt32 {
+		tokenMax = math.MaxInt32 - 1
+	}
+	s.Buffer(nil, int(tokenMax)+1)
+	s.Split(splitPairs(d.semicolons))
+
+	q := newQueryBuilder()
+	for s.Scan() {
+		if err := q.add(s.Text()); err != nil {
+			return err
+		}
+		// There's no point reading any further if we're only going
+		// to reject what we've read.
+		if d.maxKeys > 0 && len(q.order) > d.maxKeys {
+			return LimitError{Limit: "keys", Max: d.maxKeys}
+		}
+	}
+	if err := s.Err(); err == errBodyTooLarge {
+		return LimitError{Limit: "body size", Max: int(max)}
+	} else if err != nil {
+		return err
+	}
+
+	p := d.newParser(context.Background())
+	defer p.release()
+	p.order = q.order
+	return d.run(p, fn, q.params, target)
+}
+
+// A cappedReader reads from r, failing with errBodyTooLarge once more than left
+// bytes have been read.
+type cappedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (c *cappedReader) Read(b []byte) (int, error) {
+	if c.left < 0 {
+		return 0, errBodyTooLarge
+	}
+	// Read at most one byte more than we allow, which is enough to
+	// tell that the body is too large.
+	if int64(len(b)) > c.left+1 {
+		b = b[:c.left+1]
+	}
+	n, err := c.r.Read(b)
+	c.left -= int64(n)
+	if c.left < 0 {
+		return n, errBodyTooLarge
+	}
+	return n, err
+}
+
+// Returns a bufio.SplitFunc that splits a query into its pairs, which are
+// separated by ampersands or, if semicolons is set, semicolons.
+func splitPairs(semicolons bool) bufio.SplitFunc {
+	seps := "&"
+	if semicolons {
+		seps = "&;"
+	}
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexAny(data, seps); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
@@ -0,0 +1,76 @@
+package param
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseReader(t *testing.T) {
+	t.Parallel()
+
+	// One byte at a time, so that every pair straddles several reads.
+	body := "Int=4&Slice[]=1&String=a+b%21&Slice[]=2&&Struct[A]=5"
+	e := Everything{}
+	err := ParseReader(iotest.OneByteReader(strings.NewReader(body)), &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 4, e.Int)
+	assertEqual(t, "e.Slice", []int{1, 2}, e.Slice)
+	assertEqual(t, "e.String", "a b!", e.String)
+	assertEqual(t, "e.Struct.A", 5, e.Struct.A)
+
+	e = Everything{}
+	err = NewDecoder(WithSemicolons()).ParseReader(
+		strings.NewReader("Int=1;Uint=2"), &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 1, e.Int)
+	assertEqual(t, "e.Uint", uint(2), e.Uint)
+
+	err = ParseReader(strings.NewReader("Int=1;Uint=2"), &e)
+	assertEqual(t, "err", errSemicolon, err)
+	err = ParseReader(strings.NewReader("Int=%zz"), &e)
+	if err == nil {
+		t.Error("Expected an error unescaping")
+	}
+}
+
+func TestParseReaderLimits(t *testing.T) {
+	t.Parallel()
+
+	body := "String=" + strings.Repeat("x", 93)
+	d := NewDecoder(WithMaxBodySize(100))
+	e := Everything{}
+	if err := d.ParseReader(strings.NewReader(body), &e); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "len(e.String)", 93, len(e.String))
+
+	err := d.ParseReader(strings.NewReader(body+"x"), &e)
+	assertEqual(t, "err", LimitError{Limit: "body size", Max: 100}, err)
+
+	// Reading stops at the limit, so a body that never ends is fine.
+	err = d.ParseReader(endless{}, &e)
+	assertEqual(t, "err", LimitError{Limit: "body size", Max: 100}, err)
+
+	d = NewDecoder(WithMaxKeys(1))
+	err = d.ParseReader(strings.NewReader("Int=1&Uint=2&Int=3"), &e)
+	assertEqual(t, "err", LimitError{Limit: "keys", Max: 1}, err)
+
+	readErr := errors.New("oops")
+	err = ParseReader(iotest.ErrReader(readErr), &e)
+	assertEqual(t, "err", readErr, err)
+}
+
+type endless struct{}
+
+func (endless) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 'a'
+	}
+	return len(b), nil
+}
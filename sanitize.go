@@ -0,0 +1,53 @@
+package param
+
+import "net/url"
+
+// SetStringFilter sets a function that every parameter value is passed through
+// before it is parsed, along with its key, so that a service can sanitize input
+// in one place: for instance, by stripping control characters, or by rejecting
+// values that look like attempts at script injection. The filter returns the
+// value to parse in place of the one given, or an error, which Parse returns as
+// a FilterError for the key. Values are filtered in the order of their keys, so
+// the same parameters always produce the same error.
+//
+// The filter sees values of every type, since they are all strings until they
+// are parsed, but not keys, nor defaults given in struct tags. It may be called
+// from several goroutines at once.
+//
+// SetStringFilter must not be called once the Decoder is in use.
+func (d *Decoder) SetStringFilter(filter func(key, value string) (string, error)) {
+	d.stringFilter = filter
+}
+
+// Pass the given parameters through the Decoder's string filter, copying them
+// only if it changes something, since they belong to the caller.
+func (d *Decoder) filterStrings(params url.Values) url.Values {
+	filtered, copied := params, false
+	for _, key := range sortedKeys(params) {
+		values := params[key]
+		var changed []string
+		for i, v := range values {
+			fv, err := d.stringFilter(key, v)
+			if err != nil {
+				panic(FilterError{Key: key, Err: err})
+			}
+			if fv != v && changed == nil {
+				changed = append([]string(nil), values...)
+			}
+			if changed != nil {
+				changed[i] = fv
+			}
+		}
+		if changed == nil {
+			continue
+		}
+		if !copied {
+			filtered, copied = make(url.Values, len(params)), true
+			for k, vs := range params {
+				filtered[k] = vs
+			}
+		}
+		filtered[key] = changed
+	}
+	return filtered
+}
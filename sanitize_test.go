@@ -0,0 +1,63 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+var errScript = errors.New("suspicious value")
+
+func sanitize(key, value string) (string, error) {
+	if strings.Contains(strings.ToLower(value), "<script") {
+		return "", errScript
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value), nil
+}
+
+func TestStringFilter(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	d.SetStringFilter(sanitize)
+
+	params := url.Values{
+		"name":    {"Al\x00ice\n"},
+		"tags[]":  {"a", "b\x07"},
+		"address": {"ok"},
+	}
+	var s struct {
+		Name    string   `param:"name"`
+		Tags    []string `param:"tags"`
+		Address string   `param:"address"`
+	}
+	if err := d.Parse(params, &s); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.Name", "Alice", s.Name)
+	assertEqual(t, "s.Tags", []string{"a", "b"}, s.Tags)
+	assertEqual(t, "s.Address", "ok", s.Address)
+	// The caller's parameters are left alone.
+	assertEqual(t, "params", url.Values{
+		"name":    {"Al\x00ice\n"},
+		"tags[]":  {"a", "b\x07"},
+		"address": {"ok"},
+	}, params)
+
+	err := d.Parse(url.Values{
+		"name":    {"<SCRIPT>alert(1)</script>"},
+		"address": {"<script>"},
+	}, &s)
+	assertEqual(t, "err", FilterError{Key: "address", Err: errScript}, err)
+	assertEqual(t, "ErrorCode", "filter", ErrorCode(err))
+	if !errors.Is(err, ErrFilter) || !errors.Is(err, errScript) {
+		t.Errorf("Expected %v to be ErrFilter and errScript", err)
+	}
+}
@@ -0,0 +1,64 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+)
+
+// WithGroup returns an Option that parses in the given scenario, or group, so
+// that a single struct may be bound differently when, for instance, creating a
+// record and when updating it. Fields may be restricted to some groups with the
+// "groups" tag option, as in `param:"email,groups=create|update"`, and are
+// treated as though they did not exist when parsing in any other, as with
+// Permit. Fields without the option belong to every group.
+//
+// The "required" option may likewise be given the groups in which it applies,
+// as in `param:"name,required=create"`. Such fields may also have a default,
+// which is used when parsing in other groups.
+//
+// Decoders parsing in no group ignore the "groups" option, and treat fields that
+// are only required in some groups as optional. WithGroup is intended for use
+// with With: d.With(param.WithGroup("create")).Parse(params, &user).
+func WithGroup(group string) Option {
+	return func(d *Decoder) {
+		d.group = group
+	}
+}
+
+// Reports whether the given field belongs to the Decoder's group.
+func (d *Decoder) inGroup(l cacheLine) bool {
+	groups, ok := l.opts["groups"]
+	return d.group == "" || !ok || inList(groups, d.group)
+}
+
+// Reports whether the given field is required when parsing in the given group,
+// which may be empty.
+func requiredIn(opts tagOptions, group string) bool {
+	groups, ok := opts["required"]
+	return ok && (groups == "" || group != "" && inList(groups, group))
+}
+
+// Reports whether the given list, separated by "|", includes s.
+func inList(list, s string) bool {
+	for _, e := range strings.Split(list, "|") {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Complain about empty groups, such as in "groups=" or "groups=create||update",
+// which can only be mistakes.
+func checkGroups(s reflect.Type, sf reflect.StructField, opts tagOptions) {
+	groups, ok := opts["groups"]
+	if !ok {
+		return
+	}
+	for _, g := range strings.Split(groups, "|") {
+		if g == "" {
+			pebkac("struct %v field %q has an empty group in %q.",
+				s, sf.Name, groups)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Member struct {
+	ID       int    `param:"id,required=update,groups=update"`
+	Email    string `param:"email,required=create"`
+	Password string `param:"password,required=create,groups=create"`
+	Plan     string `param:"plan,required=create,default=free"`
+	Admin    bool   `param:"admin,groups=admin"`
+}
+
+func TestWithGroup(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	create := d.With(WithGroup("create"))
+	update := d.With(WithGroup("update"))
+
+	a := Member{}
+	err := create.Parse(url.Values{
+		"email":    {"a@example.com"},
+		"password": {"hunter2"},
+		"plan":     {"pro"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.Email", "a@example.com", a.Email)
+	assertEqual(t, "a.Password", "hunter2", a.Password)
+	assertEqual(t, "a.Plan", "pro", a.Plan)
+
+	err = create.Parse(url.Values{"email": {"a@example.com"}}, &Member{})
+	if _, ok := err.(RequiredError); !ok {
+		t.Errorf("Expected RequiredError, got %v", err)
+	}
+	for _, key := range []string{"id", "admin"} {
+		err := create.Parse(url.Values{
+			"email":    {"a@example.com"},
+			"password": {"hunter2"},
+			"plan":     {"pro"},
+			key:        {"1"},
+		}, &Member{})
+		if _, ok := err.(KeyError); !ok {
+			t.Errorf("Expected KeyError for %q, got %v", key, err)
+		}
+	}
+
+	a = Member{}
+	err = update.Parse(url.Values{"id": {"4"}}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.ID", 4, a.ID)
+	assertEqual(t, "a.Plan", "free", a.Plan)
+
+	err = update.Parse(url.Values{"password": {"hunter2"}, "id": {"4"}}, &Member{})
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+	err = update.Parse(url.Values{}, &Member{})
+	if _, ok := err.(RequiredError); !ok {
+		t.Errorf("Expected RequiredError, got %v", err)
+	}
+}
+
+func TestWithoutGroup(t *testing.T) {
+	t.Parallel()
+
+	a := Member{}
+	err := Parse(url.Values{
+		"id":       {"4"},
+		"password": {"hunter2"},
+		"admin":    {"true"},
+	}, &a)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "a.ID", 4, a.ID)
+	assertEqual(t, "a.Password", "hunter2", a.Password)
+	assertEqual(t, "a.Admin", true, a.Admin)
+	assertEqual(t, "a.Plan", "free", a.Plan)
+}
@@ -0,0 +1,80 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Keys that do not correspond to any field may instead be bound to setter
+// methods: if a pointer to a struct has a method SetFoo(string) error, and the
+// struct has no field named "Foo", the value of the key "Foo" is passed to
+// SetFoo. Any error it returns is returned by Parse as a TypeError. This allows
+// types to keep their fields unexported, and their invariants intact, while
+// still being bound directly. Setters are only given single values. Permit,
+// Only, and Except treat them as fields named after the key they bind to, but
+// field filters (see WithFieldFilter) don't apply to them, since they aren't
+// fields.
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// The setters of each struct type, by the name of the key they bind to, as
+// indices into the method set of a pointer to the struct.
+var settersLock sync.RWMutex
+var setters = make(map[reflect.Type]map[string]int)
+
+func setterMethods(t reflect.Type) map[string]int {
+	settersLock.RLock()
+	s, ok := setters[t]
+	settersLock.RUnlock()
+	if ok {
+		return s
+	}
+
+	s = make(map[string]int)
+	pt := reflect.PtrTo(t)
+	for i := 0; i < pt.NumMethod(); i++ {
+		m := pt.Method(i)
+		name, ok := strings.CutPrefix(m.Name, "Set")
+		if !ok || name == "" {
+			continue
+		}
+		// The receiver is the method's first argument.
+		mt := m.Type
+		if mt.NumIn() == 2 && mt.In(1) == stringType &&
+			mt.NumOut() == 1 && mt.Out(0) == errorType {
+			s[name] = i
+		}
+	}
+
+	settersLock.Lock()
+	setters[t] = s
+	settersLock.Unlock()
+	return s
+}
+
+// If target, a struct, has a setter for the key sk, call it with the key's
+// value and report true.
+func (p *parser) callSetter(key, sk, keytail string, values []string, target reflect.Value) bool {
+	i, ok := setterMethods(target.Type())[sk]
+	if !ok || !target.CanAddr() {
+		return false
+	}
+	if p.filtered() && !p.setterEnabled(kpath(key, keytail), sk) {
+		return false
+	}
+
+	value := p.primitive(key, keytail, stringType, values)
+	out := target.Addr().Method(i).Call([]reflect.Value{reflect.ValueOf(value)})
+	if err, _ := out[0].Interface().(error); err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: stringType,
+			Err:  err,
+		})
+	}
+	if p.fields != nil {
+		p.fields[p.canonicalPath(kpath(key, keytail))] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type Slugged struct {
+	Title string `param:"title"`
+
+	slug string
+}
+
+func (s *Slugged) SetSlug(slug string) error {
+	if strings.ContainsAny(slug, " /") {
+		return errors.New("invalid slug")
+	}
+	s.slug = slug
+	return nil
+}
+
+// Fields take precedence over setters.
+func (s *Slugged) SetTitle(string) error {
+	return errors.New("unreachable")
+}
+
+// Not a setter: the signature is wrong.
+func (s *Slugged) SetCount(n int) error {
+	return nil
+}
+
+func TestSetters(t *testing.T) {
+	t.Parallel()
+
+	s := Slugged{}
+	fields, err := ParseWithFields(url.Values{
+		"Slug":  {"hello-world"},
+		"title": {"Hello, World"},
+	}, &s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "s.slug", "hello-world", s.slug)
+	assertEqual(t, "s.Title", "Hello, World", s.Title)
+	assertEqual(t, "fields", []string{"Slug", "title"}, fields.Keys())
+
+	err = Parse(url.Values{"Slug": {"hello world"}}, &s)
+	assertEqual(t, "err", TypeError{
+		Key:  "Slug",
+		Type: stringType,
+		Err:  errors.New("invalid slug"),
+	}, err)
+
+	for _, params := range []url.Values{
+		{"Slug": {"a", "b"}},
+		{"Slug[a]": {"b"}},
+	} {
+		if err := Parse(params, &s); err == nil {
+			t.Errorf("Expected an error parsing %v", params)
+		}
+	}
+	err = Parse(url.Values{"Count": {"1"}}, &s)
+	if _, ok := err.(KeyError); !ok {
+		t.Errorf("Expected KeyError, got %v", err)
+	}
+}
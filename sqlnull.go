@@ -0,0 +1,28 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Reports whether the given type is one of the nullable types from
+// database/sql: sql.NullString, sql.NullInt64, sql.Null[T], and so on. These
+// are all structs with a value field followed by a boolean "Valid" field. We
+// identify them by name, which saves us from having to link in database/sql.
+func isSQLNull(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" &&
+		strings.HasPrefix(t.Name(), "Null") && t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" && t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// The nullable types from database/sql are parsed as though they were their
+// value field, and are marked as valid if that succeeds. If their key is absent,
+// they are left alone, which for a newly allocated value means they are null.
+func parseSQLNull(p *parser, key, keytail string, values []string, target reflect.Value) {
+	if p.null(keytail, values) {
+		target.Set(reflect.Zero(target.Type()))
+		return
+	}
+	parseValue(p, key, keytail, values, target.Field(0))
+	target.Field(1).SetBool(true)
+}
@@ -0,0 +1,93 @@
+package param
+
+import (
+	"database/sql"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Nullable struct {
+	String  sql.NullString            `param:"string"`
+	Int64   sql.NullInt64             `param:"int64"`
+	Float64 sql.NullFloat64           `param:"float64"`
+	Bool    sql.NullBool              `param:"bool"`
+	Time    sql.NullTime              `param:"time"`
+	Int16   sql.NullInt16             `param:"int16"`
+	Ptr     *sql.NullString           `param:"ptr"`
+	Slice   []sql.NullInt32           `param:"slice"`
+	Map     map[string]sql.NullString `param:"map"`
+}
+
+func TestSQLNull(t *testing.T) {
+	t.Parallel()
+
+	n := Nullable{}
+	err := Parse(url.Values{
+		"string":  {"bob"},
+		"int64":   {"-64"},
+		"float64": {"6.4"},
+		"time":    {testTimeString},
+		"ptr":     {""},
+		"slice[]": {"1", "2"},
+		"map[a]":  {"b"},
+	}, &n)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	assertEqual(t, "n.String", sql.NullString{String: "bob", Valid: true},
+		n.String)
+	assertEqual(t, "n.Int64", sql.NullInt64{Int64: -64, Valid: true},
+		n.Int64)
+	assertEqual(t, "n.Float64", sql.NullFloat64{Float64: 6.4, Valid: true},
+		n.Float64)
+	assertEqual(t, "n.Time", sql.NullTime{Time: testTime, Valid: true},
+		n.Time)
+	assertEqual(t, "n.Ptr", &sql.NullString{Valid: true}, n.Ptr)
+	assertEqual(t, "n.Slice", []sql.NullInt32{{Int32: 1, Valid: true}, {Int32: 2, Valid: true}},
+		n.Slice)
+	assertEqual(t, "n.Map", map[string]sql.NullString{
+		"a": {String: "b", Valid: true},
+	},
+		n.Map)
+
+	// Absent keys are null
+	assertEqual(t, "n.Bool", sql.NullBool{}, n.Bool)
+	assertEqual(t, "n.Int16", sql.NullInt16{}, n.Int16)
+
+	err = Parse(url.Values{"int64": {"llama"}}, &n)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+	err = Parse(url.Values{"int64[x]": {"1"}}, &n)
+	if _, ok := err.(NestingError); !ok {
+		t.Errorf("Expected NestingError, got %v", err)
+	}
+}
+
+func TestSQLNullEncode(t *testing.T) {
+	t.Parallel()
+
+	n := Nullable{
+		String: sql.NullString{String: "bob", Valid: true},
+		Time:   sql.NullTime{Time: time.Time{}, Valid: false},
+	}
+	params, err := Encode(n)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	assertEqual(t, "params", url.Values{"string": {"bob"}}, params)
+}
+
+func TestIsSQLNull(t *testing.T) {
+	t.Parallel()
+
+	if !isSQLNull(reflect.TypeOf(sql.NullByte{})) {
+		t.Error("Expected sql.NullByte to be nullable")
+	}
+	if isSQLNull(reflect.TypeOf(sql.RawBytes{})) {
+		t.Error("Expected sql.RawBytes not to be nullable")
+	}
+}
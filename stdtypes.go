@@ -0,0 +1,43 @@
+package param
+
+import (
+	"net/mail"
+	"net/url"
+	"reflect"
+)
+
+// Most of the standard library's types that are naturally given as a single
+// string, such as net.IP, netip.Addr, and netip.Prefix, implement
+// encoding.TextUnmarshaler and so need no special treatment. These don't.
+var urlType = reflect.TypeOf(url.URL{})
+var mailAddressType = reflect.TypeOf(mail.Address{})
+
+func parseURL(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+
+	u, err := url.Parse(value)
+	if err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  err,
+		})
+	}
+	target.Set(reflect.ValueOf(*u))
+}
+
+// Addresses are parsed as by mail.ParseAddress, so they may include a name, as
+// in "Alice <alice@example.com>".
+func parseMailAddress(p *parser, key, keytail string, values []string, target reflect.Value) {
+	value := p.primitive(key, keytail, target.Type(), values)
+
+	addr, err := mail.ParseAddress(value)
+	if err != nil {
+		panic(TypeError{
+			Key:  kpath(key, keytail),
+			Type: target.Type(),
+			Err:  err,
+		})
+	}
+	target.Set(reflect.ValueOf(*addr))
+}
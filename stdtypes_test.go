@@ -0,0 +1,64 @@
+package param
+
+import (
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+type Endpoint struct {
+	IP       net.IP        `param:"ip"`
+	Addr     netip.Addr    `param:"addr"`
+	Prefix   netip.Prefix  `param:"prefix"`
+	Callback url.URL       `param:"callback"`
+	Mirrors  []*url.URL    `param:"mirrors"`
+	Contact  mail.Address  `param:"contact"`
+	CC       *mail.Address `param:"cc"`
+}
+
+func TestStdTypes(t *testing.T) {
+	t.Parallel()
+
+	e := Endpoint{}
+	params := url.Values{
+		"ip":        {"192.0.2.1"},
+		"addr":      {"2001:db8::1"},
+		"prefix":    {"10.0.0.0/8"},
+		"callback":  {"https://example.com/hook?x=1"},
+		"mirrors[]": {"https://a.example.com", "/relative"},
+		"contact":   {"Alice <alice@example.com>"},
+		"cc":        {"bob@example.com"},
+	}
+	err := Parse(params, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.IP", net.ParseIP("192.0.2.1"), e.IP)
+	assertEqual(t, "e.Addr", netip.MustParseAddr("2001:db8::1"), e.Addr)
+	assertEqual(t, "e.Prefix", netip.MustParsePrefix("10.0.0.0/8"), e.Prefix)
+	assertEqual(t, "e.Callback.Host", "example.com", e.Callback.Host)
+	assertEqual(t, "e.Callback.RawQuery", "x=1", e.Callback.RawQuery)
+	assertEqual(t, "e.Mirrors[1].Path", "/relative", e.Mirrors[1].Path)
+	assertEqual(t, "e.Contact", mail.Address{
+		Name:    "Alice",
+		Address: "alice@example.com",
+	}, e.Contact)
+	assertEqual(t, "e.CC.Address", "bob@example.com", e.CC.Address)
+
+	encoded, err := Encode(e)
+	if err != nil {
+		t.Fatal("Encode error: ", err)
+	}
+	params["contact"] = []string{`"Alice" <alice@example.com>`}
+	params["cc"] = []string{"<bob@example.com>"}
+	assertEqual(t, "encoded", params, encoded)
+
+	for _, key := range []string{"callback", "contact"} {
+		err = Parse(url.Values{key: {"%zz not valid"}}, &e)
+		if _, ok := err.(TypeError); !ok {
+			t.Errorf("Expected TypeError for %q, got %v", key, err)
+		}
+	}
+}
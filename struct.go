@@ -16,7 +16,34 @@ import (
 type structCache map[string]cacheLine
 type cacheLine struct {
 	offset int
-	parse  func(string, string, []string, reflect.Value)
+	parse  parseFunc
+	opts   tagOptions
+	// The field's aliases, if it has any (see the "alias" option).
+	aliases []string
+	// Whether the field may be set to null (see WithJSONNulls).
+	jsonNull bool
+	// Whether the field is an embedded struct whose fields are promoted.
+	promoted bool
+	// The fields the field's required_if and required_with options refer
+	// to, if it has them.
+	cond *conditions
+	// Sets the field without reflection, if it is simple enough (see
+	// WithUnsafeFields).
+	fast fastSetter
+}
+
+// The type of the parse functions in parse.go. See parse() for what the
+// arguments mean.
+type parseFunc func(p *parser, key, keytail string, values []string, target reflect.Value)
+
+// Options that follow the name in a "param" struct tag, as in
+// `param:"limit,required"` or `param:"limit,default=25"`. Options without an
+// equals sign map to the empty string.
+type tagOptions map[string]string
+
+func (o tagOptions) has(opt string) bool {
+	_, ok := o[opt]
+	return ok
 }
 
 var cacheLock sync.RWMutex
@@ -32,7 +59,19 @@ func cacheStruct(t reflect.Type) structCache {
 	}
 
 	// It's okay if two people build struct caches simultaneously
-	sc = make(structCache)
+	sc = buildStruct(t, nil)
+
+	cacheLock.Lock()
+	cache[t] = sc
+	cacheLock.Unlock()
+
+	return sc
+}
+
+// Build the cache for the given struct type, naming fields as n says, or as
+// usual if n is nil.
+func buildStruct(t reflect.Type, n *naming) structCache {
+	sc := make(structCache)
 	for i := 0; i < t.NumField(); i++ {
 		sf := t.Field(i)
 		// Only unexported fields have a PkgPath; we want to only cache
@@ -40,30 +79,48 @@ func cacheStruct(t reflect.Type) structCache {
 		if sf.PkgPath != "" && !sf.Anonymous {
 			continue
 		}
-		name := extractName(sf)
-		if name != "-" {
-			sc[name] = cacheLine{i, extractHandler(t, sf)}
+		name, skip := extractName(sf), ignored(sf)
+		untagged := tagName(sf) == ""
+		if n != nil {
+			name, skip = n.name(sf)
+			untagged = namingTag(sf, n.tags) == ""
+		}
+		if !skip {
+			opts := extractOptions(sf)
+			if n != nil && n.protoJSON {
+				opts = protoJSONAlias(sf, name, opts)
+			}
+			if opts.has("flatten") {
+				checkFlatten(t, sf)
+			}
+			h := extractHandler(t, sf)
+			if isJSONUnmarshaler(sf.Type) {
+				h = wrapJSONUnmarshaler(h)
+			}
+			sc[name] = cacheLine{
+				offset:  i,
+				parse:   wrapHandler(t, sf, opts, h),
+				opts:    opts,
+				aliases: aliases(opts),
+				jsonNull: jsonNamed(sf) &&
+					(sf.Type.Kind() == reflect.Ptr || sf.Type == timeType),
+				promoted: promotes(sf, untagged),
+				fast:     fastSetterFor(sf, opts),
+			}
+			checkGroups(t, sf, opts)
+			checkDefault(t, sf, name, sc[name])
 		}
 	}
 
-	cacheLock.Lock()
-	cache[t] = sc
-	cacheLock.Unlock()
-
+	checkAliases(t, sc)
+	checkConditions(t, sc)
 	return sc
 }
 
 // Extract the name of the given struct field, looking at struct tags as
 // appropriate.
 func extractName(sf reflect.StructField) string {
-	name := sf.Tag.Get("param")
-	if name == "" {
-		name = sf.Tag.Get("json")
-		idx := strings.IndexRune(name, ',')
-		if idx >= 0 {
-			name = name[:idx]
-		}
-	}
+	name := tagName(sf)
 	if name == "" {
 		name = sf.Name
 	}
@@ -71,10 +128,116 @@ func extractName(sf reflect.StructField) string {
 	return name
 }
 
-func extractHandler(s reflect.Type, sf reflect.StructField) func(string, string, []string, reflect.Value) {
+// Extract the name given to the given struct field by its tags, if any.
+func tagName(sf reflect.StructField) string {
+	name, _ := splitTag(namingTag(sf, defaultTagNames))
+	return name
+}
+
+// Reports whether the given struct field is to be ignored, because the tag its
+// name comes from is exactly "-". As with encoding/json, a tag of "-," names
+// the field "-" instead.
+func ignored(sf reflect.StructField) bool {
+	return namingTag(sf, defaultTagNames) == "-"
+}
+
+// The tags that field names are taken from, in order of preference, unless a
+// Decoder says otherwise (see WithTagNames).
+var defaultTagNames = []string{"param", "mapstructure", "json"}
+
+// Returns the first of the given tags of the given struct field that gives it a
+// name, or the empty string if none do.
+func namingTag(sf reflect.StructField, keys []string) string {
+	for _, key := range keys {
+		tag := sf.Tag.Get(key)
+		if name, _ := splitTag(tag); name != "" {
+			return tag
+		}
+	}
+	return ""
+}
+
+// Extract the options of the given struct field's "param" tag.
+func extractOptions(sf reflect.StructField) tagOptions {
+	_, opts := splitTag(sf.Tag.Get("param"))
+	return opts
+}
+
+func splitTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	opts := make(tagOptions)
+	for _, opt := range parts[1:] {
+		if i := strings.IndexRune(opt, '='); i >= 0 {
+			opts[opt[:i]] = opt[i+1:]
+		} else {
+			opts[opt] = ""
+		}
+	}
+	return parts[0], opts
+}
+
+// Default values are parsed exactly as though they had been passed in as
+// parameters. Make sure that's possible now instead of failing on some request
+// far in the future that happens to omit the key.
+func checkDefault(s reflect.Type, sf reflect.StructField, name string, l cacheLine) {
+	def, ok := l.opts["default"]
+	if !ok {
+		return
+	}
+	if requiredIn(l.opts, "") {
+		pebkac("struct %v field %q is both required and has a default.",
+			s, sf.Name)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			pebkac("struct %v field %q has invalid default %q: %v",
+				s, sf.Name, def, r)
+		}
+	}()
+	l.parse(defaultParser(), name, "", []string{def}, reflect.New(sf.Type).Elem())
+}
+
+func extractHandler(s reflect.Type, sf reflect.StructField) parseFunc {
+	// Fields with the base64 option may be of types we would otherwise
+	// reject, and wrapHandler substitutes their handler anyway.
+	if extractOptions(sf).has("base64") {
+		return nil
+	}
+	if isBig(sf.Type) {
+		return parseBig
+	}
+	if reflect.PtrTo(sf.Type).Implements(contextTextUnmarshalerType) {
+		return parseContextTextUnmarshaler
+	}
 	if reflect.PtrTo(sf.Type).Implements(textUnmarshalerType) {
 		return parseTextUnmarshaler
 	}
+	if reflect.PtrTo(sf.Type).Implements(flagValueType) {
+		return parseFlagValue
+	}
+
+	switch sf.Type {
+	case tcpAddrType:
+		return parseTCPAddr
+	case udpAddrType:
+		return parseUDPAddr
+	case rawMessageType:
+		return parseRawMessage
+	case urlType:
+		return parseURL
+	case mailAddressType:
+		return parseMailAddress
+	}
+	if isSQLNull(sf.Type) {
+		return parseSQLNull
+	}
+	if isOptional(sf.Type) {
+		return parseOptional
+	}
+	if isBytes(sf.Type) {
+		return parseBytes
+	}
 
 	switch sf.Type.Kind() {
 	case reflect.Bool:
@@ -95,6 +258,13 @@ func extractHandler(s reflect.Type, sf reflect.StructField) func(string, string,
 		return parseString
 	case reflect.Struct:
 		return parseStruct
+	case reflect.Interface:
+		// Interfaces can only be parsed with the help of a
+		// discriminator, whose handler wrapHandler substitutes.
+		if extractOptions(sf).has("discriminator") {
+			return nil
+		}
+		fallthrough
 
 	default:
 		pebkac("struct %v has illegal field %q (type %v, kind %v).",
@@ -103,19 +273,172 @@ func extractHandler(s reflect.Type, sf reflect.StructField) func(string, string,
 	}
 }
 
+// Some tag options change how a field's values are parsed. We implement these by
+// wrapping the field's handler, which is why they only apply to the field
+// itself and its elements, and not to the fields of nested structs.
+func wrapHandler(s reflect.Type, sf reflect.StructField, opts tagOptions, h parseFunc) parseFunc {
+	if opts.has("percent") {
+		h = wrapPercent(s, sf, h)
+	}
+	if opts.has("bytesize") {
+		h = wrapByteSize(s, sf, h)
+	}
+	if base, ok := opts["base"]; ok {
+		h = wrapBase(s, sf, base, h)
+	}
+	if sep, ok := opts["decimal"]; ok {
+		h = wrapDecimal(s, sf, sep, h)
+	}
+	if port, ok := opts["hostport"]; ok {
+		h = wrapHostPort(s, sf, port, h)
+	}
+	if enc, ok := opts["base64"]; ok {
+		h = base64Handler(s, sf, enc)
+	}
+	if opts.has("raw") {
+		h = rawHandler(s, sf)
+	}
+	if kf, ok := opts["keyfield"]; ok {
+		h = keyFieldHandler(s, sf, kf, h)
+	}
+	if disc, ok := opts["discriminator"]; ok {
+		h = discriminatorHandler(s, sf, disc)
+	}
+	if opts.has("append") {
+		h = wrapAppend(s, sf, h)
+	}
+	if opts.has("min") || opts.has("max") || opts.has("clamp") {
+		h = wrapBounds(s, sf, opts, h)
+	}
+	if oneof, ok := opts["oneof"]; ok {
+		h = wrapOneOf(s, sf, oneof, h)
+	}
+	if pattern, ok := opts["pattern"]; ok {
+		h = wrapPattern(s, sf, pattern, h)
+	}
+	if maxlen, ok := opts["maxlen"]; ok {
+		h = wrapMaxLen(s, sf, maxlen, h)
+	}
+	if maxitems, ok := opts["maxitems"]; ok {
+		h = wrapMaxItems(s, sf, maxitems, h)
+	}
+	// Values are converted before the options above check them.
+	if opts.has("lower") || opts.has("upper") || opts.has("title") {
+		h = wrapCase(s, sf, opts, h)
+	}
+	// These come last so that values are trimmed before any other option
+	// sees them.
+	if opts.has("trim") {
+		h = transformValues(h, trimSpace)
+	}
+	return wrapTrimSpace(opts, h)
+}
+
+// Returns the type of the values that end up being parsed into the given type,
+// looking through pointers and slices.
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		if isLeaf(t) {
+			break
+		}
+		t = t.Elem()
+	}
+	return t
+}
+
+// Wraps h so that every value is passed through fn before h sees it. Errors
+// returned by fn are reported as TypeErrors.
+func transformValues(h parseFunc, fn func(string) (string, error)) parseFunc {
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		// We only copy the values if fn changes any of them.
+		transformed := values
+		for i, v := range values {
+			t, err := fn(v)
+			if err != nil {
+				panic(TypeError{
+					Key:  kpath(key, keytail),
+					Type: target.Type(),
+					Err:  err,
+				})
+			}
+			if t == v {
+				continue
+			}
+			if &transformed[0] == &values[0] {
+				transformed = append([]string(nil), values...)
+			}
+			transformed[i] = t
+		}
+		h(p, key, keytail, transformed, target)
+	}
+}
+
 // We have to parse two types of structs: ones at the top level, whose keys
 // don't have square brackets around them, and nested structs, which do.
-func parseStructField(cache structCache, key, sk, keytail string, values []string, target reflect.Value) {
-	l, ok := cache[sk]
+func parseStructField(p *parser, cache structCache, key, sk, keytail string, values []string, target reflect.Value) {
+	path := kpath(key, keytail)
+	name, l, ok := cache.lookup(sk)
+	if !ok {
+		if ec, ev, found := p.promoted(cache, sk, target); found {
+			parseStructField(p, ec, key, sk, keytail, values, ev)
+			return
+		}
+		if fc, fv, rest, found := p.flattened(cache, sk, target); found {
+			parseStructField(p, fc, key, rest, keytail, values, fv)
+			return
+		}
+		if p.callSetter(key, sk, keytail, values, target) {
+			return
+		}
+	}
+	if ok && p.filtered() {
+		ok = p.fieldEnabled(path, target.Type(), name, l)
+	}
+	if !ok && p.unknownKeys != nil {
+		p.unknownKeys = append(p.unknownKeys, p.detach(key))
+		p.warn(WarnUnknownKey, key, values...)
+		return
+	}
 	if !ok {
+		prefix := structPrefix(path, sk)
 		panic(KeyError{
 			FullKey: key,
-			Key:     kpath(key, keytail),
+			Key:     path,
 			Type:    target.Type(),
 			Field:   sk,
+			Suggestion: suggestField(cache, sk, func(name string, l cacheLine) bool {
+				return !p.filtered() || p.fieldEnabled(
+					subkey(prefix, name), target.Type(), name, l)
+			}),
 		})
 	}
+	if l.aliases != nil {
+		p.checkAliasConflict(structPrefix(path, sk), name, l)
+	}
+	if p.fields != nil {
+		path = p.canonicalField(path, sk, name)
+	}
+	if p.unsafeFields && p.setFast(l, key, keytail, path, values, target) {
+		return
+	}
 	f := target.Field(l.offset)
+	defer recoverInternal(key, f.Type())
+	if p.fillZero && filled(f) {
+		return
+	}
+	if p.fields != nil {
+		p.fields[path] = struct{}{}
+	}
 
-	l.parse(key, keytail, values, f)
+	if l.jsonNull && p.jsonNull(keytail, values) {
+		f.Set(reflect.Zero(f.Type()))
+		return
+	}
+	if p.hooks != nil && f.Kind() != reflect.Ptr {
+		var done bool
+		if values, done = p.runHooks(key, keytail, values, f); done {
+			return
+		}
+	}
+	l.parse(p, key, keytail, values, f)
 }
@@ -1,6 +1,7 @@
 package param
 
 import (
+	"net/url"
 	"reflect"
 	"testing"
 )
@@ -39,15 +40,15 @@ var fruityNames = []string{
 }
 
 var fruityCache = map[string]cacheLine{
-	"A":           {0, parseBool},
-	"banana":      {1, parseInt},
-	"cherry":      {2, parseUint},
-	"dragonfruit": {3, parseFloat},
-	"fig":         {5, parseMap},
-	"grape":       {6, parsePtr},
-	"honeydew":    {7, parseSlice},
-	"I":           {8, parseString},
-	"jackfruit":   {9, parseStruct},
+	"A":           {offset: 0, parse: parseBool},
+	"banana":      {offset: 1, parse: parseInt},
+	"cherry":      {offset: 2, parse: parseUint},
+	"dragonfruit": {offset: 3, parse: parseFloat},
+	"fig":         {offset: 5, parse: parseMap},
+	"grape":       {offset: 6, parse: parsePtr},
+	"honeydew":    {offset: 7, parse: parseSlice},
+	"I":           {offset: 8, parse: parseString},
+	"jackfruit":   {offset: 9, parse: parseStruct},
 }
 
 func assertEqual(t *testing.T, what string, e, a interface{}) {
@@ -104,3 +105,31 @@ func TestPrivate(t *testing.T) {
 		t.Error("Expected Private{} to have one cachable field")
 	}
 }
+
+type Dashing struct {
+	Dash    int `param:"-,"`
+	Ignored int `param:"-"`
+	JSON    int `json:"-,omitempty"`
+	NotJSON int `json:"-"`
+}
+
+func TestDashName(t *testing.T) {
+	t.Parallel()
+
+	dashingType := reflect.TypeOf(Dashing{})
+	for i, e := range []bool{false, true, false, true} {
+		sf := dashingType.Field(i)
+		assertEqual(t, "ignored("+sf.Name+")", e, ignored(sf))
+	}
+	assertEqual(t, "len(cache)", 1, len(cacheStruct(dashingType)))
+
+	var d struct {
+		Dash    int `param:"-,"`
+		Ignored int `param:"-"`
+	}
+	err := Parse(url.Values{"-": {"1"}}, &d)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "d.Dash", 1, d.Dash)
+}
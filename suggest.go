@@ -0,0 +1,65 @@
+package param
+
+import (
+	"strings"
+)
+
+// Returns the name of the field in the given struct cache that is most similar
+// to the given (unknown) field name, if any is similar enough that it might
+// plausibly have been intended. This is a typo detector, so the threshold is
+// an edit distance of one for every four characters. Only fields for which
+// enabled returns true are considered.
+func suggestField(cache structCache, field string, enabled func(string, cacheLine) bool) string {
+	best, bestDist := "", len(field)/4+1
+	for name, l := range cache {
+		if !enabled(name, l) {
+			continue
+		}
+		d := editDistance(strings.ToLower(field), strings.ToLower(name))
+		if d < bestDist || d == bestDist && name < best {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// Computes the optimal string alignment distance between a and b: the number of
+// single-byte insertions, deletions, substitutions, and transpositions of
+// adjacent bytes needed to transform one into the other.
+func editDistance(a, b string) int {
+	// We only ever need the previous two rows of the usual dynamic
+	// programming table.
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] &&
+				prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,59 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+var editDistances = []struct {
+	a, b string
+	d    int
+}{
+	{"", "", 0},
+	{"", "abc", 3},
+	{"email", "email", 0},
+	{"emial", "email", 1},
+	{"kitten", "sitting", 3},
+	{"ca", "abc", 3},
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range editDistances {
+		assertEqual(t, test.a+" -> "+test.b, test.d,
+			editDistance(test.a, test.b))
+		assertEqual(t, test.b+" -> "+test.a, test.d,
+			editDistance(test.b, test.a))
+	}
+}
+
+type Signup struct {
+	Email    string `param:"email"`
+	Name     string `param:"name"`
+	Password string `param:"password"`
+}
+
+func TestSuggestion(t *testing.T) {
+	t.Parallel()
+
+	for field, suggestion := range map[string]string{
+		"emial":     "email",
+		"Email":     "email",
+		"nmae":      "name",
+		"passwrod":  "password",
+		"pasword":   "password",
+		"llama":     "",
+		"x":         "",
+		"usernames": "",
+	} {
+		err := Parse(url.Values{field: {"1"}}, &Signup{})
+		kerr, ok := err.(KeyError)
+		if !ok {
+			t.Errorf("Expected KeyError, got %v", err)
+			continue
+		}
+		assertEqual(t, "suggestion for "+field, suggestion,
+			kerr.Suggestion)
+	}
+}
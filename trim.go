@@ -0,0 +1,62 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+)
+
+// WithTrimSpace returns an Option that trims leading and trailing white space
+// from every value before it is parsed, so that for instance " 42 " is parsed as
+// the integer 42, and " Bob " as the string "Bob". The "trim" tag option, as in
+// `param:"age,trim"`, does the same for a single field (and the values nested
+// within it). Values are trimmed before any other tag option sees them.
+func WithTrimSpace() Option {
+	return func(d *Decoder) {
+		d.trimSpace = true
+	}
+}
+
+func trimSpace(v string) (string, error) {
+	return strings.TrimSpace(v), nil
+}
+
+// The tag options that look at values before the parse functions in parse.go
+// do, and so need them trimmed beforehand. Other options see values only by
+// way of those parse functions, or else take them verbatim, as raw does.
+var earlyOptions = []string{
+	"percent", "bytesize", "base", "decimal", "hostport", "pattern", "maxlen",
+	"lower", "upper", "title",
+}
+
+// Wraps h so that its values are trimmed if the Decoder calls for it, for the
+// sake of fields with any of earlyOptions.
+func wrapTrimSpace(opts tagOptions, h parseFunc) parseFunc {
+	early := false
+	for _, opt := range earlyOptions {
+		early = early || opts.has(opt)
+	}
+	if !early {
+		return h
+	}
+
+	return func(p *parser, key, keytail string, values []string, target reflect.Value) {
+		if !p.trimSpace {
+			h(p, key, keytail, values, target)
+			return
+		}
+		// As in transformValues, we only copy the values if we must.
+		trimmed := values
+		for i, v := range values {
+			t := strings.TrimSpace(v)
+			if t == v {
+				continue
+			}
+			p.warn(WarnTrimmed, kpath(key, keytail), v)
+			if &trimmed[0] == &values[0] {
+				trimmed = append([]string(nil), values...)
+			}
+			trimmed[i] = t
+		}
+		h(p, key, keytail, trimmed, target)
+	}
+}
@@ -0,0 +1,105 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Trimmed struct {
+	Age   int      `param:"age,trim"`
+	Tags  []string `param:"tags,trim"`
+	Ratio float64  `param:"ratio,trim,percent"`
+	Name  string   `param:"name"`
+}
+
+func TestTrim(t *testing.T) {
+	t.Parallel()
+
+	params := url.Values{
+		"age":    {" 42\t"},
+		"tags[]": {" a", "b "},
+		"ratio":  {" 50% "},
+	}
+	var tr Trimmed
+	if err := Parse(params, &tr); err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "tr", Trimmed{Age: 42, Tags: []string{"a", "b"}, Ratio: 0.5},
+		tr)
+
+	err := Parse(url.Values{"name": {" Bob "}}, &tr)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "tr.Name", " Bob ", tr.Name)
+}
+
+func TestTrimSpace(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithTrimSpace())
+	e := Everything{}
+	err := d.Parse(url.Values{
+		"Int":       {" 42 "},
+		"Bool":      {"\ntrue"},
+		"String":    {" Bob "},
+		"Map[a]":    {" 1"},
+		"Struct[A]": {"2 "},
+		"Time":      {" " + testTimeString + " "},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 42, e.Int)
+	assertEqual(t, "e.Bool", true, e.Bool)
+	assertEqual(t, "e.String", "Bob", e.String)
+	assertEqual(t, "e.Map", map[string]int{"a": 1}, e.Map)
+	assertEqual(t, "e.Struct.A", 2, e.Struct.A)
+	assertEqual(t, "e.Time", testTime, e.Time)
+
+	err = Parse(url.Values{"Int": {" 42 "}}, &Everything{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
+
+type TrimmedOptions struct {
+	Mask  uint16   `param:"mask,base=16"`
+	Size  int64    `param:"size,bytesize"`
+	Ratio float64  `param:"ratio,percent"`
+	Slug  string   `param:"slug,pattern=^[a-z]+$"`
+	Bio   string   `param:"bio,maxlen=3"`
+	Codes []string `param:"codes,upper"`
+}
+
+func TestTrimSpaceOptions(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithTrimSpace())
+	var o TrimmedOptions
+	warnings, err := d.ParseWithWarnings(url.Values{
+		"mask":    {" ff "},
+		"size":    {" 1KiB "},
+		"ratio":   {" 5% "},
+		"slug":    {" abc "},
+		"bio":     {" abc "},
+		"codes[]": {" us", "fr "},
+	}, &o)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "o", TrimmedOptions{
+		Mask:  0xff,
+		Size:  1024,
+		Ratio: 0.05,
+		Slug:  "abc",
+		Bio:   "abc",
+		Codes: []string{"US", "FR"},
+	}, o)
+	assertEqual(t, "len(warnings)", 7, len(warnings))
+
+	err = Parse(url.Values{"mask": {" ff "}}, &TrimmedOptions{})
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package param
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Validator is implemented by types that can check their own validity. If the
+// target of a parse, or any value reachable from it, implements Validator, its
+// Validate method is called once parsing is otherwise complete. Values are
+// validated after everything they contain has been, so a struct's Validate
+// method may assume that its fields are individually valid. The first error
+// returned by a Validate method is returned by Parse as a ValidationError.
+//
+// The target itself is always validated, but struct fields are only validated
+// if they were given, or if the target already held a value for them other
+// than the zero value: a nested struct that no parameter mentions was never
+// asked for, and so isn't held to its Validate method. Each value pointed to is
+// validated only once, however many pointers lead to it, so cyclic structures
+// are safe.
+type Validator interface {
+	Validate() error
+}
+
+// ContextValidator is like Validator, but its ValidateContext method is also
+// passed the context given to ParseContext (or context.Background(), for
+// Parse). Types implementing both interfaces are validated with
+// ValidateContext.
+type ContextValidator interface {
+	ValidateContext(ctx context.Context) error
+}
+
+var validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+var contextValidatorType = reflect.TypeOf((*ContextValidator)(nil)).Elem()
+
+// Whether it's worth looking for Validators within values of a given type. We
+// compute this once per type so that parsing into types without Validators
+// doesn't have to walk the whole target after the fact.
+var validatableLock sync.RWMutex
+var validatable = make(map[reflect.Type]bool)
+
+func isValidatable(t reflect.Type) bool {
+	validatableLock.RLock()
+	v, ok := validatable[t]
+	validatableLock.RUnlock()
+	if ok {
+		return v
+	}
+
+	v = findImplementer(t, make(map[reflect.Type]bool), validatorType,
+		contextValidatorType)
+
+	validatableLock.Lock()
+	validatable[t] = v
+	validatableLock.Unlock()
+	return v
+}
+
+// Reports whether t, or a pointer to it, or any type reachable from it,
+// implements any of the given interfaces.
+func findImplementer(t reflect.Type, seen map[reflect.Type]bool, ifaces ...reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	for _, iface := range ifaces {
+		if t.Implements(iface) || pt.Implements(iface) {
+			return true
+		}
+	}
+	if seen[t] || isLeaf(t) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return findImplementer(t.Elem(), seen, ifaces...)
+	case reflect.Struct:
+		for _, l := range cacheStruct(t) {
+			if findImplementer(t.Field(l.offset).Type, seen, ifaces...) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// A value pointed to, identified by its address and type, since a struct and
+// its first field share an address.
+type pointee struct {
+	ptr uintptr
+	t   reflect.Type
+}
+
+// Run every Validator reachable from v, whose key is key.
+func runValidators(p *parser, key string, v reflect.Value) {
+	if !isValidatable(v.Type()) {
+		return
+	}
+
+	t := v.Type()
+	switch {
+	case isLeaf(t):
+	case t.Kind() == reflect.Ptr:
+		// Pointers can't have methods of their own, so there's
+		// nothing more to do after validating what they point to.
+		if v.IsNil() {
+			return
+		}
+		pe := pointee{v.Pointer(), t.Elem()}
+		if p.validated[pe] {
+			return
+		}
+		if p.validated == nil {
+			p.validated = make(map[pointee]bool)
+		}
+		p.validated[pe] = true
+		runValidators(p, key, v.Elem())
+		return
+	case t.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			runValidators(p, key+"["+strconv.Itoa(i)+"]", v.Index(i))
+		}
+	case t.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, mk := range keys {
+			// Map values aren't addressable, so we make a copy in
+			// case Validate has a pointer receiver.
+			mv := reflect.New(t.Elem()).Elem()
+			mv.Set(v.MapIndex(mk))
+			runValidators(p, key+"["+escapeKey(mk.String())+"]", mv)
+		}
+	case t.Kind() == reflect.Struct:
+		cache := p.cached(t)
+		names := make([]string, 0, len(cache))
+		for name := range cache {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fkey := fieldKey(key, name)
+			f := v.Field(cache[name].offset)
+			if p.present(fkey, f) {
+				runValidators(p, fkey, f)
+			}
+		}
+	}
+
+	var val interface{}
+	if v.CanAddr() && (reflect.PtrTo(t).Implements(contextValidatorType) ||
+		reflect.PtrTo(t).Implements(validatorType)) {
+		val = v.Addr().Interface()
+	} else if t.Implements(contextValidatorType) || t.Implements(validatorType) {
+		val = v.Interface()
+	} else {
+		return
+	}
+
+	var err error
+	if cv, ok := val.(ContextValidator); ok {
+		err = cv.ValidateContext(p.ctx)
+	} else {
+		err = val.(Validator).Validate()
+	}
+	if err != nil {
+		panic(ValidationError{
+			Key:  key,
+			Type: t,
+			Err:  err,
+		})
+	}
+}
+
+// Validate parses the given parameters as Parse would, returning the same error
+// Parse would, but into a throwaway value of the type of prototype, which must
+// be a struct or a map, or a pointer to one. prototype itself is left
+// untouched. This allows requests to be validated up front, for instance in
+// middleware, before anything is done with them.
+//
+// Since the throwaway value starts out as the zero value of its type, Validate
+// may disagree with Parse when parsing into a struct that already has values.
+func Validate(params url.Values, prototype interface{}) error {
+	return defaultDecoder.validate("param.Validate", params, prototype)
+}
+
+// Validate is like the package-level Validate, but parses as the Decoder would.
+func (d *Decoder) Validate(params url.Values, prototype interface{}) error {
+	return d.validate("param.Decoder.Validate", params, prototype)
+}
+
+func (d *Decoder) validate(fn string, params url.Values, prototype interface{}) error {
+	// Anything that isn't a struct or a pointer to one is passed through
+	// for decode to complain about.
+	target := prototype
+	if t := reflect.TypeOf(prototype); t != nil {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct || t.Kind() == reflect.Map {
+			target = reflect.New(t).Interface()
+		}
+	}
+	p := d.newParser(context.Background())
+	defer p.release()
+	return d.run(p, fn, params, target)
+}
+
+// SetValidator sets a function that is passed the target of every successful
+// parse, once any Validators have been run. This is intended for use with
+// validation libraries such as github.com/go-playground/validator: for
+// instance, d.SetValidator(validate.Struct).
+//
+// If the validation function returns an error, Parse returns it as a
+// ValidationError, or if the error is a slice of errors describing individual
+// struct fields, as ValidationErrors. An error describes a struct field if it
+// has a method StructNamespace() string that returns a dotted path of Go field
+// names, such as "Form.Address.City", as the field errors of
+// github.com/go-playground/validator do. The keys of the resulting
+// ValidationErrors are translated from these paths, such as "address[city]".
+//
+// SetValidator must not be called once the Decoder is in use.
+func (d *Decoder) SetValidator(validate func(interface{}) error) {
+	d.validator = func(_ context.Context, v interface{}) error {
+		return validate(v)
+	}
+}
+
+// SetValidatorContext is like SetValidator, but the validation function is also
+// passed the context given to ParseContext (or context.Background(), for
+// Parse).
+func (d *Decoder) SetValidatorContext(validate func(context.Context, interface{}) error) {
+	d.validator = validate
+}
+
+// An error describing a single struct field.
+type structFieldError interface {
+	error
+	StructNamespace() string
+}
+
+// Translate an error returned by a Decoder's validation function into one of
+// our own, given the type of the struct being validated.
+func (d *Decoder) validationError(t reflect.Type, err error) error {
+	if fe, ok := err.(structFieldError); ok {
+		return ValidationErrors{d.fieldValidationError(t, fe)}
+	}
+
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Slice || v.Len() == 0 {
+		return ValidationError{Type: t, Err: err}
+	}
+	errs := make(ValidationErrors, v.Len())
+	for i := range errs {
+		fe, ok := v.Index(i).Interface().(structFieldError)
+		if !ok {
+			return ValidationError{Type: t, Err: err}
+		}
+		errs[i] = d.fieldValidationError(t, fe)
+	}
+	return errs
+}
+
+func (d *Decoder) fieldValidationError(t reflect.Type, fe structFieldError) ValidationError {
+	key, ft, ok := d.namespaceKey(t, fe.StructNamespace())
+	if !ok {
+		key, ft = fe.StructNamespace(), nil
+	}
+	return ValidationError{Key: key, Type: ft, Err: fe}
+}
+
+// Translate a path of Go field names such as "Form.Items[0].Name" into the key
+// of the corresponding parameter, such as "items[0][name]", also returning the
+// type of the field. The first component of the path names the struct type
+// itself, and is ignored.
+func (d *Decoder) namespaceKey(t reflect.Type, ns string) (string, reflect.Type, bool) {
+	parts := strings.Split(ns, ".")
+	if len(parts) < 2 {
+		return "", nil, false
+	}
+
+	key := ""
+	for _, part := range parts[1:] {
+		field, index := part, ""
+		if i := strings.IndexRune(part, '['); i >= 0 {
+			field, index = part[:i], part[i:]
+		}
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return "", nil, false
+		}
+		name, sf, ok := d.fieldByGoName(t, field)
+		if !ok {
+			return "", nil, false
+		}
+
+		if key == "" {
+			key = name
+		} else {
+			key += "[" + name + "]"
+		}
+		key += index
+		t = sf.Type
+		for i := strings.Count(index, "["); i > 0; i-- {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Map {
+				return "", nil, false
+			}
+			t = t.Elem()
+		}
+	}
+	return key, t, true
+}
+
+// Find the field of the given struct with the given Go name, returning its
+// parameter name.
+func (d *Decoder) fieldByGoName(t reflect.Type, goName string) (string, reflect.StructField, bool) {
+	for name, l := range d.cached(t) {
+		if sf := t.Field(l.offset); sf.Name == goName {
+			return name, sf, true
+		}
+	}
+	return "", reflect.StructField{}, false
+}
@@ -0,0 +1,245 @@
+package param
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type Range struct {
+	Min int
+	Max int
+}
+
+func (r Range) Validate() error {
+	if r.Min > r.Max {
+		return errors.New("min is greater than max")
+	}
+	return nil
+}
+
+type Positive int
+
+func (p *Positive) Validate() error {
+	if *p <= 0 {
+		return errors.New("not positive")
+	}
+	return nil
+}
+
+type Validated struct {
+	Range   Range
+	Ranges  map[string]*Range
+	Counts  []Positive
+	Exclude bool
+
+	calls int
+}
+
+func (v *Validated) Validate() error {
+	v.calls++
+	if v.Exclude && len(v.Counts) > 0 {
+		return errors.New("counts given with exclude")
+	}
+	return nil
+}
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	v := Validated{}
+	err := Parse(url.Values{
+		"Range[Min]":     {"1"},
+		"Range[Max]":     {"2"},
+		"Ranges[a][Max]": {"4"},
+		"Counts[]":       {"1", "2"},
+	}, &v)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "v.calls", 1, v.calls)
+}
+
+func TestValidatorErrors(t *testing.T) {
+	t.Parallel()
+
+	for key, params := range map[string]url.Values{
+		"Range":     {"Range[Min]": {"2"}},
+		"Ranges[a]": {"Ranges[a][Min]": {"2"}},
+		"Counts[1]": {"Counts[]": {"1", "0"}},
+		"":          {"Counts[]": {"1"}, "Exclude": {"true"}},
+	} {
+		v := Validated{}
+		err := Parse(params, &v)
+		if verr, ok := err.(ValidationError); !ok {
+			t.Errorf("Expected ValidationError for %q, got %v", key,
+				err)
+		} else {
+			assertEqual(t, "verr.Key", key, verr.Key)
+		}
+	}
+}
+
+func TestNotValidatable(t *testing.T) {
+	t.Parallel()
+
+	if isValidatable(reflect.TypeOf(Crazy{})) {
+		t.Error("Expected Crazy not to be validatable")
+	}
+}
+
+// Looks like the field errors of github.com/go-playground/validator
+type fieldError struct {
+	ns  string
+	tag string
+}
+
+func (f fieldError) Error() string {
+	return "field " + f.ns + " failed " + f.tag
+}
+
+func (f fieldError) StructNamespace() string {
+	return f.ns
+}
+
+type fieldErrors []fieldError
+
+func (f fieldErrors) Error() string {
+	return "validation failed"
+}
+
+type Library struct {
+	Name    string          `param:"name"`
+	Books   []Book          `param:"books"`
+	Shelves map[string]Book `param:"shelves"`
+}
+
+type Book struct {
+	Title string `param:"title"`
+}
+
+func TestSetValidator(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder()
+	d.SetValidator(func(v interface{}) error {
+		l := v.(*Library)
+		if l.Name == "" {
+			return fieldErrors{
+				{"Library.Name", "required"},
+				{"Library.Books[0].Title", "required"},
+				{"Library.Shelves[top].Title", "required"},
+				{"Library.Unknown", "required"},
+			}
+		}
+		if l.Name == "bad" {
+			return errors.New("bad library")
+		}
+		return nil
+	})
+
+	l := Library{}
+	err := d.Parse(url.Values{"name": {"good"}}, &l)
+	if err != nil {
+		t.Error("Parse error: ", err)
+	}
+
+	err = d.Parse(url.Values{"name": {"bad"}}, &l)
+	if verr, ok := err.(ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+	} else {
+		assertEqual(t, "verr.Key", "", verr.Key)
+	}
+
+	err = d.Parse(url.Values{"name": {""}}, &l)
+	verrs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	keys := make([]string, len(verrs))
+	for i, verr := range verrs {
+		keys[i] = verr.Key
+	}
+	assertEqual(t, "keys", []string{
+		"name", "books[0][title]", "shelves[top][title]",
+		"Library.Unknown",
+	}, keys)
+	assertEqual(t, "verrs[1].Type", reflect.TypeOf(""), verrs[1].Type)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	v := Validated{Exclude: true}
+	err := Validate(url.Values{"Counts[]": {"1"}}, &v)
+	if err != nil {
+		t.Fatal("Validate error: ", err)
+	}
+	assertEqual(t, "v", Validated{Exclude: true}, v)
+
+	err = Validate(url.Values{"Counts[]": {"0"}}, Validated{})
+	if verr, ok := err.(ValidationError); !ok {
+		t.Errorf("Expected ValidationError, got %v", err)
+	} else {
+		assertEqual(t, "verr.Key", "Counts[0]", verr.Key)
+	}
+
+	err = NewDecoder(WithMaxKeys(1)).Validate(url.Values{
+		"Counts[]": {"1"},
+		"Exclude":  {"true"},
+	}, &v)
+	if !errors.Is(err, ErrLimit) {
+		t.Errorf("Expected LimitError, got %v", err)
+	}
+}
+
+type Interval struct {
+	Start, End int
+}
+
+func (i Interval) Validate() error {
+	if i.Start >= i.End {
+		return errors.New("empty interval")
+	}
+	return nil
+}
+
+type Rota struct {
+	Name   string
+	Window Interval
+	Next   *Rota
+}
+
+func TestValidatorUntouched(t *testing.T) {
+	t.Parallel()
+
+	// Window was never given, so its zero value isn't validated.
+	err := Parse(url.Values{"Name": {"daily"}}, &Rota{})
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+
+	err = Parse(url.Values{"Window[Start]": {"0"}}, &Rota{})
+	if verr, ok := err.(ValidationError); !ok || verr.Key != "Window" {
+		t.Errorf("Expected ValidationError for Window, got %v", err)
+	}
+
+	// Values the target already had are validated too.
+	s := Rota{Window: Interval{Start: 2, End: 1}}
+	err = Parse(url.Values{"Name": {"daily"}}, &s)
+	if verr, ok := err.(ValidationError); !ok || verr.Key != "Window" {
+		t.Errorf("Expected ValidationError for Window, got %v", err)
+	}
+}
+
+func TestValidatorCycle(t *testing.T) {
+	t.Parallel()
+
+	s := &Rota{Window: Interval{Start: 1, End: 2}}
+	s.Next = s
+	err := Parse(url.Values{"Name": {"daily"}}, s)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+}
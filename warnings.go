@@ -0,0 +1,78 @@
+package param
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// A WarningKind describes what a Warning is about.
+type WarningKind int
+
+const (
+	// WarnUnknownKey reports a key that was ignored because it does not
+	// correspond to any field.
+	WarnUnknownKey WarningKind = iota
+	// WarnDuplicates reports values that were discarded because the key
+	// expects a single value (see WithDuplicates).
+	WarnDuplicates
+	// WarnTrimmed reports a value that had surrounding whitespace trimmed
+	// (see WithTrimSpace).
+	WarnTrimmed
+	// WarnEmptyZero reports an empty value that was parsed as zero (see
+	// WithEmptyAsZero).
+	WarnEmptyZero
+)
+
+// A Warning describes something a lenient Decoder let pass instead of
+// returning an error: a key it ignored, or a value it discarded or coerced.
+type Warning struct {
+	Kind WarningKind
+	// The key concerned.
+	Key string
+	// The values concerned: the values of an unknown key, the discarded
+	// values of a duplicated key, or the value as it was given before it
+	// was trimmed.
+	Values []string
+}
+
+func (w Warning) String() string {
+	switch w.Kind {
+	case WarnUnknownKey:
+		return fmt.Sprintf("ignored unknown key %q", w.Key)
+	case WarnDuplicates:
+		return fmt.Sprintf("discarded duplicate values %q of key %q",
+			w.Values, w.Key)
+	case WarnTrimmed:
+		return fmt.Sprintf("trimmed whitespace from value %q of key %q",
+			w.Values, w.Key)
+	case WarnEmptyZero:
+		return fmt.Sprintf("parsed empty value of key %q as zero", w.Key)
+	}
+	return fmt.Sprintf("warning %d for key %q", w.Kind, w.Key)
+}
+
+// ParseWithWarnings is like ParseWithReport, but describes everything it let
+// pass as a list of Warnings, in the order in which it came across them, so that
+// it can be logged and monitored: unknown keys, which it ignores, and values
+// that the Decoder's options allowed it to discard or coerce. Warnings are
+// returned even if parsing fails.
+func (d *Decoder) ParseWithWarnings(params url.Values, target interface{}) (warnings []Warning, err error) {
+	p := d.newParser(context.Background())
+	defer p.release()
+	p.unknownKeys = []string{}
+	p.warnings = []Warning{}
+	err = d.run(p, "param.Decoder.ParseWithWarnings", params, target)
+	return p.warnings, err
+}
+
+// Record a warning, if anyone is interested.
+func (p *parser) warn(kind WarningKind, key string, values ...string) {
+	if p.warnings != nil {
+		p.warnings = append(p.warnings, Warning{
+			Kind:   kind,
+			Key:    key,
+			Values: values,
+		})
+	}
+}
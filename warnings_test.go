@@ -0,0 +1,55 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseWithWarnings(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithDuplicates(DuplicatesLast), WithTrimSpace(),
+		WithEmptyAsZero())
+	e := Everything{}
+	warnings, err := d.ParseWithWarnings(url.Values{
+		"Int":    {"1", "2", "3"},
+		"Llama":  {"x"},
+		"String": {" a "},
+		"Uint":   {""},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "e.Int", 3, e.Int)
+	assertEqual(t, "e.String", "a", e.String)
+	assertEqual(t, "warnings", []Warning{
+		{Kind: WarnDuplicates, Key: "Int", Values: []string{"1", "2"}},
+		{Kind: WarnUnknownKey, Key: "Llama", Values: []string{"x"}},
+		{Kind: WarnTrimmed, Key: "String", Values: []string{" a "}},
+		{Kind: WarnEmptyZero, Key: "Uint"},
+	}, warnings)
+	assertEqual(t, "warnings[0]",
+		`discarded duplicate values ["1" "2"] of key "Int"`,
+		warnings[0].String())
+
+	// Warnings are returned along with errors.
+	warnings, err = d.ParseWithWarnings(url.Values{
+		"Float": {"1", "2"},
+		"Int":   {"llama"},
+	}, &e)
+	if _, ok := err.(TypeError); !ok {
+		t.Errorf("Expected TypeError, got %v", err)
+	}
+	assertEqual(t, "warnings", []Warning{
+		{Kind: WarnDuplicates, Key: "Float", Values: []string{"1"}},
+	}, warnings)
+
+	// Strict Decoders have nothing to warn about.
+	warnings, err = NewDecoder().ParseWithWarnings(url.Values{
+		"Int": {"1"},
+	}, &e)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "warnings", []Warning{}, warnings)
+}
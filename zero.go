@@ -0,0 +1,65 @@
+package param
+
+import "reflect"
+
+// WithZeroMissing returns an Option that resets every field of the target that
+// parameters may be parsed into to its zero value before parsing, so that a
+// struct reused from one request to the next, as from a sync.Pool, can't leak
+// values from a previous request into the next. Fields that are given no value
+// are left zeroed, or are parsed from their default, if they have one. Fields
+// excluded by a field filter, and unexported fields, are left untouched.
+//
+// Since fields are reset before anything is parsed into them, slices with the
+// "append" option are appended to an empty slice. Map targets are emptied.
+func WithZeroMissing() Option {
+	return func(d *Decoder) {
+		d.zeroMissing = true
+	}
+}
+
+// Reset the fields of the given struct, or the entries of the given map, as
+// WithZeroMissing describes.
+func (p *parser) zeroFields(el reflect.Value) {
+	t := el.Type()
+	if t.Kind() == reflect.Map {
+		el.Set(reflect.MakeMap(t))
+		return
+	}
+	for name, l := range p.cached(t) {
+		if p.filtered() && !p.fieldEnabled(name, t, name, l) {
+			continue
+		}
+		f := el.Field(l.offset)
+		f.Set(reflect.Zero(f.Type()))
+	}
+}
+
+// WithFillZero returns an Option that makes parameters only be parsed into
+// fields that are currently zero, as reflect.Value.IsZero has it, leaving
+// fields the target already has values for untouched. This suits layered
+// configuration, in which the caller fills in the target with values of its
+// own, and parameters are only used for whatever is left. Default values given
+// in struct tags are likewise only used for fields that are zero, and required
+// fields that already have a value need not be given one.
+//
+// Nested structs and maps are parsed into as usual, so that their own fields
+// and entries can be filled in; it's the fields within them that are left
+// untouched if they already have values.
+func WithFillZero() Option {
+	return func(d *Decoder) {
+		d.fillZero = true
+	}
+}
+
+// Reports whether the given struct field already has a value which WithFillZero
+// says must be left alone.
+func filled(f reflect.Value) bool {
+	t := f.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Map || t.Kind() == reflect.Struct && !isLeaf(t) {
+		return false
+	}
+	return !f.IsZero()
+}
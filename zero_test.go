@@ -0,0 +1,83 @@
+package param
+
+import (
+	"net/url"
+	"testing"
+)
+
+type Pooled struct {
+	Query  string   `param:"q"`
+	Limit  int      `param:"limit,default=25"`
+	Tags   []string `param:"tags,append"`
+	Sort   Sub      `param:"sort"`
+	hidden int
+}
+
+func TestZeroMissing(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithZeroMissing())
+	p := Pooled{
+		Query:  "old",
+		Limit:  10,
+		Tags:   []string{"a"},
+		Sort:   Sub{A: 1, B: 2},
+		hidden: 3,
+	}
+	err := d.Parse(url.Values{
+		"tags[]":  {"b"},
+		"sort[B]": {"4"},
+	}, &p)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "p", Pooled{
+		Limit:  25,
+		Tags:   []string{"b"},
+		Sort:   Sub{B: 4},
+		hidden: 3,
+	}, p)
+
+	m := map[string]int{"a": 1}
+	err = d.Parse(url.Values{"b": {"2"}}, &m)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "m", map[string]int{"b": 2}, m)
+}
+
+type Layered struct {
+	Host    string `param:"host"`
+	Port    int    `param:"port,default=80"`
+	Verbose bool   `param:"verbose"`
+	Token   string `param:"token,required"`
+	Sort    Sub    `param:"sort"`
+}
+
+func TestFillZero(t *testing.T) {
+	t.Parallel()
+
+	d := NewDecoder(WithFillZero())
+	l := Layered{Host: "example.com", Token: "secret", Sort: Sub{A: 1}}
+	err := d.Parse(url.Values{
+		"host":    {"evil.com"},
+		"verbose": {"true"},
+		"sort[A]": {"2"},
+		"sort[B]": {"3"},
+	}, &l)
+	if err != nil {
+		t.Fatal("Parse error: ", err)
+	}
+	assertEqual(t, "l", Layered{
+		Host:    "example.com",
+		Port:    80,
+		Verbose: true,
+		Token:   "secret",
+		Sort:    Sub{A: 1, B: 3},
+	}, l)
+
+	l = Layered{Port: 8080}
+	err = d.Parse(url.Values{"port": {"9090"}}, &l)
+	assertEqual(t, "err", RequiredError{Key: "token", Type: stringType}, err)
+	assertEqual(t, "l.Port", 8080, l.Port)
+}